@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/imran31415/spine"
 )
@@ -15,9 +16,44 @@ import (
 // Manager provides the high-level API for managing named spine graphs.
 // All methods are safe for concurrent use.
 type Manager struct {
-	mu     sync.Mutex
-	dir    string
-	graphs map[string]*spine.Graph[NodeData, EdgeData]
+	// CompactSave writes graphs without indentation, producing smaller files.
+	// Open reads both forms. Set it before the Manager is shared.
+	CompactSave bool
+
+	// UndoDepth bounds the snapshots kept per graph for Undo. Zero uses
+	// DefaultUndoDepth and a negative value disables undo. Set it before
+	// the Manager is shared.
+	UndoDepth int
+
+	// Journal enables a per-graph write-ahead journal: every logged mutation
+	// is also appended to <name>.journal in the graph directory, replayed
+	// when the graph is next opened, and discarded when the graph is saved.
+	// Undo and Redo instead rewrite the journal with the resulting state.
+	// Set it before the Manager is shared.
+	Journal bool
+
+	mu       sync.Mutex
+	dir      string
+	graphs   map[string]*spine.Graph[NodeData, EdgeData]
+	versions map[string]int // graph name -> mutation counter
+
+	readyHooks map[string][]func([]string) // graph name -> OnReady callbacks
+	policies   map[string]ReadinessPolicy  // graph name -> auto-ready policy
+	logPath    string                      // mutation log; empty disables logging
+
+	undo map[string][]*spine.Graph[NodeData, EdgeData] // graph name -> states before recent mutations
+	redo map[string][]*spine.Graph[NodeData, EdgeData] // graph name -> states undone since the last mutation
+
+	listIndex map[string]listEntry // graph name -> cached file summary for List
+	peeks     int                  // files parsed by List, for tests
+}
+
+// listEntry caches a persisted graph's summary along with the file state it
+// was read from, so List only re-reads files that changed.
+type listEntry struct {
+	info    GraphInfo
+	modTime time.Time
+	size    int64
 }
 
 // NewManager creates a Manager backed by the given directory.
@@ -27,19 +63,30 @@ func NewManager(dir string) (*Manager, error) {
 		return nil, fmt.Errorf("create graph dir: %w", err)
 	}
 	return &Manager{
-		dir:    dir,
-		graphs: make(map[string]*spine.Graph[NodeData, EdgeData]),
+		dir:        dir,
+		graphs:     make(map[string]*spine.Graph[NodeData, EdgeData]),
+		versions:   make(map[string]int),
+		readyHooks: make(map[string][]func([]string)),
+		policies:   make(map[string]ReadinessPolicy),
+		listIndex:  make(map[string]listEntry),
+		undo:       make(map[string][]*spine.Graph[NodeData, EdgeData]),
+		redo:       make(map[string][]*spine.Graph[NodeData, EdgeData]),
 	}, nil
 }
 
-func (m *Manager) graphPath(name string) string {
-	return filepath.Join(m.dir, name+".json")
+// graphPath returns the file the named graph is persisted in, or
+// ErrInvalidGraphName if name would resolve outside the graph directory.
+func (m *Manager) graphPath(name string) (string, error) {
+	if name == "" || name == "." || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidGraphName, name)
+	}
+	return filepath.Join(m.dir, name+".json"), nil
 }
 
 func (m *Manager) getGraph(name string) (*spine.Graph[NodeData, EdgeData], error) {
 	g, ok := m.graphs[name]
 	if !ok {
-		return nil, fmt.Errorf("graph %q not open", name)
+		return nil, fmt.Errorf("%w: %q", ErrGraphNotOpen, name)
 	}
 	return g, nil
 }
@@ -51,7 +98,9 @@ func (m *Manager) Open(name string) (*GraphInfo, error) {
 }
 
 // OpenWithDirected loads a graph from disk, or creates a new graph with the
-// specified directed mode if the file does not exist.
+// specified directed mode if the file does not exist. With Journal set, any
+// mutations journaled since the last Save are replayed before the graph
+// becomes visible to other calls.
 func (m *Manager) OpenWithDirected(name string, directed bool) (*GraphInfo, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -59,14 +108,30 @@ func (m *Manager) OpenWithDirected(name string, directed bool) (*GraphInfo, erro
 	if g, ok := m.graphs[name]; ok {
 		return m.graphInfo(name, g), nil
 	}
+	g, err := m.loadGraph(name, directed)
+	if err != nil {
+		return nil, err
+	}
+	if m.Journal {
+		if err := m.recoverJournalLocked(name, g); err != nil {
+			return nil, err
+		}
+	}
+	m.graphs[name] = g
+	return m.graphInfo(name, g), nil
+}
 
-	path := m.graphPath(name)
+// loadGraph reads the named graph from disk, or returns a new graph with the
+// specified directed mode if the file does not exist.
+func (m *Manager) loadGraph(name string, directed bool) (*spine.Graph[NodeData, EdgeData], error) {
+	path, err := m.graphPath(name)
+	if err != nil {
+		return nil, err
+	}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			g := spine.NewGraph[NodeData, EdgeData](directed)
-			m.graphs[name] = g
-			return m.graphInfo(name, g), nil
+			return spine.NewGraph[NodeData, EdgeData](directed), nil
 		}
 		return nil, fmt.Errorf("open %q: %w", name, err)
 	}
@@ -81,9 +146,7 @@ func (m *Manager) OpenWithDirected(name string, directed bool) (*GraphInfo, erro
 	// with matching keys but the generic N type in spine is decoded as a
 	// map[string]any when the JSON has object values.
 	FixupGraphData(g)
-
-	m.graphs[name] = g
-	return m.graphInfo(name, g), nil
+	return g, nil
 }
 
 // FixupGraphData re-parses node and edge data that json.Unmarshal may have
@@ -128,19 +191,81 @@ func (m *Manager) Save(name string) error {
 	if err != nil {
 		return err
 	}
+	return m.saveLocked(name, g)
+}
+
+// SaveIfVersion persists the named graph only if its current version equals
+// expected. It returns ErrVersionConflict without writing when the graph has
+// been mutated since the caller last observed it.
+func (m *Manager) SaveIfVersion(name string, expected int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(name)
+	if err != nil {
+		return err
+	}
+	if current := m.versions[name]; current != expected {
+		return fmt.Errorf("%w: graph %q expected version %d, current %d", ErrVersionConflict, name, expected, current)
+	}
+	return m.saveLocked(name, g)
+}
+
+func (m *Manager) saveLocked(name string, g *spine.Graph[NodeData, EdgeData]) error {
+	data, err := m.marshalLocked(name, g)
+	if err != nil {
+		return err
+	}
+	path, err := m.graphPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	return m.compactJournalLocked(name)
+}
+
+func (m *Manager) marshalLocked(name string, g *spine.Graph[NodeData, EdgeData]) ([]byte, error) {
 	data, err := spine.Marshal(g, &spine.MarshalOptions{
 		Graph:   true,
 		Meta:    true,
 		Schemas: true,
-		Indent:  true,
+		Indent:  !m.CompactSave,
 	})
 	if err != nil {
-		return fmt.Errorf("marshal %q: %w", name, err)
+		return nil, fmt.Errorf("marshal %q: %w", name, err)
+	}
+	return data, nil
+}
+
+// Snapshot returns the JSON snapshot of the named graph. An open graph is
+// marshalled from memory (including unsaved changes); otherwise the persisted
+// file is returned as is. Unlike Open, it never creates a graph.
+func (m *Manager) Snapshot(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if g, ok := m.graphs[name]; ok {
+		return m.marshalLocked(name, g)
+	}
+	path, err := m.graphPath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("graph %q not found", name)
+		}
+		return nil, fmt.Errorf("read %q: %w", name, err)
 	}
-	return os.WriteFile(m.graphPath(name), data, 0o644)
+	return data, nil
 }
 
-// List returns info for every persisted graph (files on disk).
+// List returns info for every persisted graph (files on disk). Summaries of
+// graphs that are not loaded come from an index refreshed by stat'ing each
+// file, so only new or modified files are re-read.
 func (m *Manager) List() ([]GraphInfo, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -151,11 +276,13 @@ func (m *Manager) List() ([]GraphInfo, error) {
 	}
 
 	var result []GraphInfo
+	seen := make(map[string]bool, len(entries))
 	for _, e := range entries {
 		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
 			continue
 		}
 		name := strings.TrimSuffix(e.Name(), ".json")
+		seen[name] = true
 
 		// If already loaded, use in-memory copy.
 		if g, ok := m.graphs[name]; ok {
@@ -163,44 +290,124 @@ func (m *Manager) List() ([]GraphInfo, error) {
 			continue
 		}
 
-		// Peek at the file to get basic info.
-		data, err := os.ReadFile(filepath.Join(m.dir, e.Name()))
+		fi, err := e.Info()
 		if err != nil {
 			continue
 		}
-		var peek struct {
-			Directed bool `json:"directed"`
-			Graph    *struct {
-				Nodes []json.RawMessage `json:"nodes"`
-				Edges []json.RawMessage `json:"edges"`
-			} `json:"graph"`
-		}
-		if json.Unmarshal(data, &peek) != nil {
+		if cached, ok := m.listIndex[name]; ok && cached.modTime.Equal(fi.ModTime()) && cached.size == fi.Size() {
+			result = append(result, cached.info)
 			continue
 		}
-		info := GraphInfo{Name: name, Directed: peek.Directed}
-		if peek.Graph != nil {
-			info.NodeCount = len(peek.Graph.Nodes)
-			info.EdgeCount = len(peek.Graph.Edges)
+
+		info, err := m.peekGraphFile(name, filepath.Join(m.dir, e.Name()))
+		if err != nil {
+			delete(m.listIndex, name)
+			continue
 		}
+		m.listIndex[name] = listEntry{info: info, modTime: fi.ModTime(), size: fi.Size()}
 		result = append(result, info)
 	}
+
+	for name := range m.listIndex {
+		if !seen[name] {
+			delete(m.listIndex, name)
+		}
+	}
 	return result, nil
 }
 
+// peekGraphFile reads just enough of a snapshot file to summarise it.
+func (m *Manager) peekGraphFile(name, path string) (GraphInfo, error) {
+	m.peeks++
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return GraphInfo{}, err
+	}
+	var peek struct {
+		Directed bool `json:"directed"`
+		Graph    *struct {
+			Nodes []json.RawMessage `json:"nodes"`
+			Edges []json.RawMessage `json:"edges"`
+		} `json:"graph"`
+	}
+	if err := json.Unmarshal(data, &peek); err != nil {
+		return GraphInfo{}, err
+	}
+	info := GraphInfo{Name: name, Directed: peek.Directed}
+	if peek.Graph != nil {
+		info.NodeCount = len(peek.Graph.Nodes)
+		info.EdgeCount = len(peek.Graph.Edges)
+	}
+	return info, nil
+}
+
 // Delete removes a graph from disk and from the in-memory cache.
 func (m *Manager) Delete(name string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	path, err := m.graphPath(name)
+	if err != nil {
+		return err
+	}
 	delete(m.graphs, name)
-	path := m.graphPath(name)
+	delete(m.versions, name)
+	delete(m.listIndex, name)
+	delete(m.undo, name)
+	delete(m.redo, name)
 	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("delete %q: %w", name, err)
 	}
+	return m.compactJournalLocked(name)
+}
+
+// SetConfig sets graph-level configuration values (e.g. a default status or
+// display options) on the named graph. They are persisted with the graph on
+// Save. A nil value deletes the key.
+func (m *Manager) SetConfig(name string, values map[string]any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(name)
+	if err != nil {
+		return err
+	}
+	undo, rollback := m.rollbackSnapshot(g)
+	mark := m.markLocked(name)
+	m.pushUndoLocked(name, undo)
+	store := g.GraphMeta()
+	for k, v := range values {
+		if v == nil {
+			store.Delete(k)
+			continue
+		}
+		store.Set(k, v)
+	}
+	m.bumpVersion(name)
+	if err := m.logLocked(opSetConfig, name, setConfigRequest{Values: values}); err != nil {
+		m.abortLocked(name, g, rollback, mark)
+		return err
+	}
 	return nil
 }
 
+// Config returns a copy of the named graph's configuration values.
+func (m *Manager) Config(name string) (map[string]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(name)
+	if err != nil {
+		return nil, err
+	}
+	cfg := make(map[string]any)
+	g.GraphMeta().Range(func(k string, v any) bool {
+		cfg[k] = v
+		return true
+	})
+	return cfg, nil
+}
+
 // Summary returns structural statistics for the named graph.
 func (m *Manager) Summary(name string) (*GraphSummary, error) {
 	m.mu.Lock()
@@ -255,6 +462,76 @@ func (m *Manager) Remove(req RemoveRequest) (*RemoveResult, error) {
 		return nil, err
 	}
 
+	undo, rollback := m.rollbackSnapshot(g)
+	mark := m.markLocked(req.Graph)
+	m.pushUndoLocked(req.Graph, undo)
+	res := applyRemove(g, req)
+	m.bumpVersion(req.Graph)
+	if err := m.logLocked(opRemove, req.Graph, req); err != nil {
+		m.abortLocked(req.Graph, g, rollback, mark)
+		return nil, err
+	}
+	return res, nil
+}
+
+// SimulateRemove previews a Remove: it applies req to a copy of the graph and
+// reports the nodes that would no longer be reachable from any root, the
+// nodes that would lose all of their dependencies, and the root-to-node paths
+// that would be broken. Roots are the nodes without in-edges before the
+// removal. The real graph is not modified.
+func (m *Manager) SimulateRemove(req RemoveRequest) (*SimulationResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(req.Graph)
+	if err != nil {
+		return nil, err
+	}
+
+	sim := g.Copy()
+	res := &SimulationResult{
+		RemoveResult: *applyRemove(sim, req),
+		Unreachable:  make([]string, 0),
+		Orphaned:     make([]string, 0),
+		BrokenPaths:  make([]EdgeKey, 0),
+	}
+
+	var roots []string
+	for _, n := range spine.Roots(g) {
+		roots = append(roots, n.ID)
+	}
+	reachedBefore := make(map[string]bool)
+	reachedAfter := make(map[string]bool)
+	for _, r := range roots {
+		after := make(map[string]bool)
+		if sim.HasNode(r) {
+			for _, id := range spine.Descendants(sim, r) {
+				after[id] = true
+				reachedAfter[id] = true
+			}
+		}
+		for _, id := range spine.Descendants(g, r) {
+			reachedBefore[id] = true
+			if sim.HasNode(r) && sim.HasNode(id) && !after[id] {
+				res.BrokenPaths = append(res.BrokenPaths, EdgeKey{From: r, To: id})
+			}
+		}
+	}
+
+	for _, n := range sim.Nodes() {
+		if reachedBefore[n.ID] && !reachedAfter[n.ID] {
+			res.Unreachable = append(res.Unreachable, n.ID)
+		}
+		if len(g.InEdges(n.ID)) > 0 && len(sim.InEdges(n.ID)) == 0 {
+			res.Orphaned = append(res.Orphaned, n.ID)
+		}
+	}
+	sortEdgeKeys(res.BrokenPaths)
+	return res, nil
+}
+
+// applyRemove deletes the nodes and edges named by req from g.
+func applyRemove(g *spine.Graph[NodeData, EdgeData], req RemoveRequest) *RemoveResult {
 	res := &RemoveResult{}
 	for _, id := range req.Nodes {
 		if g.HasNode(id) {
@@ -268,7 +545,7 @@ func (m *Manager) Remove(req RemoveRequest) (*RemoveResult, error) {
 			res.EdgesRemoved++
 		}
 	}
-	return res, nil
+	return res
 }
 
 func (m *Manager) graphInfo(name string, g *spine.Graph[NodeData, EdgeData]) *GraphInfo {
@@ -277,9 +554,15 @@ func (m *Manager) graphInfo(name string, g *spine.Graph[NodeData, EdgeData]) *Gr
 		NodeCount: g.Order(),
 		EdgeCount: g.Size(),
 		Directed:  g.Directed,
+		Version:   m.versions[name],
 	}
 }
 
+// bumpVersion records a mutation of the named graph. Caller must hold m.mu.
+func (m *Manager) bumpVersion(name string) {
+	m.versions[name]++
+}
+
 // sortedNodeIDs returns sorted node IDs from a graph.
 func sortedNodeIDs(g *spine.Graph[NodeData, EdgeData]) []string {
 	nodes := g.Nodes()
@@ -1,9 +1,12 @@
 package api
 
 import (
+	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func tempDir(t *testing.T) string {
@@ -85,6 +88,84 @@ func TestSaveAndReload(t *testing.T) {
 	}
 }
 
+func TestCompactSave(t *testing.T) {
+	dir := tempDir(t)
+	req := func(graph string) UpsertRequest {
+		return UpsertRequest{
+			Graph: graph,
+			Nodes: []UpsertNode{
+				{ID: "a", Label: "Alpha", Status: "pending", Meta: map[string]any{"owner": "x"}},
+				{ID: "b", Label: "Beta", Status: "ready"},
+			},
+			Edges: []UpsertEdge{{From: "a", To: "b", Label: "dep"}},
+		}
+	}
+
+	pretty, _ := NewManager(dir)
+	pretty.Open("pretty")
+	pretty.Upsert(req("pretty"))
+	if err := pretty.Save("pretty"); err != nil {
+		t.Fatal(err)
+	}
+
+	compact, _ := NewManager(dir)
+	compact.CompactSave = true
+	compact.Open("compact")
+	compact.Upsert(req("compact"))
+	if err := compact.Save("compact"); err != nil {
+		t.Fatal(err)
+	}
+
+	compactData, _ := os.ReadFile(filepath.Join(dir, "compact.json"))
+	if bytes.Contains(compactData, []byte("\n")) {
+		t.Error("expected compact file without newlines")
+	}
+	prettyData, _ := os.ReadFile(filepath.Join(dir, "pretty.json"))
+	if !bytes.Contains(prettyData, []byte("\n")) {
+		t.Error("expected pretty file with newlines")
+	}
+
+	fresh, _ := NewManager(dir)
+	fresh.Open("pretty")
+	fresh.Open("compact")
+	a, _ := fresh.Snapshot("pretty")
+	b, _ := fresh.Snapshot("compact")
+	if !bytes.Equal(a, b) {
+		t.Errorf("expected equal graphs after reload:\n%s\n%s", a, b)
+	}
+}
+
+func TestConfigPersists(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("plan")
+	if err := mgr.SetConfig("plan", map[string]any{"default_status": "pending", "task_plan": true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.Save("plan"); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh, _ := NewManager(dir)
+	fresh.Open("plan")
+	cfg, err := fresh.Config("plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg["default_status"] != "pending" || cfg["task_plan"] != true {
+		t.Errorf("expected config to persist, got %v", cfg)
+	}
+
+	fresh.SetConfig("plan", map[string]any{"task_plan": nil})
+	cfg, _ = fresh.Config("plan")
+	if _, ok := cfg["task_plan"]; ok {
+		t.Error("expected nil value to delete the key")
+	}
+	if _, err := fresh.Config("nope"); err == nil {
+		t.Error("expected error for non-open graph")
+	}
+}
+
 func TestList(t *testing.T) {
 	dir := tempDir(t)
 	mgr, _ := NewManager(dir)
@@ -102,6 +183,56 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestListIndex(t *testing.T) {
+	dir := tempDir(t)
+	writer, _ := NewManager(dir)
+	writer.Open("aaa")
+	writer.Upsert(UpsertRequest{Graph: "aaa", Nodes: []UpsertNode{{ID: "x"}}})
+	writer.Save("aaa")
+	writer.Open("bbb")
+	writer.Save("bbb")
+
+	mgr, _ := NewManager(dir)
+	list, err := mgr.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || mgr.peeks != 2 {
+		t.Fatalf("expected 2 graphs read once each, got %d graphs and %d reads", len(list), mgr.peeks)
+	}
+
+	// Unchanged files are served from the index.
+	list, _ = mgr.List()
+	if mgr.peeks != 2 {
+		t.Errorf("expected no re-reads for unchanged files, got %d reads", mgr.peeks)
+	}
+	if list[0].NodeCount != 1 {
+		t.Errorf("expected cached node count 1, got %d", list[0].NodeCount)
+	}
+
+	// A modified file is re-read.
+	writer.Upsert(UpsertRequest{Graph: "aaa", Nodes: []UpsertNode{{ID: "y"}}})
+	writer.Save("aaa")
+	future := time.Now().Add(time.Hour)
+	os.Chtimes(filepath.Join(dir, "aaa.json"), future, future)
+	list, _ = mgr.List()
+	if mgr.peeks != 3 {
+		t.Errorf("expected only the modified file re-read, got %d reads", mgr.peeks)
+	}
+	if list[0].NodeCount != 2 {
+		t.Errorf("expected refreshed node count 2, got %d", list[0].NodeCount)
+	}
+
+	// Removed files drop out of the index.
+	os.Remove(filepath.Join(dir, "bbb.json"))
+	if list, _ = mgr.List(); len(list) != 1 {
+		t.Errorf("expected 1 graph after removal, got %d", len(list))
+	}
+	if _, ok := mgr.listIndex["bbb"]; ok {
+		t.Error("expected bbb pruned from index")
+	}
+}
+
 func TestDelete(t *testing.T) {
 	dir := tempDir(t)
 	mgr, _ := NewManager(dir)
@@ -182,6 +313,43 @@ func TestRemove(t *testing.T) {
 	}
 }
 
+func TestSimulateRemove(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("sim")
+	// Two clusters joined by the bridge b -> c.
+	mgr.Upsert(UpsertRequest{
+		Graph: "sim",
+		Nodes: []UpsertNode{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}},
+		Edges: []UpsertEdge{{From: "a", To: "b"}, {From: "b", To: "c"}, {From: "c", To: "d"}},
+	})
+
+	res, err := mgr.SimulateRemove(RemoveRequest{
+		Graph: "sim",
+		Edges: []RemoveEdge{{From: "b", To: "c"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.EdgesRemoved != 1 {
+		t.Errorf("expected 1 edge removed, got %d", res.EdgesRemoved)
+	}
+	if len(res.Unreachable) != 2 || res.Unreachable[0] != "c" || res.Unreachable[1] != "d" {
+		t.Errorf("expected [c d] unreachable, got %v", res.Unreachable)
+	}
+	if len(res.Orphaned) != 1 || res.Orphaned[0] != "c" {
+		t.Errorf("expected [c] orphaned, got %v", res.Orphaned)
+	}
+	if len(res.BrokenPaths) != 2 || res.BrokenPaths[0] != (EdgeKey{From: "a", To: "c"}) {
+		t.Errorf("expected a->c and a->d broken, got %v", res.BrokenPaths)
+	}
+
+	g, _ := mgr.OpenGraph("sim")
+	if !g.HasEdge("b", "c") || g.Size() != 3 {
+		t.Error("simulation must not modify the real graph")
+	}
+}
+
 func TestSaveNotOpen(t *testing.T) {
 	dir := tempDir(t)
 	mgr, _ := NewManager(dir)
@@ -189,3 +357,46 @@ func TestSaveNotOpen(t *testing.T) {
 		t.Error("expected error saving non-open graph")
 	}
 }
+
+func TestVersionBumpsOnMutation(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	info, _ := mgr.Open("v")
+	if info.Version != 0 {
+		t.Fatalf("expected version 0 on open, got %d", info.Version)
+	}
+
+	mgr.Upsert(UpsertRequest{Graph: "v", Nodes: []UpsertNode{{ID: "a", Status: "pending"}}})
+	mgr.Transition(TransitionRequest{Graph: "v", ID: "a", Status: "ready"})
+	mgr.Remove(RemoveRequest{Graph: "v", Nodes: []string{"a"}})
+
+	info, _ = mgr.Open("v")
+	if info.Version != 3 {
+		t.Errorf("expected version 3 after three mutations, got %d", info.Version)
+	}
+}
+
+func TestSaveIfVersion(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	info, _ := mgr.Open("v")
+	seen := info.Version
+
+	// Another agent mutates the graph after we observed it.
+	mgr.Upsert(UpsertRequest{Graph: "v", Nodes: []UpsertNode{{ID: "a"}}})
+
+	if err := mgr.SaveIfVersion("v", seen); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected stale save to be rejected with ErrVersionConflict, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "v.json")); !os.IsNotExist(err) {
+		t.Error("expected no file to be written for a stale save")
+	}
+
+	info, _ = mgr.Open("v")
+	if err := mgr.SaveIfVersion("v", info.Version); err != nil {
+		t.Fatalf("expected save at current version to succeed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "v.json")); err != nil {
+		t.Error("expected file on disk after successful save")
+	}
+}
@@ -0,0 +1,328 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/imran31415/spine"
+)
+
+// GraphPatch is the difference between two snapshots of a graph, as produced
+// by SaveDelta. Nodes and Edges hold records that were added or changed;
+// NodeMeta and EdgeMeta replace the whole metadata store (entries and schema)
+// of each node or edge whose metadata changed, with empty entries clearing
+// it. Config, when non-nil, replaces the graph config. Removing a node also
+// removes its edges.
+type GraphPatch struct {
+	Version      int                               `json:"version"`
+	Nodes        []spine.NodeData[json.RawMessage] `json:"nodes,omitempty"`
+	RemovedNodes []string                          `json:"removed_nodes,omitempty"`
+	Edges        []spine.EdgeData[json.RawMessage] `json:"edges,omitempty"`
+	RemovedEdges []EdgeKey                         `json:"removed_edges,omitempty"`
+	NodeMeta     []spine.NodeMetaData              `json:"node_meta,omitempty"`
+	EdgeMeta     []spine.EdgeMetaData              `json:"edge_meta,omitempty"`
+	Config       map[string]any                    `json:"config"`
+
+	// DirectedEdgeMeta holds the per-direction stores of undirected edges.
+	DirectedEdgeMeta []spine.EdgeMetaData `json:"directed_edge_meta,omitempty"`
+}
+
+// SaveDelta returns a JSON-encoded GraphPatch that turns the snapshot base
+// (as written by Save or returned by Snapshot) into the current state of the
+// named graph. Only changed records are included, so for a large graph with
+// a small edit the delta is much smaller than a full snapshot. Rebuild the
+// graph with ApplyDelta.
+func (m *Manager) SaveDelta(name string, base []byte) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := m.marshalLocked(name, g)
+	if err != nil {
+		return nil, err
+	}
+	var from, to rawSnapshot
+	if err := json.Unmarshal(base, &from); err != nil {
+		return nil, fmt.Errorf("delta %q: base: %w", name, err)
+	}
+	if err := json.Unmarshal(data, &to); err != nil {
+		return nil, fmt.Errorf("delta %q: %w", name, err)
+	}
+	if from.Directed != to.Directed {
+		return nil, fmt.Errorf("delta %q: base directedness differs", name)
+	}
+	return json.Marshal(diffSnapshots(&from, &to))
+}
+
+// ApplyDelta rebuilds a graph from a base snapshot and a delta produced by
+// SaveDelta against that base, and installs it as the named graph. An open
+// graph is replaced in place (and the replacement can be undone); otherwise
+// the graph is opened in memory. Nothing is written to disk until Save.
+func (m *Manager) ApplyDelta(name string, base, delta []byte) (*GraphInfo, error) {
+	g, err := spine.Unmarshal[NodeData, EdgeData](base)
+	if err != nil {
+		return nil, fmt.Errorf("apply delta %q: base: %w", name, err)
+	}
+	FixupGraphData(g)
+	var p GraphPatch
+	if err := json.Unmarshal(delta, &p); err != nil {
+		return nil, fmt.Errorf("apply delta %q: %w", name, err)
+	}
+	if err := applyPatch(g, &p); err != nil {
+		return nil, fmt.Errorf("apply delta %q: %w", name, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mark := m.markLocked(name)
+	cur, open := m.graphs[name]
+	var rollback *spine.Graph[NodeData, EdgeData]
+	if open {
+		var undo *spine.Graph[NodeData, EdgeData]
+		undo, rollback = m.rollbackSnapshot(cur)
+		m.pushUndoLocked(name, undo)
+		cur.Restore(g)
+		g = cur
+	} else {
+		m.graphs[name] = g
+	}
+	m.bumpVersion(name)
+	if err := m.logLocked(opApplyDelta, name, applyDeltaRequest{Base: base, Delta: delta}); err != nil {
+		if open {
+			m.abortLocked(name, g, rollback, mark)
+		} else {
+			delete(m.graphs, name)
+			m.versions[name] = mark.version
+		}
+		return nil, err
+	}
+	return m.graphInfo(name, g), nil
+}
+
+// diffSnapshots computes the patch from one snapshot to another.
+func diffSnapshots(from, to *rawSnapshot) *GraphPatch {
+	p := &GraphPatch{Version: 1}
+
+	fromNodes, toNodes := snapshotNodes(from), snapshotNodes(to)
+	for _, id := range sortedKeys(toNodes) {
+		if old, ok := fromNodes[id]; !ok || !sameJSON(old.Data, toNodes[id].Data) {
+			p.Nodes = append(p.Nodes, toNodes[id])
+		}
+	}
+	for _, id := range sortedKeys(fromNodes) {
+		if _, ok := toNodes[id]; !ok {
+			p.RemovedNodes = append(p.RemovedNodes, id)
+		}
+	}
+
+	fromEdges, toEdges := snapshotEdges(from), snapshotEdges(to)
+	for _, k := range sortedEdgeKeys(toEdges) {
+		e := toEdges[k]
+		if old, ok := fromEdges[k]; !ok || old.Weight != e.Weight || !sameJSON(old.Data, e.Data) {
+			p.Edges = append(p.Edges, e)
+		}
+	}
+	for _, k := range sortedEdgeKeys(fromEdges) {
+		_, kept := toEdges[k]
+		_, hasFrom := toNodes[k.From]
+		_, hasTo := toNodes[k.To]
+		// Edges of removed nodes go with them.
+		if !kept && hasFrom && hasTo {
+			p.RemovedEdges = append(p.RemovedEdges, k)
+		}
+	}
+
+	fromNodeMeta, toNodeMeta := snapshotNodeMeta(from), snapshotNodeMeta(to)
+	for _, id := range sortedKeys(toNodes) {
+		old, cur := fromNodeMeta[id], toNodeMeta[id]
+		if !sameMeta(old.Entries, old.Schema, cur.Entries, cur.Schema) {
+			p.NodeMeta = append(p.NodeMeta, spine.NodeMetaData{ID: id, Entries: cur.Entries, Schema: cur.Schema})
+		}
+	}
+	fromEdgeMeta, toEdgeMeta := snapshotEdgeMeta(from, false), snapshotEdgeMeta(to, false)
+	for _, k := range sortedEdgeKeys(toEdges) {
+		old, cur := fromEdgeMeta[k], toEdgeMeta[k]
+		if !sameMeta(old.Entries, old.Schema, cur.Entries, cur.Schema) {
+			p.EdgeMeta = append(p.EdgeMeta, spine.EdgeMetaData{From: k.From, To: k.To, Entries: cur.Entries, Schema: cur.Schema})
+		}
+	}
+
+	fromDirMeta, toDirMeta := snapshotEdgeMeta(from, true), snapshotEdgeMeta(to, true)
+	union := make(map[EdgeKey]bool)
+	for k := range fromDirMeta {
+		union[k] = true
+	}
+	for k := range toDirMeta {
+		union[k] = true
+	}
+	for _, k := range sortedEdgeKeys(union) {
+		_, fwd := toEdges[k]
+		_, rev := toEdges[EdgeKey{From: k.To, To: k.From}]
+		old, cur := fromDirMeta[k], toDirMeta[k]
+		if (fwd || rev) && !sameMeta(old.Entries, old.Schema, cur.Entries, cur.Schema) {
+			p.DirectedEdgeMeta = append(p.DirectedEdgeMeta, spine.EdgeMetaData{From: k.From, To: k.To, Entries: cur.Entries, Schema: cur.Schema})
+		}
+	}
+
+	if canonicalJSON(from.Config) != canonicalJSON(to.Config) {
+		p.Config = to.Config
+		if p.Config == nil {
+			p.Config = make(map[string]any)
+		}
+	}
+	return p
+}
+
+// applyPatch applies p to g in place.
+func applyPatch(g *spine.Graph[NodeData, EdgeData], p *GraphPatch) error {
+	if p.Version != 1 {
+		return fmt.Errorf("unsupported delta version: %d", p.Version)
+	}
+	for _, id := range p.RemovedNodes {
+		g.RemoveNode(id)
+	}
+	for _, e := range p.RemovedEdges {
+		g.RemoveEdge(e.From, e.To)
+	}
+	for _, n := range p.Nodes {
+		var nd NodeData
+		if err := json.Unmarshal(n.Data, &nd); err != nil {
+			return fmt.Errorf("node %q: %w", n.ID, err)
+		}
+		g.AddNode(n.ID, nd)
+	}
+	for _, e := range p.Edges {
+		var ed EdgeData
+		if err := json.Unmarshal(e.Data, &ed); err != nil {
+			return fmt.Errorf("edge %s->%s: %w", e.From, e.To, err)
+		}
+		if err := g.AddEdge(e.From, e.To, ed, e.Weight); err != nil {
+			return fmt.Errorf("edge %s->%s: %w", e.From, e.To, err)
+		}
+	}
+	for _, nm := range p.NodeMeta {
+		if store := g.NodeMeta(nm.ID); store != nil {
+			replaceStore(store, nm.Entries, nm.Schema)
+		}
+	}
+	for _, em := range p.EdgeMeta {
+		if store := g.EdgeMeta(em.From, em.To); store != nil {
+			replaceStore(store, em.Entries, em.Schema)
+		}
+	}
+	for _, em := range p.DirectedEdgeMeta {
+		if store := g.DirectedEdgeMeta(em.From, em.To); store != nil {
+			replaceStore(store, em.Entries, em.Schema)
+		}
+	}
+	if p.Config != nil {
+		replaceStore(g.GraphMeta(), p.Config, nil)
+	}
+	return nil
+}
+
+func replaceStore(store *spine.Store, entries map[string]any, schema spine.Schema) {
+	store.Clear()
+	for k, v := range entries {
+		store.Set(k, v)
+	}
+	store.SetSchema(schema)
+	if schema != nil {
+		store.CoerceToSchema()
+	}
+}
+
+func snapshotNodes(s *rawSnapshot) map[string]spine.NodeData[json.RawMessage] {
+	out := make(map[string]spine.NodeData[json.RawMessage])
+	if s.Graph != nil {
+		for _, n := range s.Graph.Nodes {
+			out[n.ID] = n
+		}
+	}
+	return out
+}
+
+func snapshotEdges(s *rawSnapshot) map[EdgeKey]spine.EdgeData[json.RawMessage] {
+	out := make(map[EdgeKey]spine.EdgeData[json.RawMessage])
+	if s.Graph != nil {
+		for _, e := range s.Graph.Edges {
+			out[EdgeKey{From: e.From, To: e.To}] = e
+		}
+	}
+	return out
+}
+
+func snapshotNodeMeta(s *rawSnapshot) map[string]spine.NodeMetaData {
+	out := make(map[string]spine.NodeMetaData)
+	if s.Meta != nil {
+		for _, nm := range s.Meta.Nodes {
+			out[nm.ID] = nm
+		}
+	}
+	return out
+}
+
+// snapshotEdgeMeta indexes a snapshot's edge metadata, or its per-direction
+// edge metadata if directed is set.
+func snapshotEdgeMeta(s *rawSnapshot, directed bool) map[EdgeKey]spine.EdgeMetaData {
+	out := make(map[EdgeKey]spine.EdgeMetaData)
+	if s.Meta != nil {
+		list := s.Meta.Edges
+		if directed {
+			list = s.Meta.DirectedEdges
+		}
+		for _, em := range list {
+			out[EdgeKey{From: em.From, To: em.To}] = em
+		}
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedEdgeKeys[V any](m map[EdgeKey]V) []EdgeKey {
+	keys := make([]EdgeKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sortEdgeKeys(keys)
+	return keys
+}
+
+// sameJSON reports whether two raw JSON values are equal ignoring whitespace.
+func sameJSON(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// sameMeta reports whether two metadata stores hold the same entries and
+// schema. A missing store equals an empty one.
+func sameMeta(entriesA map[string]any, schemaA spine.Schema, entriesB map[string]any, schemaB spine.Schema) bool {
+	return canonicalJSON(entriesA) == canonicalJSON(entriesB) && canonicalJSON(schemaA) == canonicalJSON(schemaB)
+}
+
+// canonicalJSON encodes m with sorted keys, for comparing decoded values. An
+// empty or nil map encodes as "".
+func canonicalJSON[M ~map[string]V, V any](m M) string {
+	if len(m) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(m)
+	return string(data)
+}
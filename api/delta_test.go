@@ -0,0 +1,114 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveDeltaApplyDelta(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("big")
+	req := UpsertRequest{Graph: "big"}
+	for i := 0; i < 200; i++ {
+		id := fmt.Sprintf("n%03d", i)
+		req.Nodes = append(req.Nodes, UpsertNode{ID: id, Label: "Task " + id, Status: "pending", Meta: map[string]any{"index": i}})
+		if i > 0 {
+			req.Edges = append(req.Edges, UpsertEdge{From: fmt.Sprintf("n%03d", i-1), To: id})
+		}
+	}
+	mgr.Upsert(req)
+	mgr.SetConfig("big", map[string]any{"owner": "ops"})
+	base, err := mgr.Snapshot("big")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A small edit: one status change, one metadata change, one new node and
+	// edge, one removed node.
+	mgr.Upsert(UpsertRequest{
+		Graph: "big",
+		Nodes: []UpsertNode{
+			{ID: "n010", Status: "ready"},
+			{ID: "n020", Meta: map[string]any{"note": "flaky"}},
+			{ID: "extra", Label: "Extra"},
+		},
+		Edges: []UpsertEdge{{From: "n199", To: "extra"}},
+	})
+	mgr.Remove(RemoveRequest{Graph: "big", Nodes: []string{"n150"}})
+
+	delta, err := mgr.SaveDelta("big", base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delta) >= len(base)/10 {
+		t.Errorf("expected a small delta, got %d bytes for a %d byte base", len(delta), len(base))
+	}
+	var p GraphPatch
+	json.Unmarshal(delta, &p)
+	if len(p.Nodes) != 2 || len(p.RemovedNodes) != 1 || len(p.Edges) != 1 || len(p.NodeMeta) != 1 || p.Config != nil {
+		t.Errorf("unexpected patch: %+v", p)
+	}
+
+	want, _ := mgr.Snapshot("big")
+	restored, _ := NewManager(filepath.Join(dir, "restored"))
+	if _, err := restored.ApplyDelta("big", base, delta); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := restored.Snapshot("big")
+	if !bytes.Equal(got, want) {
+		t.Fatalf("reconstructed snapshot differs:\n%s", got)
+	}
+	g, _ := restored.OpenGraph("big")
+	if v, _ := g.NodeMeta("n005").Get("index"); v != float64(5) {
+		t.Errorf("expected untouched metadata to survive, got %v", v)
+	}
+
+	// An empty delta reproduces the base.
+	fresh, _ := NewManager(filepath.Join(dir, "fresh"))
+	fresh.ApplyDelta("base", base, []byte(`{"version":1,"config":null}`))
+	if got, _ := fresh.Snapshot("base"); !bytes.Equal(got, base) {
+		t.Error("expected an empty delta to reproduce the base")
+	}
+
+	if _, err := mgr.SaveDelta("missing", base); err == nil {
+		t.Error("expected error for unknown graph")
+	}
+	if _, err := restored.ApplyDelta("big", base, []byte(`{"version":2}`)); err == nil {
+		t.Error("expected error for unsupported delta version")
+	}
+}
+
+func TestDeltaMetadataRemoval(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("m")
+	mgr.Upsert(UpsertRequest{
+		Graph: "m",
+		Nodes: []UpsertNode{{ID: "a", Meta: map[string]any{"k": "v"}}, {ID: "b"}},
+		Edges: []UpsertEdge{{From: "a", To: "b", Meta: map[string]any{"latency": 3}}},
+	})
+	mgr.SetConfig("m", map[string]any{"mode": "x"})
+	base, _ := mgr.Snapshot("m")
+
+	mgr.DeleteMetaBulk("m", []string{"a"}, []string{"k"})
+	mgr.Remove(RemoveRequest{Graph: "m", Edges: []RemoveEdge{{From: "a", To: "b"}}})
+	mgr.SetConfig("m", map[string]any{"mode": nil})
+
+	delta, err := mgr.SaveDelta("m", base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, _ := NewManager(filepath.Join(dir, "restored"))
+	restored.ApplyDelta("m", base, delta)
+	g, _ := restored.OpenGraph("m")
+	if g.NodeMetaCount("a") != 0 || g.HasEdge("a", "b") {
+		t.Errorf("expected metadata and edge removed, got meta=%d edge=%v", g.NodeMetaCount("a"), g.HasEdge("a", "b"))
+	}
+	if v, ok := g.GraphMeta().Get("mode"); ok {
+		t.Errorf("expected config cleared, got %v", v)
+	}
+}
@@ -0,0 +1,34 @@
+package api
+
+import "errors"
+
+// Sentinel errors returned (wrapped with context) by Manager methods.
+// Use errors.Is to test for them.
+var (
+	// ErrGraphNotOpen is returned when an operation names a graph that has not
+	// been opened.
+	ErrGraphNotOpen = errors.New("graph not open")
+
+	// ErrNodeNotFound is returned when an operation names a node that does not
+	// exist in the graph.
+	ErrNodeNotFound = errors.New("node not found")
+
+	// ErrInvalidTransition is returned when a status change is not allowed by
+	// the transition rules.
+	ErrInvalidTransition = errors.New("invalid transition")
+
+	// ErrNothingToUndo is returned by Undo when no earlier state is recorded.
+	ErrNothingToUndo = errors.New("nothing to undo")
+
+	// ErrNothingToRedo is returned by Redo when no undone state is recorded.
+	ErrNothingToRedo = errors.New("nothing to redo")
+
+	// ErrInvalidGraphName is returned when a graph name could address a file
+	// outside the graph directory: it is empty, "." or contains a path
+	// separator or "..".
+	ErrInvalidGraphName = errors.New("invalid graph name")
+
+	// ErrVersionConflict is returned by SaveIfVersion when the graph has been
+	// mutated since the caller observed the expected version.
+	ErrVersionConflict = errors.New("version conflict")
+)
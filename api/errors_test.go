@@ -0,0 +1,34 @@
+package api
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+
+	if err := mgr.Save("nope"); !errors.Is(err, ErrGraphNotOpen) {
+		t.Errorf("expected ErrGraphNotOpen, got %v", err)
+	}
+	if _, err := mgr.ReadNodes(ReadNodesRequest{Graph: "nope"}); !errors.Is(err, ErrGraphNotOpen) {
+		t.Errorf("expected ErrGraphNotOpen from ReadNodes, got %v", err)
+	}
+
+	mgr.Open("g")
+	mgr.Upsert(UpsertRequest{Graph: "g", Nodes: []UpsertNode{{ID: "a", Status: "pending"}}})
+
+	_, err := mgr.Transition(TransitionRequest{Graph: "g", ID: "zzz", Status: "ready"})
+	if !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("expected ErrNodeNotFound, got %v", err)
+	}
+
+	_, err = mgr.Transition(TransitionRequest{Graph: "g", ID: "a", Status: "done"})
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition, got %v", err)
+	}
+	if errors.Is(err, ErrNodeNotFound) {
+		t.Error("invalid transition should not match ErrNodeNotFound")
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/imran31415/spine"
+)
+
+// defaultStatusColors fills nodes in ExportDOT when the graph's status
+// machine does not name a color for their status.
+var defaultStatusColors = map[string]string{
+	"pending": "lightgray",
+	"ready":   "lightblue",
+	"running": "gold",
+	"done":    "palegreen",
+	"failed":  "salmon",
+	"skipped": "white",
+}
+
+// ExportDOT renders the named graph in the Graphviz DOT language. Nodes are
+// labelled with their label (or ID if unlabelled) and filled by status,
+// using the colors of the graph's status machine where set. Edges show their
+// label, if any, and weight.
+func (m *Manager) ExportDOT(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(name)
+	if err != nil {
+		return nil, err
+	}
+	sm, err := StatusMachineFor(g)
+	if err != nil {
+		return nil, err
+	}
+	return spine.MarshalDOT(g, &spine.DOTOptions[NodeData, EdgeData]{
+		Name: name,
+		NodeAttrs: func(n spine.Node[NodeData]) map[string]string {
+			attrs := make(map[string]string)
+			if n.Data.Label != "" {
+				attrs["label"] = n.Data.Label
+			}
+			color, ok := sm.Colors[n.Data.Status]
+			if !ok {
+				color, ok = defaultStatusColors[n.Data.Status]
+			}
+			if ok {
+				attrs["style"] = "filled"
+				attrs["fillcolor"] = color
+			}
+			return attrs
+		},
+		EdgeAttrs: func(e spine.Edge[EdgeData]) map[string]string {
+			if e.Data.Label == "" {
+				return nil
+			}
+			return map[string]string{"label": e.Data.Label + " (" + strconv.FormatFloat(e.Weight, 'g', -1, 64) + ")"}
+		},
+	})
+}
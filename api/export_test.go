@@ -0,0 +1,41 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportDOT(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("plan")
+	mgr.Upsert(UpsertRequest{
+		Graph: "plan",
+		Nodes: []UpsertNode{{ID: "design", Label: "Design", Status: "done"}, {ID: "build", Status: "review"}},
+		Edges: []UpsertEdge{{From: "design", To: "build", Label: "blocks", Weight: floatPtr(3)}},
+	})
+	sm := DefaultStatusMachine()
+	sm.Transitions["ready"] = append(sm.Transitions["ready"], "review")
+	sm.Colors = map[string]string{"review": "#a78bfa"}
+	mgr.SetStatusMachine("plan", &sm)
+
+	data, err := mgr.ExportDOT("plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		`digraph "plan" {`,
+		`"design" [fillcolor="palegreen", label="Design", style="filled"];`,
+		`"build" [fillcolor="#a78bfa", label="build", style="filled"];`,
+		`"design" -> "build" [label="blocks (3)"];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	if _, err := mgr.ExportDOT("missing"); err == nil {
+		t.Error("expected error for unknown graph")
+	}
+}
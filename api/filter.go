@@ -2,6 +2,9 @@ package api
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/imran31415/spine"
@@ -18,34 +21,131 @@ func matchesFilters(g *spine.Graph[NodeData, EdgeData], nodeID string, filters [
 	}
 	store := g.NodeMeta(nodeID)
 	for _, f := range filters {
-		if !matchFilter(store, node.Data, f) {
+		var match bool
+		switch f.Key {
+		case "upstream_status":
+			match = matchNeighborStatus(g, g.InEdges(nodeID), true, f)
+		case "downstream_status":
+			match = matchNeighborStatus(g, g.OutEdges(nodeID), false, f)
+		default:
+			match = matchFilter(store, node.Data, f)
+		}
+		if !match {
 			return false
 		}
 	}
 	return true
 }
 
+// indexedCandidates narrows a node scan with g's metadata indexes: for the
+// first "eq" filter on an indexed metadata key, it returns the sorted IDs of
+// the nodes whose value may equal the filter's. Every filter must still be
+// applied to the result. ok is false if no filter can use an index.
+func indexedCandidates(g *spine.Graph[NodeData, EdgeData], filters []MetaFilter) (ids []string, ok bool) {
+	for _, f := range filters {
+		if f.Op != "eq" || f.Key == "status" || f.Key == "label" || !g.MetaIndexed(f.Key) {
+			continue
+		}
+		if _, isRef := fieldRef(f.Value); isRef {
+			continue
+		}
+		probes, ok := indexProbes(f.Value)
+		if !ok {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, p := range probes {
+			for _, id := range g.FindNodesByMeta(f.Key, p) {
+				if !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+		}
+		sort.Strings(ids)
+		return ids, true
+	}
+	return nil, false
+}
+
+// indexProbes returns the values a stored value may have to be valuesEqual
+// to v: v itself plus its string form, or for strings the bool and number
+// they parse as. Stored values that are not strings, numbers or bools are
+// not indexed, so they can only match through a scan.
+func indexProbes(v any) ([]any, bool) {
+	switch x := v.(type) {
+	case string:
+		probes := []any{x}
+		if b, err := strconv.ParseBool(x); err == nil {
+			probes = append(probes, b)
+		}
+		if f, err := strconv.ParseFloat(x, 64); err == nil {
+			probes = append(probes, f)
+		}
+		return probes, true
+	case bool:
+		return []any{x, strconv.FormatBool(x)}, true
+	}
+	if f, ok := toFloat64(v); ok {
+		return []any{f, fmt.Sprintf("%v", v)}, true
+	}
+	return nil, false
+}
+
+// matchNeighborStatus reports whether at least one neighbor across edges has
+// a status satisfying f's op and value. upstream selects the edge's From end
+// (predecessors) rather than its To end (successors).
+func matchNeighborStatus(g *spine.Graph[NodeData, EdgeData], edges []spine.Edge[EdgeData], upstream bool, f MetaFilter) bool {
+	statusFilter := MetaFilter{Key: "status", Op: f.Op, Value: f.Value}
+	for _, e := range edges {
+		id := e.To
+		if upstream {
+			id = e.From
+		}
+		n, ok := g.GetNode(id)
+		if !ok {
+			continue
+		}
+		if matchFilter(nil, n.Data, statusFilter) {
+			return true
+		}
+	}
+	return false
+}
+
 // matchFilter evaluates a single filter predicate against a node's structural
 // fields and metadata store.
 func matchFilter(store *spine.Store, data NodeData, f MetaFilter) bool {
-	// Resolve value: structural fields take precedence.
-	var val any
-	var found bool
+	return matchLookup(func(key string) (any, bool) { return fieldValue(store, data, key) }, f)
+}
 
-	switch f.Key {
-	case "status":
-		val, found = data.Status, true
-	case "label":
-		val, found = data.Label, true
-	default:
-		if store != nil {
-			val, found = store.Get(f.Key)
+// matchEdgeFilter evaluates a single filter predicate against an edge's
+// structural fields and metadata store.
+func matchEdgeFilter(store *spine.Store, e spine.Edge[EdgeData], f MetaFilter) bool {
+	return matchLookup(func(key string) (any, bool) { return edgeFieldValue(store, e, key) }, f)
+}
+
+// matchLookup evaluates a single filter predicate, resolving keys with lookup.
+func matchLookup(lookup func(key string) (any, bool), f MetaFilter) bool {
+	val, found := lookup(f.Key)
+
+	// A {"$field": key} value compares against another field of the same
+	// node or edge; if that field is missing the filter cannot match.
+	if ref, ok := fieldRef(f.Value); ok {
+		refVal, refFound := lookup(ref)
+		if !refFound {
+			return false
 		}
+		f.Value = refVal
 	}
 
 	switch f.Op {
 	case "exists":
 		return found
+	case "empty":
+		return !found || isEmptyValue(val)
+	case "notempty":
+		return found && !isEmptyValue(val)
 	case "eq":
 		if !found {
 			return false
@@ -61,19 +161,85 @@ func matchFilter(store *spine.Store, data NodeData, f MetaFilter) bool {
 			return false
 		}
 		return strings.Contains(fmt.Sprintf("%v", val), fmt.Sprintf("%v", f.Value))
-	case "gt":
-		return compareFloat(val, f.Value, found) > 0
-	case "gte":
-		return compareFloat(val, f.Value, found) >= 0
-	case "lt":
-		return compareFloat(val, f.Value, found) < 0
-	case "lte":
-		return compareFloat(val, f.Value, found) <= 0
+	case "gt", "gte", "lt", "lte":
+		// A missing or non-numeric operand never matches.
+		c := compareFloat(val, f.Value, found)
+		switch {
+		case c == -2:
+			return false
+		case f.Op == "gt":
+			return c > 0
+		case f.Op == "gte":
+			return c >= 0
+		case f.Op == "lt":
+			return c < 0
+		default:
+			return c <= 0
+		}
 	default:
 		return false
 	}
 }
 
+// fieldValue resolves key against a node's structural fields, which take
+// precedence, and then its metadata store.
+func fieldValue(store *spine.Store, data NodeData, key string) (any, bool) {
+	switch key {
+	case "status":
+		return data.Status, true
+	case "label":
+		return data.Label, true
+	}
+	if store == nil {
+		return nil, false
+	}
+	return store.Get(key)
+}
+
+// edgeFieldValue resolves key against an edge's structural fields ("from",
+// "to", "label" and "weight"), which take precedence, and then its metadata
+// store.
+func edgeFieldValue(store *spine.Store, e spine.Edge[EdgeData], key string) (any, bool) {
+	switch key {
+	case "from":
+		return e.From, true
+	case "to":
+		return e.To, true
+	case "label":
+		return e.Data.Label, true
+	case "weight":
+		return e.Weight, true
+	}
+	if store == nil {
+		return nil, false
+	}
+	return store.Get(key)
+}
+
+// fieldRef reports whether v is a field reference of the form
+// {"$field": key} and returns the referenced key.
+func fieldRef(v any) (string, bool) {
+	m, ok := v.(map[string]any)
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	key, ok := m["$field"].(string)
+	return key, ok
+}
+
+// isEmptyValue reports whether v is nil, "", or an empty slice or map.
+func isEmptyValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	}
+	return false
+}
+
 // valuesEqual compares two values for equality, using numeric comparison
 // when both values are numeric to avoid string formatting mismatches
 // (e.g., float64(1) vs int(1)).
@@ -79,6 +79,36 @@ func TestMatchFilter_Exists(t *testing.T) {
 	}
 }
 
+func TestMatchFilter_Empty(t *testing.T) {
+	g := newTestGraph()
+	g.AddNode("d", NodeData{Label: "Delta"})
+	g.NodeMeta("b").Set("owner", "")
+	g.NodeMeta("c").Set("owner", "carol")
+	g.NodeMeta("c").Set("tags", []any{})
+
+	if !matchesFilters(g, "d", []MetaFilter{{Key: "status", Op: "empty"}}) {
+		t.Error("expected empty status to match node d")
+	}
+	if matchesFilters(g, "a", []MetaFilter{{Key: "status", Op: "empty"}}) {
+		t.Error("expected status empty to NOT match node a")
+	}
+	if !matchesFilters(g, "b", []MetaFilter{{Key: "owner", Op: "empty"}}) {
+		t.Error("expected present-but-empty owner to match node b")
+	}
+	if !matchesFilters(g, "a", []MetaFilter{{Key: "owner", Op: "empty"}}) {
+		t.Error("expected missing owner to count as empty")
+	}
+	if !matchesFilters(g, "c", []MetaFilter{{Key: "tags", Op: "empty"}}) {
+		t.Error("expected empty slice to match")
+	}
+	if !matchesFilters(g, "c", []MetaFilter{{Key: "owner", Op: "notempty"}}) {
+		t.Error("expected owner notempty to match node c")
+	}
+	if matchesFilters(g, "b", []MetaFilter{{Key: "owner", Op: "notempty"}}) {
+		t.Error("expected owner notempty to NOT match node b")
+	}
+}
+
 func TestMatchFilter_AND(t *testing.T) {
 	g := newTestGraph()
 	filters := []MetaFilter{
@@ -124,3 +154,126 @@ func TestMatchFilter_UnknownOp(t *testing.T) {
 		t.Error("expected unknown op to return false")
 	}
 }
+
+func TestMatchFilter_UpstreamStatus(t *testing.T) {
+	g := newTestGraph()
+	g.AddNode("d", NodeData{Status: "pending"})
+	g.AddEdge("c", "b", EdgeData{}, 0) // b blocked by running c
+	g.AddEdge("a", "d", EdgeData{}, 0) // d only depends on done a
+
+	blocked := []MetaFilter{{Key: "upstream_status", Op: "eq", Value: "running"}}
+	if !matchesFilters(g, "b", blocked) {
+		t.Error("expected b to have a running upstream")
+	}
+	if matchesFilters(g, "d", blocked) {
+		t.Error("expected d to have no running upstream")
+	}
+	if matchesFilters(g, "c", blocked) {
+		t.Error("expected root c to have no upstream")
+	}
+	if !matchesFilters(g, "c", []MetaFilter{{Key: "downstream_status", Op: "eq", Value: "pending"}}) {
+		t.Error("expected c to have a pending downstream")
+	}
+}
+
+func TestMatchFilter_FieldRef(t *testing.T) {
+	g := newTestGraph()
+	g.NodeMeta("a").Set("current", float64(3))
+	g.NodeMeta("a").Set("target", float64(5))
+	g.NodeMeta("b").Set("current", float64(7))
+	g.NodeMeta("b").Set("target", float64(5))
+	g.NodeMeta("c").Set("current", float64(1))
+
+	behind := []MetaFilter{{Key: "current", Op: "lt", Value: map[string]any{"$field": "target"}}}
+	if !matchesFilters(g, "a", behind) {
+		t.Error("expected current < target to match node a")
+	}
+	if matchesFilters(g, "b", behind) {
+		t.Error("expected current < target to NOT match node b")
+	}
+	if matchesFilters(g, "c", behind) {
+		t.Error("expected missing target to NOT match node c")
+	}
+
+	// References resolve structural fields too.
+	g.NodeMeta("b").Set("expected_status", "pending")
+	if !matchesFilters(g, "b", []MetaFilter{{Key: "status", Op: "eq", Value: map[string]any{"$field": "expected_status"}}}) {
+		t.Error("expected status == expected_status to match node b")
+	}
+}
+
+func TestReadFieldRefImpossibleComparison(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("f")
+	mgr.Upsert(UpsertRequest{
+		Graph: "f",
+		Nodes: []UpsertNode{
+			{ID: "ok", Meta: map[string]any{"current": 1, "target": 5}},
+			{ID: "nocurrent", Meta: map[string]any{"target": 5}},
+			{ID: "text", Meta: map[string]any{"current": "low", "target": 5}},
+		},
+		Edges: []UpsertEdge{
+			{From: "ok", To: "nocurrent", Meta: map[string]any{"current": 1, "target": 5}},
+			{From: "ok", To: "text", Meta: map[string]any{"target": 5}},
+			{From: "nocurrent", To: "text", Meta: map[string]any{"current": 1, "target": "high"}},
+		},
+	})
+
+	for _, op := range []string{"lt", "lte", "gt", "gte"} {
+		filters := []MetaFilter{{Key: "current", Op: op, Value: map[string]any{"$field": "target"}}}
+		nodes, err := mgr.ReadNodes(ReadNodesRequest{Graph: "f", Filters: filters})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range nodes.Nodes {
+			if n.ID != "ok" {
+				t.Errorf("%s: expected %s not to match", op, n.ID)
+			}
+		}
+		edges, err := mgr.ReadEdges(ReadEdgesRequest{Graph: "f", Filters: filters})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range edges.Edges {
+			if e.From != "ok" || e.To != "nocurrent" {
+				t.Errorf("%s: expected %s->%s not to match", op, e.From, e.To)
+			}
+		}
+	}
+	nodes, _ := mgr.ReadNodes(ReadNodesRequest{Graph: "f", Filters: []MetaFilter{{Key: "current", Op: "lt", Value: map[string]any{"$field": "target"}}}})
+	if nodes.Total != 1 {
+		t.Errorf("expected only ok to match lt, got %+v", nodes.Nodes)
+	}
+}
+
+func TestReadNodesBlockedByRunning(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("p")
+	mgr.Upsert(UpsertRequest{
+		Graph: "p",
+		Nodes: []UpsertNode{
+			{ID: "build", Status: "running"},
+			{ID: "lint", Status: "done"},
+			{ID: "test", Status: "pending"},
+			{ID: "docs", Status: "pending"},
+		},
+		Edges: []UpsertEdge{
+			{From: "build", To: "test"},
+			{From: "lint", To: "test"},
+			{From: "lint", To: "docs"},
+		},
+	})
+
+	res, err := mgr.ReadNodes(ReadNodesRequest{
+		Graph:   "p",
+		Filters: []MetaFilter{{Key: "upstream_status", Op: "eq", Value: "running"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 1 || res.Nodes[0].ID != "test" {
+		t.Errorf("expected only test blocked, got %+v", res.Nodes)
+	}
+}
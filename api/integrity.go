@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/imran31415/spine"
+)
+
+// rawSnapshot is a persisted snapshot with node and edge data left undecoded,
+// so it can be rewritten without disturbing anything but the metadata.
+type rawSnapshot = spine.Snapshot[json.RawMessage, json.RawMessage]
+
+// CheckIntegrity inspects the named graph and reports metadata entries in
+// its persisted snapshot that reference missing nodes or edges, along with
+// self-loop edges and labels shared by more than one node. Orphaned metadata
+// is silently dropped when a graph is loaded, so it is only visible in the
+// file; self-loops and labels are checked on the graph in memory if it is
+// open, so unsaved edits count. If repair is set and orphans were found, the
+// snapshot file is rewritten without them.
+func (m *Manager) CheckIntegrity(name string, repair bool) (*IntegrityReport, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path, err := m.graphPath(name)
+	if err != nil {
+		return nil, err
+	}
+	g, open := m.graphs[name]
+	data, err := os.ReadFile(path)
+	persisted := err == nil
+	if os.IsNotExist(err) {
+		if !open {
+			return nil, fmt.Errorf("graph %q not found", name)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("read %q: %w", name, err)
+	}
+
+	var snap rawSnapshot
+	if persisted {
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return nil, fmt.Errorf("unmarshal %q: %w", name, err)
+		}
+	}
+
+	report := &IntegrityReport{
+		OrphanNodeMeta:         make([]string, 0),
+		OrphanEdgeMeta:         make([]EdgeKey, 0),
+		OrphanDirectedEdgeMeta: make([]EdgeKey, 0),
+		SelfLoops:              make([]EdgeKey, 0),
+		DuplicateLabels:        make(map[string][]string),
+	}
+	nodes := make(map[string]bool)
+	edges := make(map[EdgeKey]bool)
+	byLabel := make(map[string][]string)
+	if snap.Graph != nil {
+		for _, n := range snap.Graph.Nodes {
+			nodes[n.ID] = true
+			if open {
+				continue
+			}
+			var nd NodeData
+			if len(n.Data) > 0 {
+				_ = json.Unmarshal(n.Data, &nd)
+			}
+			if nd.Label != "" {
+				byLabel[nd.Label] = append(byLabel[nd.Label], n.ID)
+			}
+		}
+		for _, e := range snap.Graph.Edges {
+			edges[EdgeKey{From: e.From, To: e.To}] = true
+			if !snap.Directed {
+				edges[EdgeKey{From: e.To, To: e.From}] = true
+			}
+			if !open && e.From == e.To {
+				report.SelfLoops = append(report.SelfLoops, EdgeKey{From: e.From, To: e.To})
+			}
+		}
+	}
+	if open {
+		for _, n := range g.Nodes() {
+			if n.Data.Label != "" {
+				byLabel[n.Data.Label] = append(byLabel[n.Data.Label], n.ID)
+			}
+		}
+		for _, e := range g.Edges() {
+			if e.From == e.To {
+				report.SelfLoops = append(report.SelfLoops, EdgeKey{From: e.From, To: e.To})
+			}
+		}
+	}
+	for label, ids := range byLabel {
+		if len(ids) > 1 {
+			sort.Strings(ids)
+			report.DuplicateLabels[label] = ids
+		}
+	}
+
+	if snap.Meta != nil {
+		keptNodes := make([]spine.NodeMetaData, 0, len(snap.Meta.Nodes))
+		for _, nm := range snap.Meta.Nodes {
+			if nodes[nm.ID] {
+				keptNodes = append(keptNodes, nm)
+			} else {
+				report.OrphanNodeMeta = append(report.OrphanNodeMeta, nm.ID)
+			}
+		}
+		keepEdges := func(metas []spine.EdgeMetaData, orphans *[]EdgeKey) []spine.EdgeMetaData {
+			kept := make([]spine.EdgeMetaData, 0, len(metas))
+			for _, em := range metas {
+				key := EdgeKey{From: em.From, To: em.To}
+				if edges[key] {
+					kept = append(kept, em)
+				} else {
+					*orphans = append(*orphans, key)
+				}
+			}
+			return kept
+		}
+		snap.Meta.Nodes = keptNodes
+		snap.Meta.Edges = keepEdges(snap.Meta.Edges, &report.OrphanEdgeMeta)
+		snap.Meta.DirectedEdges = keepEdges(snap.Meta.DirectedEdges, &report.OrphanDirectedEdgeMeta)
+	}
+	sort.Strings(report.OrphanNodeMeta)
+	sortEdgeKeys(report.OrphanEdgeMeta)
+	sortEdgeKeys(report.OrphanDirectedEdgeMeta)
+	sortEdgeKeys(report.SelfLoops)
+
+	orphans := len(report.OrphanNodeMeta) + len(report.OrphanEdgeMeta) + len(report.OrphanDirectedEdgeMeta)
+	report.Clean = orphans == 0 && len(report.SelfLoops) == 0 && len(report.DuplicateLabels) == 0
+	if !repair || orphans == 0 {
+		return report, nil
+	}
+
+	if m.CompactSave {
+		data, err = json.Marshal(snap)
+	} else {
+		data, err = json.MarshalIndent(snap, "", "  ")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("marshal %q: %w", name, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return nil, fmt.Errorf("repair %q: %w", name, err)
+	}
+	report.Repaired = true
+	return report, nil
+}
+
+func sortEdgeKeys(keys []EdgeKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].From != keys[j].From {
+			return keys[i].From < keys[j].From
+		}
+		return keys[i].To < keys[j].To
+	})
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imran31415/spine"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("g")
+	mgr.Upsert(UpsertRequest{
+		Graph: "g",
+		Nodes: []UpsertNode{
+			{ID: "a", Label: "build", Meta: map[string]any{"owner": "x"}},
+			{ID: "b", Label: "build"},
+			{ID: "c", Label: "test"},
+		},
+		Edges: []UpsertEdge{{From: "a", To: "b"}, {From: "c", To: "c"}},
+	})
+	if err := mgr.Save("g"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Inject orphaned metadata into the persisted snapshot.
+	path := filepath.Join(dir, "g.json")
+	data, _ := os.ReadFile(path)
+	var snap rawSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		t.Fatal(err)
+	}
+	snap.Meta.Nodes = append(snap.Meta.Nodes, spine.NodeMetaData{ID: "ghost", Entries: map[string]any{"k": 1}})
+	snap.Meta.Edges = append(snap.Meta.Edges, spine.EdgeMetaData{From: "b", To: "a", Entries: map[string]any{"k": 1}})
+	snap.Meta.DirectedEdges = append(snap.Meta.DirectedEdges,
+		spine.EdgeMetaData{From: "a", To: "b", Entries: map[string]any{"k": 1}},
+		spine.EdgeMetaData{From: "c", To: "a", Entries: map[string]any{"k": 1}})
+	data, _ = json.Marshal(snap)
+	os.WriteFile(path, data, 0o644)
+
+	// The open graph never loaded the orphans, but the file is still scanned
+	// for them; self-loops come from the graph in memory, unsaved ones
+	// included.
+	mgr.Upsert(UpsertRequest{Graph: "g", Edges: []UpsertEdge{{From: "b", To: "b"}}})
+	report, err := mgr.CheckIntegrity("g", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.OrphanNodeMeta) != 1 || report.OrphanNodeMeta[0] != "ghost" {
+		t.Errorf("expected orphan node meta [ghost], got %v", report.OrphanNodeMeta)
+	}
+	if len(report.OrphanEdgeMeta) != 1 || report.OrphanEdgeMeta[0] != (EdgeKey{From: "b", To: "a"}) {
+		t.Errorf("expected orphan edge meta b->a, got %v", report.OrphanEdgeMeta)
+	}
+	if len(report.OrphanDirectedEdgeMeta) != 1 || report.OrphanDirectedEdgeMeta[0] != (EdgeKey{From: "c", To: "a"}) {
+		t.Errorf("expected orphan directed edge meta c->a, got %v", report.OrphanDirectedEdgeMeta)
+	}
+	if len(report.SelfLoops) != 2 || report.SelfLoops[0].From != "b" || report.SelfLoops[1].From != "c" {
+		t.Errorf("expected self-loops on b and c, got %v", report.SelfLoops)
+	}
+	if ids := report.DuplicateLabels["build"]; len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Errorf("expected duplicate label build on [a b], got %v", report.DuplicateLabels)
+	}
+	if report.Clean || report.Repaired {
+		t.Errorf("unexpected flags: clean=%v repaired=%v", report.Clean, report.Repaired)
+	}
+
+	// Closed, the snapshot is checked on its own.
+	mgr, _ = NewManager(dir)
+	report, err = mgr.CheckIntegrity("g", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.OrphanNodeMeta) != 1 || len(report.SelfLoops) != 1 || report.SelfLoops[0].From != "c" {
+		t.Errorf("expected the snapshot's orphan and self-loop on c, got %+v", report)
+	}
+
+	mgr.Open("g")
+	report, err = mgr.CheckIntegrity("g", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Repaired {
+		t.Error("expected repair to rewrite the snapshot")
+	}
+	report, _ = mgr.CheckIntegrity("g", false)
+	if len(report.OrphanNodeMeta) != 0 || len(report.OrphanEdgeMeta) != 0 || len(report.OrphanDirectedEdgeMeta) != 0 {
+		t.Errorf("expected no orphans after repair, got %+v", report)
+	}
+
+	// Legitimate metadata survives the repair.
+	fresh, _ := NewManager(dir)
+	fresh.Open("g")
+	resp, _ := fresh.ReadNodes(ReadNodesRequest{Graph: "g", IDs: []string{"a"}})
+	if len(resp.Nodes) != 1 || resp.Nodes[0].Meta["owner"] != "x" {
+		t.Errorf("expected owner metadata to survive repair, got %+v", resp.Nodes)
+	}
+
+	if _, err := mgr.CheckIntegrity("missing", false); err == nil {
+		t.Error("expected error for unknown graph")
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/imran31415/spine"
+)
+
+func (m *Manager) journalPath(name string) string {
+	return filepath.Join(m.dir, name+".journal")
+}
+
+// recoverJournalLocked replays the named graph's journal, if any, onto g,
+// which loadGraph has just read and is not registered yet. The entries are
+// replayed on a private Manager, so they are not journaled or logged again
+// and leave no undo history. Caller must hold m.mu.
+func (m *Manager) recoverJournalLocked(name string, g *spine.Graph[NodeData, EdgeData]) error {
+	entries, err := readLogEntries(m.journalPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("recover %q: %w", name, err)
+	}
+
+	r, err := NewManager(m.dir)
+	if err != nil {
+		return fmt.Errorf("recover %q: %w", name, err)
+	}
+	r.UndoDepth = m.UndoDepth
+	r.graphs[name] = g
+	if p, ok := m.policies[name]; ok {
+		r.policies[name] = p
+	}
+	for i, e := range entries {
+		if err := r.replayEntry(e); err != nil {
+			return fmt.Errorf("recover %q: journal entry %d (%s): %w", name, i+1, e.Op, err)
+		}
+	}
+	m.versions[name] += r.versions[name]
+	return nil
+}
+
+// journalStateLocked replaces the named graph's journal with one entry
+// holding the graph's current state. Undo and Redo are journaled this way:
+// the undo history they draw on does not survive a restart and may reach
+// back past the last Save. Caller must hold m.mu.
+func (m *Manager) journalStateLocked(name string) error {
+	g, err := m.getGraph(name)
+	if err != nil {
+		return err
+	}
+	data, err := spine.Marshal(g, &spine.MarshalOptions{Graph: true, Meta: true, Schemas: true})
+	if err != nil {
+		return fmt.Errorf("journal %q: %w", name, err)
+	}
+	line, err := json.Marshal(LogEntry{
+		Time:     time.Now().UTC(),
+		Graph:    name,
+		Op:       opRestore,
+		Directed: g.Directed,
+		Request:  data,
+	})
+	if err != nil {
+		return fmt.Errorf("journal %q: %w", name, err)
+	}
+	path := m.journalPath(name)
+	if err := os.WriteFile(path+".tmp", append(line, '\n'), 0o644); err != nil {
+		return fmt.Errorf("journal %q: %w", name, err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("journal %q: %w", name, err)
+	}
+	return nil
+}
+
+// restoreState replaces the named graph's contents with the state recorded
+// by journalStateLocked.
+func (m *Manager) restoreState(name string, data []byte) error {
+	snap, err := spine.Unmarshal[NodeData, EdgeData](data)
+	if err != nil {
+		return err
+	}
+	FixupGraphData(snap)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g, err := m.getGraph(name)
+	if err != nil {
+		return err
+	}
+	g.Restore(snap)
+	m.bumpVersion(name)
+	return nil
+}
+
+// compactJournalLocked discards the named graph's journal once its state is
+// persisted. Caller must hold m.mu.
+func (m *Manager) compactJournalLocked(name string) error {
+	if err := os.Remove(m.journalPath(name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("compact journal %q: %w", name, err)
+	}
+	return nil
+}
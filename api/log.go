@@ -0,0 +1,252 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/imran31415/spine"
+)
+
+// Mutation log operation names.
+const (
+	opUpsert           = "upsert"
+	opTransition       = "transition"
+	opRemove           = "remove"
+	opUndo             = "undo"
+	opRedo             = "redo"
+	opMerge            = "merge"
+	opSetMeta          = "set_meta"
+	opDeleteMeta       = "delete_meta"
+	opSetConfig        = "set_config"
+	opSetLabelSchema   = "set_label_schema"
+	opSetStatusMachine = "set_status_machine"
+	opApplyDelta       = "apply_delta"
+	opRestore          = "restore" // journal only: the graph's full state
+)
+
+// Logged requests of the mutations that take plain arguments rather than a
+// request struct. MergeGraphs is logged as the UpsertRequest it amounts to,
+// since the source graph may not exist at replay time.
+type (
+	metaBulkRequest struct {
+		IDs  []string       `json:"ids"`
+		Meta map[string]any `json:"meta,omitempty"`
+		Keys []string       `json:"keys,omitempty"`
+	}
+	setConfigRequest struct {
+		Values map[string]any `json:"values"`
+	}
+	labelSchemaRequest struct {
+		Label  string       `json:"label"`
+		Schema spine.Schema `json:"schema"`
+	}
+	statusMachineRequest struct {
+		Machine *StatusMachine `json:"machine"`
+	}
+	applyDeltaRequest struct {
+		Base  json.RawMessage `json:"base"`
+		Delta json.RawMessage `json:"delta"`
+	}
+)
+
+// LogEntry is one line of a Manager's append-only mutation log.
+type LogEntry struct {
+	Time     time.Time       `json:"time"`
+	Graph    string          `json:"graph"`
+	Op       string          `json:"op"`
+	Directed bool            `json:"directed"`
+	Request  json.RawMessage `json:"request"`
+}
+
+// NewManagerWithLog creates a Manager like NewManager that also appends a
+// JSON line to logPath for every successful mutation: Upsert, Transition,
+// Remove, AdvancePlan, Undo, Redo, MergeGraphs, SetMetaBulk, DeleteMetaBulk,
+// SetConfig, SetLabelSchema, SetStatusMachine, ApplyDelta and the repairs of
+// CheckIntegrity on open graphs.
+// The log can be fed to Replay to rebuild the graphs.
+func NewManagerWithLog(dir, logPath string) (*Manager, error) {
+	m, err := NewManager(dir)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open log: %w", err)
+	}
+	f.Close()
+	m.logPath = logPath
+	return m, nil
+}
+
+// logLocked appends a mutation to the log, if one is configured.
+// Caller must hold m.mu.
+func (m *Manager) logLocked(op, graph string, req any) error {
+	if m.logPath == "" && !m.Journal {
+		return nil
+	}
+	reqData, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("log %s: %w", op, err)
+	}
+	entry := LogEntry{
+		Time:    time.Now().UTC(),
+		Graph:   graph,
+		Op:      op,
+		Request: reqData,
+	}
+	if g, ok := m.graphs[graph]; ok {
+		entry.Directed = g.Directed
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("log %s: %w", op, err)
+	}
+
+	if m.logPath != "" {
+		if err := appendLine(m.logPath, line); err != nil {
+			return fmt.Errorf("log %s: %w", op, err)
+		}
+	}
+	if m.Journal {
+		if op == opUndo || op == opRedo {
+			return m.journalStateLocked(graph)
+		}
+		if err := appendLine(m.journalPath(graph), line); err != nil {
+			return fmt.Errorf("journal %s: %w", op, err)
+		}
+	}
+	return nil
+}
+
+// appendLine appends line and a newline to the file at path, creating it if
+// needed.
+func appendLine(path string, line []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Replay rebuilds graphs by applying every entry of the mutation log at
+// logPath in order. Graphs that are not yet open are opened (or created with
+// the directedness recorded in the log) on first use. If this Manager has a
+// log of its own, replayed mutations are appended to it.
+func (m *Manager) Replay(logPath string) error {
+	entries, err := readLogEntries(logPath)
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	for i, e := range entries {
+		if err := m.replayEntry(e); err != nil {
+			return fmt.Errorf("replay entry %d (%s %q): %w", i+1, e.Op, e.Graph, err)
+		}
+	}
+	return nil
+}
+
+// readLogEntries reads every entry of the mutation log at path.
+func readLogEntries(path string) ([]LogEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []LogEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (m *Manager) replayEntry(e LogEntry) error {
+	if _, err := m.OpenWithDirected(e.Graph, e.Directed); err != nil {
+		return err
+	}
+	switch e.Op {
+	case opUpsert, opMerge:
+		var req UpsertRequest
+		if err := json.Unmarshal(e.Request, &req); err != nil {
+			return err
+		}
+		_, err := m.Upsert(req)
+		return err
+	case opTransition:
+		var req TransitionRequest
+		if err := json.Unmarshal(e.Request, &req); err != nil {
+			return err
+		}
+		_, err := m.Transition(req)
+		return err
+	case opRemove:
+		var req RemoveRequest
+		if err := json.Unmarshal(e.Request, &req); err != nil {
+			return err
+		}
+		_, err := m.Remove(req)
+		return err
+	case opUndo:
+		return m.Undo(e.Graph)
+	case opRedo:
+		return m.Redo(e.Graph)
+	case opSetMeta, opDeleteMeta:
+		var req metaBulkRequest
+		if err := json.Unmarshal(e.Request, &req); err != nil {
+			return err
+		}
+		var err error
+		if e.Op == opSetMeta {
+			_, err = m.SetMetaBulk(e.Graph, req.IDs, req.Meta)
+		} else {
+			_, err = m.DeleteMetaBulk(e.Graph, req.IDs, req.Keys)
+		}
+		return err
+	case opSetConfig:
+		var req setConfigRequest
+		if err := json.Unmarshal(e.Request, &req); err != nil {
+			return err
+		}
+		return m.SetConfig(e.Graph, req.Values)
+	case opSetLabelSchema:
+		var req labelSchemaRequest
+		if err := json.Unmarshal(e.Request, &req); err != nil {
+			return err
+		}
+		return m.SetLabelSchema(e.Graph, req.Label, req.Schema)
+	case opSetStatusMachine:
+		var req statusMachineRequest
+		if err := json.Unmarshal(e.Request, &req); err != nil {
+			return err
+		}
+		return m.SetStatusMachine(e.Graph, req.Machine)
+	case opApplyDelta:
+		var req applyDeltaRequest
+		if err := json.Unmarshal(e.Request, &req); err != nil {
+			return err
+		}
+		_, err := m.ApplyDelta(e.Graph, req.Base, req.Delta)
+		return err
+	case opRestore:
+		return m.restoreState(e.Graph, e.Request)
+	}
+	return fmt.Errorf("unknown op %q", e.Op)
+}
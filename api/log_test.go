@@ -0,0 +1,231 @@
+package api
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imran31415/spine"
+)
+
+func TestReplayLog(t *testing.T) {
+	dir := tempDir(t)
+	logPath := filepath.Join(dir, "mutations.jsonl")
+	mgr, err := NewManagerWithLog(filepath.Join(dir, "a"), logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr.Open("plan")
+	mgr.OpenWithDirected("net", false)
+
+	mgr.Upsert(UpsertRequest{
+		Graph: "plan",
+		Nodes: []UpsertNode{
+			{ID: "a", Label: "A", Status: "running", Meta: map[string]any{"owner": "alice"}},
+			{ID: "b", Label: "B", Status: "pending"},
+			{ID: "c", Label: "C", Status: "pending"},
+		},
+		Edges: []UpsertEdge{{From: "a", To: "b"}, {From: "a", To: "c"}},
+	})
+	mgr.Upsert(UpsertRequest{Graph: "net", Edges: []UpsertEdge{{From: "x", To: "y"}}})
+	mgr.Transition(TransitionRequest{Graph: "plan", ID: "a", Status: "done"})
+	mgr.Remove(RemoveRequest{Graph: "plan", Nodes: []string{"c"}})
+
+	// Failed mutations are not logged.
+	if _, err := mgr.Transition(TransitionRequest{Graph: "plan", ID: "b", Status: "done"}); err == nil {
+		t.Fatal("expected invalid transition")
+	}
+
+	f, err := os.Open(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := 0
+	for s := bufio.NewScanner(f); s.Scan(); {
+		lines++
+	}
+	f.Close()
+	if lines != 4 {
+		t.Errorf("expected 4 log lines, got %d", lines)
+	}
+
+	fresh, _ := NewManager(filepath.Join(dir, "b"))
+	if err := fresh.Replay(logPath); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"plan", "net"} {
+		want, _ := mgr.OpenGraph(name)
+		got, err := fresh.OpenGraph(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if spine.Fingerprint(got) != spine.Fingerprint(want) {
+			t.Errorf("graph %q differs after replay", name)
+		}
+	}
+	got, _ := fresh.OpenGraph("plan")
+	if n, _ := got.GetNode("b"); n.Data.Status != "ready" {
+		t.Errorf("expected b ready after replay, got %q", n.Data.Status)
+	}
+	if net, _ := fresh.OpenGraph("net"); net.Directed {
+		t.Error("expected net to be replayed as undirected")
+	}
+}
+
+func TestReplayLogAllOps(t *testing.T) {
+	dir := tempDir(t)
+	logPath := filepath.Join(dir, "mutations.jsonl")
+	mgr, _ := NewManagerWithLog(filepath.Join(dir, "a"), logPath)
+	mgr.Open("plan")
+	mgr.Open("other")
+	mgr.Upsert(UpsertRequest{Graph: "plan", Nodes: []UpsertNode{{ID: "a", Status: "pending"}, {ID: "b"}}})
+	mgr.Upsert(UpsertRequest{Graph: "other", Nodes: []UpsertNode{{ID: "c", Meta: map[string]any{"k": "v"}}}})
+	mgr.MergeGraphs("plan", "other", nil)
+	mgr.SetMetaBulk("plan", []string{"a", "b"}, map[string]any{"owner": "bob", "tmp": true})
+	mgr.DeleteMetaBulk("plan", []string{"a"}, []string{"tmp"})
+	mgr.SetConfig("plan", map[string]any{"theme": "dark"})
+	mgr.SetLabelSchema("plan", "task", spine.Schema{"owner": {Type: spine.FieldString}})
+	mgr.SetStatusMachine("plan", &StatusMachine{Transitions: map[string][]string{"pending": {"done"}}})
+	base, _ := mgr.Snapshot("plan")
+	mgr.Upsert(UpsertRequest{Graph: "plan", Nodes: []UpsertNode{{ID: "d"}}})
+	delta, _ := mgr.SaveDelta("plan", base)
+	mgr.Undo("plan")
+	if _, err := mgr.ApplyDelta("plan", base, delta); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := readLogEntries(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ops []string
+	for _, e := range entries[2:] {
+		ops = append(ops, e.Op)
+	}
+	want := []string{opMerge, opSetMeta, opDeleteMeta, opSetConfig, opSetLabelSchema, opSetStatusMachine, opUpsert, opUndo, opApplyDelta}
+	if fmt.Sprint(ops) != fmt.Sprint(want) {
+		t.Fatalf("expected ops %v, got %v", want, ops)
+	}
+
+	fresh, _ := NewManager(filepath.Join(dir, "b"))
+	if err := fresh.Replay(logPath); err != nil {
+		t.Fatal(err)
+	}
+	want1, _ := mgr.Snapshot("plan")
+	got, _ := fresh.Snapshot("plan")
+	if string(got) != string(want1) {
+		t.Errorf("plan differs after replay:\n got %s\nwant %s", got, want1)
+	}
+
+	// The configuration setters are undoable too.
+	for range 3 {
+		if err := mgr.Undo("plan"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cfg, _ := mgr.Config("plan"); cfg["theme"] != "dark" || cfg[labelSchemasKey] != nil {
+		t.Errorf("expected only the config change left, got %v", cfg)
+	}
+}
+
+func TestReplayUnknownOp(t *testing.T) {
+	dir := tempDir(t)
+	logPath := filepath.Join(dir, "bad.jsonl")
+	os.WriteFile(logPath, []byte(`{"graph":"g","op":"explode","request":{}}`+"\n"), 0o644)
+
+	mgr, _ := NewManager(dir)
+	if err := mgr.Replay(logPath); err == nil {
+		t.Error("expected error for unknown op")
+	}
+}
+
+func TestLogFailureRollsBack(t *testing.T) {
+	dir := tempDir(t)
+	logPath := filepath.Join(dir, "mutations.jsonl")
+	mgr, err := NewManagerWithLog(dir, logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, _ := mgr.Open("g")
+	mgr.Upsert(UpsertRequest{
+		Graph: "g",
+		Nodes: []UpsertNode{{ID: "a", Status: "pending", MetaOps: []MetaOp{{Key: "n", Op: "incr"}}}},
+		Edges: []UpsertEdge{{From: "a", To: "b"}},
+	})
+	mgr.Undo("g")
+	mgr.Redo("g")
+	g, _ := mgr.OpenGraph("g")
+	want := spine.Fingerprint(g)
+	info, _ = mgr.Open("g")
+	version := info.Version
+
+	// A directory in place of the log makes every append fail.
+	os.Remove(logPath)
+	os.Mkdir(logPath, 0o755)
+
+	checks := map[string]func() error{
+		"upsert": func() error {
+			_, err := mgr.Upsert(UpsertRequest{Graph: "g", Nodes: []UpsertNode{{ID: "a", MetaOps: []MetaOp{{Key: "n", Op: "incr"}}}}})
+			return err
+		},
+		"remove": func() error {
+			_, err := mgr.Remove(RemoveRequest{Graph: "g", Nodes: []string{"b"}})
+			return err
+		},
+		"set_meta": func() error {
+			_, err := mgr.SetMetaBulk("g", []string{"a"}, map[string]any{"k": 1})
+			return err
+		},
+		"delete_meta": func() error {
+			_, err := mgr.DeleteMetaBulk("g", []string{"a"}, []string{"n"})
+			return err
+		},
+		"set_config": func() error { return mgr.SetConfig("g", map[string]any{"k": 1}) },
+		"transition": func() error {
+			_, err := mgr.Transition(TransitionRequest{Graph: "g", ID: "a", Status: "ready"})
+			return err
+		},
+		"advance": func() error {
+			_, err := mgr.AdvancePlan("g", []string{"a"})
+			return err
+		},
+		"set_status_machine": func() error { return mgr.SetStatusMachine("g", nil) },
+		"set_label_schema": func() error {
+			return mgr.SetLabelSchema("g", "x", spine.Schema{"k": {Type: spine.FieldInt}})
+		},
+		"undo": func() error { return mgr.Undo("g") },
+	}
+	for name, fn := range checks {
+		if err := fn(); err == nil {
+			t.Errorf("%s: expected the log failure to be reported", name)
+		}
+		if got := spine.Fingerprint(g); got != want {
+			t.Errorf("%s: expected the graph rolled back, got a different graph", name)
+		}
+		if info, _ := mgr.Open("g"); info.Version != version {
+			t.Errorf("%s: expected version %d kept, got %d", name, version, info.Version)
+		}
+	}
+
+	// The undo and redo stacks are intact, and a retry once the log is back
+	// applies the increment once.
+	os.Remove(logPath)
+	if err := mgr.Redo("g"); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("expected nothing to redo, got %v", err)
+	}
+	if _, err := mgr.Upsert(UpsertRequest{Graph: "g", Nodes: []UpsertNode{{ID: "a", MetaOps: []MetaOp{{Key: "n", Op: "incr"}}}}}); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := g.NodeMeta("a").Get("n"); v != 2 {
+		t.Errorf("expected n incremented once more to 2, got %v", v)
+	}
+	if err := mgr.Undo("g"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.Undo("g"); err != nil {
+		t.Errorf("expected the original upsert still undoable, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/imran31415/spine"
+)
+
+// MoreAdvancedStatus is a status resolver for MergeGraphs that keeps
+// whichever of a and b is further along the default task lifecycle (see
+// statusRank), preferring a on a tie. An empty status never wins over a
+// set one.
+func MoreAdvancedStatus(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	case statusRank[b] > statusRank[a]:
+		return b
+	}
+	return a
+}
+
+// MergeGraphs merges the src graph into dst, both of which must be open and
+// have the same directedness. Nodes and edges missing from dst are copied
+// with their metadata. For nodes present in both, the status becomes
+// statusResolve(dstStatus, srcStatus) (MoreAdvancedStatus if nil), an empty
+// label is filled in from src, and metadata keys missing from dst are copied;
+// existing dst edges and metadata values are kept. src is not modified. The
+// merge is logged with the equivalent upsert on dst as its request.
+func (m *Manager) MergeGraphs(dst, src string, statusResolve func(a, b string) string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dg, err := m.getGraph(dst)
+	if err != nil {
+		return err
+	}
+	sg, err := m.getGraph(src)
+	if err != nil {
+		return err
+	}
+	if dg.Directed != sg.Directed {
+		return fmt.Errorf("merge %q into %q: directedness differs", src, dst)
+	}
+	if statusResolve == nil {
+		statusResolve = MoreAdvancedStatus
+	}
+
+	undo, rollback := m.rollbackSnapshot(dg)
+	mark := m.markLocked(dst)
+	logged := UpsertRequest{Graph: dst}
+	for _, sn := range sg.Nodes() {
+		nd := sn.Data
+		if existing, ok := dg.GetNode(sn.ID); ok {
+			nd = existing.Data
+			nd.Status = statusResolve(existing.Data.Status, sn.Data.Status)
+			if nd.Label == "" {
+				nd.Label = sn.Data.Label
+			}
+		}
+		dg.AddNode(sn.ID, nd)
+		var meta map[string]any
+		if sg.NodeMetaCount(sn.ID) > 0 {
+			meta = missingMeta(dg.NodeMeta(sn.ID), sg.NodeMeta(sn.ID))
+			if _, err := setMeta(dg.NodeMeta(sn.ID), meta); err != nil {
+				dg.Restore(rollback)
+				return fmt.Errorf("merge %q into %q: node %q: %w", src, dst, sn.ID, err)
+			}
+		}
+		logged.Nodes = append(logged.Nodes, UpsertNode{ID: sn.ID, Label: nd.Label, Status: nd.Status, Meta: meta})
+	}
+
+	edges, _ := sg.EdgesPage(0, 0)
+	for _, se := range edges {
+		if !dg.HasEdge(se.From, se.To) {
+			_ = dg.AddEdge(se.From, se.To, se.Data, se.Weight)
+		}
+		var meta map[string]any
+		if sg.EdgeMetaCount(se.From, se.To) > 0 {
+			meta = missingMeta(dg.EdgeMeta(se.From, se.To), sg.EdgeMeta(se.From, se.To))
+			if _, err := setMeta(dg.EdgeMeta(se.From, se.To), meta); err != nil {
+				dg.Restore(rollback)
+				return fmt.Errorf("merge %q into %q: edge %s->%s: %w", src, dst, se.From, se.To, err)
+			}
+		}
+		e, _ := dg.GetEdge(se.From, se.To)
+		w := e.Weight
+		logged.Edges = append(logged.Edges, UpsertEdge{From: se.From, To: se.To, Label: e.Data.Label, Weight: &w, Meta: meta})
+	}
+
+	m.pushUndoLocked(dst, undo)
+	m.bumpVersion(dst)
+	if err := m.logLocked(opMerge, dst, logged); err != nil {
+		m.abortLocked(dst, dg, rollback, mark)
+		return err
+	}
+	return nil
+}
+
+// missingMeta returns the entries of src whose keys are absent from dst.
+func missingMeta(dst, src *spine.Store) map[string]any {
+	out := make(map[string]any)
+	src.Range(func(k string, v any) bool {
+		if _, ok := dst.Get(k); !ok {
+			out[k] = v
+		}
+		return true
+	})
+	return out
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMergeGraphs(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("backend")
+	mgr.Open("frontend")
+	mgr.Upsert(UpsertRequest{
+		Graph: "backend",
+		Nodes: []UpsertNode{
+			{ID: "design", Label: "Design", Status: "done", Meta: map[string]any{"owner": "ana"}},
+			{ID: "api", Status: "running"},
+			{ID: "release", Status: "pending"},
+		},
+		Edges: []UpsertEdge{{From: "design", To: "api"}, {From: "api", To: "release"}},
+	})
+	mgr.Upsert(UpsertRequest{
+		Graph: "frontend",
+		Nodes: []UpsertNode{
+			{ID: "design", Status: "running", Meta: map[string]any{"owner": "bo", "figma": "link"}},
+			{ID: "ui", Label: "UI", Status: "ready"},
+			{ID: "release", Label: "Release", Status: "ready"},
+		},
+		Edges: []UpsertEdge{{From: "design", To: "ui"}, {From: "ui", To: "release"}},
+	})
+
+	if err := mgr.MergeGraphs("backend", "frontend", nil); err != nil {
+		t.Fatal(err)
+	}
+	g, _ := mgr.OpenGraph("backend")
+	if g.Order() != 4 || g.Size() != 4 {
+		t.Fatalf("expected 4 nodes and 4 edges, got %d and %d", g.Order(), g.Size())
+	}
+	for _, e := range [][2]string{{"design", "api"}, {"api", "release"}, {"design", "ui"}, {"ui", "release"}} {
+		if !g.HasEdge(e[0], e[1]) {
+			t.Errorf("expected edge %s->%s", e[0], e[1])
+		}
+	}
+
+	statuses := map[string]string{"design": "done", "api": "running", "ui": "ready", "release": "ready"}
+	for id, want := range statuses {
+		n, _ := g.GetNode(id)
+		if n.Data.Status != want {
+			t.Errorf("%s: expected status %q, got %q", id, want, n.Data.Status)
+		}
+	}
+	if n, _ := g.GetNode("release"); n.Data.Label != "Release" {
+		t.Errorf("expected empty label to be filled from src, got %q", n.Data.Label)
+	}
+	if v, _ := g.NodeMeta("design").Get("owner"); v != "ana" {
+		t.Errorf("expected dst metadata to win, got %v", v)
+	}
+	if v, _ := g.NodeMeta("design").Get("figma"); v != "link" {
+		t.Errorf("expected missing metadata to be copied, got %v", v)
+	}
+
+	// A custom resolver: src always wins.
+	mgr.Open("other")
+	mgr.Upsert(UpsertRequest{Graph: "other", Nodes: []UpsertNode{{ID: "design", Status: "pending"}}})
+	if err := mgr.MergeGraphs("backend", "other", func(a, b string) string { return b }); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := g.GetNode("design"); n.Data.Status != "pending" {
+		t.Errorf("expected resolver to pick src status, got %q", n.Data.Status)
+	}
+
+	if err := mgr.MergeGraphs("backend", "missing", nil); !errors.Is(err, ErrGraphNotOpen) {
+		t.Errorf("expected ErrGraphNotOpen, got %v", err)
+	}
+	mgr.OpenWithDirected("undirected", false)
+	if err := mgr.MergeGraphs("backend", "undirected", nil); err == nil {
+		t.Error("expected error merging graphs of different directedness")
+	}
+}
+
+func TestMoreAdvancedStatus(t *testing.T) {
+	tests := []struct{ a, b, want string }{
+		{"pending", "done", "done"},
+		{"running", "ready", "running"},
+		{"", "pending", "pending"},
+		{"failed", "pending", "pending"},
+		{"done", "skipped", "done"},
+		{"failed", "", "failed"},
+	}
+	for _, tt := range tests {
+		if got := MoreAdvancedStatus(tt.a, tt.b); got != tt.want {
+			t.Errorf("MoreAdvancedStatus(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
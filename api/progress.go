@@ -0,0 +1,65 @@
+package api
+
+// statusRank orders statuses by how far along a task is. Failed ranks below
+// pending so any move into failure counts as a regression.
+var statusRank = map[string]int{
+	"failed":  -1,
+	"":        0,
+	"pending": 0,
+	"ready":   1,
+	"running": 2,
+	"done":    3,
+	"skipped": 3,
+}
+
+// ProgressDiff compares two open graphs holding snapshots of the same plan,
+// a (before) and b (after). Nodes are matched by ID; status changes are split
+// into advanced and regressed, and the net change in "done" nodes is reported.
+func (m *Manager) ProgressDiff(a, b string) (*ProgressReport, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ga, err := m.getGraph(a)
+	if err != nil {
+		return nil, err
+	}
+	gb, err := m.getGraph(b)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ProgressReport{
+		Advanced:  make([]StatusChange, 0),
+		Regressed: make([]StatusChange, 0),
+	}
+	for _, n := range ga.Nodes() {
+		if n.Data.Status == "done" {
+			report.DoneBefore++
+		}
+		after, ok := gb.GetNode(n.ID)
+		if !ok {
+			report.Removed = append(report.Removed, n.ID)
+			continue
+		}
+		from, to := n.Data.Status, after.Data.Status
+		if from == to {
+			continue
+		}
+		change := StatusChange{ID: n.ID, From: from, To: to}
+		if statusRank[to] < statusRank[from] {
+			report.Regressed = append(report.Regressed, change)
+		} else {
+			report.Advanced = append(report.Advanced, change)
+		}
+	}
+	for _, n := range gb.Nodes() {
+		if n.Data.Status == "done" {
+			report.DoneAfter++
+		}
+		if !ga.HasNode(n.ID) {
+			report.Added = append(report.Added, n.ID)
+		}
+	}
+	report.NetCompleted = report.DoneAfter - report.DoneBefore
+	return report, nil
+}
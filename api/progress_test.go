@@ -0,0 +1,56 @@
+package api
+
+import "testing"
+
+func TestProgressDiff(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	plan := UpsertRequest{
+		Nodes: []UpsertNode{
+			{ID: "design", Status: "running"},
+			{ID: "build", Status: "ready"},
+			{ID: "test", Status: "pending"},
+			{ID: "ship", Status: "done"},
+		},
+		Edges: []UpsertEdge{{From: "design", To: "test"}, {From: "build", To: "test"}},
+	}
+	for _, name := range []string{"before", "after"} {
+		mgr.Open(name)
+		req := plan
+		req.Graph = name
+		mgr.Upsert(req)
+	}
+
+	// Complete design and build; ship regresses to failed for the sake of the test.
+	mgr.Transition(TransitionRequest{Graph: "after", ID: "design", Status: "done"})
+	mgr.Transition(TransitionRequest{Graph: "after", ID: "build", Status: "running"})
+	mgr.Transition(TransitionRequest{Graph: "after", ID: "build", Status: "done"})
+	mgr.Upsert(UpsertRequest{Graph: "after", Nodes: []UpsertNode{{ID: "ship", Status: "failed"}, {ID: "docs", Status: "pending"}}})
+
+	report, err := mgr.ProgressDiff("before", "after")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"build": "done", "design": "done", "test": "ready"}
+	if len(report.Advanced) != len(want) {
+		t.Fatalf("expected %d advanced, got %+v", len(want), report.Advanced)
+	}
+	for _, c := range report.Advanced {
+		if want[c.ID] != c.To {
+			t.Errorf("unexpected advance %+v", c)
+		}
+	}
+	if len(report.Regressed) != 1 || report.Regressed[0].ID != "ship" {
+		t.Errorf("expected ship regressed, got %+v", report.Regressed)
+	}
+	if len(report.Added) != 1 || report.Added[0] != "docs" {
+		t.Errorf("expected docs added, got %v", report.Added)
+	}
+	if report.DoneBefore != 1 || report.DoneAfter != 2 || report.NetCompleted != 1 {
+		t.Errorf("unexpected done counts: %+v", report)
+	}
+
+	if _, err := mgr.ProgressDiff("before", "nope"); err == nil {
+		t.Error("expected error for non-open graph")
+	}
+}
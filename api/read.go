@@ -1,7 +1,9 @@
 package api
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/imran31415/spine"
 )
@@ -28,6 +30,8 @@ func (m *Manager) ReadNodes(req ReadNodesRequest) (*ReadNodesResponse, error) {
 				ids = append(ids, id)
 			}
 		}
+	} else if cands, ok := indexedCandidates(g, req.Filters); ok {
+		ids = cands
 	} else {
 		ids = sortedNodeIDs(g)
 	}
@@ -62,15 +66,12 @@ func (m *Manager) ReadNodes(req ReadNodesRequest) (*ReadNodesResponse, error) {
 	keySet := makeKeySet(req.Keys)
 	nodes := make([]NodeResult, 0, len(page))
 	for _, id := range page {
-		n, _ := g.GetNode(id)
-		nr := NodeResult{
-			ID:        id,
-			Label:     n.Data.Label,
-			Status:    n.Data.Status,
-			InDegree:  len(g.InEdges(id)),
-			OutDegree: len(g.OutEdges(id)),
-		}
-		nr.Meta = projectMeta(g.NodeMeta(id), keySet)
+		nr := nodeResult(g, id, keySet)
+		if req.IncludeReachCounts {
+			ancestors := len(spine.Ancestors(g, id))
+			descendants := len(spine.Descendants(g, id))
+			nr.AncestorCount, nr.DescendantCount = &ancestors, &descendants
+		}
 		nodes = append(nodes, nr)
 	}
 
@@ -80,15 +81,18 @@ func (m *Manager) ReadNodes(req ReadNodesRequest) (*ReadNodesResponse, error) {
 		HasMore: end < total,
 	}
 
-	// Optionally include edges between matched nodes.
+	// Optionally include edges between matched nodes. EdgesPage lists each
+	// undirected edge once, normalized so From <= To.
 	if req.IncludeEdges && len(page) > 0 {
 		sub := spine.Subgraph(g, page)
-		for _, e := range sub.Edges() {
+		edges, _ := sub.EdgesPage(0, 0)
+		for _, e := range edges {
 			er := EdgeResult{
-				From:   e.From,
-				To:     e.To,
-				Label:  e.Data.Label,
-				Weight: e.Weight,
+				From:       e.From,
+				To:         e.To,
+				Label:      e.Data.Label,
+				Weight:     e.Weight,
+				Undirected: !g.Directed,
 			}
 			edgeMeta := g.EdgeMeta(e.From, e.To)
 			er.Meta = projectMeta(edgeMeta, keySet)
@@ -99,6 +103,244 @@ func (m *Manager) ReadNodes(req ReadNodesRequest) (*ReadNodesResponse, error) {
 	return resp, nil
 }
 
+// ReadEdges returns the edges of a graph that pass every filter, sorted by
+// From then To, with metadata projected to Keys and paginated like
+// ReadNodes. Undirected edges are reported once with From <= To.
+func (m *Manager) ReadEdges(req ReadEdgesRequest) (*ReadEdgesResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(req.Graph)
+	if err != nil {
+		return nil, err
+	}
+
+	all, _ := g.EdgesPage(0, 0)
+	var matched []spine.Edge[EdgeData]
+	for _, e := range all {
+		var store *spine.Store
+		if g.EdgeMetaCount(e.From, e.To) > 0 {
+			store = g.EdgeMeta(e.From, e.To)
+		}
+		ok := true
+		for _, f := range req.Filters {
+			if !matchEdgeFilter(store, e, f) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, e)
+		}
+	}
+	total := len(matched)
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	offset := min(max(req.Offset, 0), total)
+	end := min(offset+limit, total)
+
+	keySet := makeKeySet(req.Keys)
+	edges := make([]EdgeResult, 0, end-offset)
+	for _, e := range matched[offset:end] {
+		er := EdgeResult{
+			From:       e.From,
+			To:         e.To,
+			Label:      e.Data.Label,
+			Weight:     e.Weight,
+			Undirected: !g.Directed,
+		}
+		if g.EdgeMetaCount(e.From, e.To) > 0 {
+			er.Meta = projectMeta(g.EdgeMeta(e.From, e.To), keySet)
+		}
+		edges = append(edges, er)
+	}
+	return &ReadEdgesResponse{Edges: edges, Total: total, HasMore: end < total}, nil
+}
+
+// NodesByKind returns every node whose kindKey metadata value equals kindValue,
+// sorted by ID. It is intended for heterogeneous graphs that tag nodes with a
+// type, such as directory trees mixing "directory" and "file" nodes.
+func (m *Manager) NodesByKind(graph, kindKey, kindValue string) ([]NodeResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, ok := indexedCandidates(g, []MetaFilter{{Key: kindKey, Op: "eq", Value: kindValue}})
+	if !ok {
+		ids = sortedNodeIDs(g)
+	}
+	result := make([]NodeResult, 0)
+	for _, id := range ids {
+		if g.NodeMetaCount(id) == 0 {
+			continue
+		}
+		v, ok := g.NodeMeta(id).Get(kindKey)
+		if !ok || !valuesEqual(v, kindValue) {
+			continue
+		}
+		result = append(result, nodeResult(g, id, nil))
+	}
+	return result, nil
+}
+
+// IndexMetaKey indexes the named graph's node metadata values under key, so
+// ReadNodes "eq" filters and NodesByKind lookups on it visit only the
+// matching nodes instead of scanning the graph. The index is kept up to date
+// by later mutations but is not saved; it lasts while the graph is open.
+func (m *Manager) IndexMetaKey(graph, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(graph)
+	if err != nil {
+		return err
+	}
+	g.IndexMetaKey(key)
+	return nil
+}
+
+// CountByMetaKey tallies how many nodes do and do not have the given metadata
+// key set, e.g. for data-quality checks.
+func (m *Manager) CountByMetaKey(graph, key string) (withKey, withoutKey int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(graph)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, n := range g.Nodes() {
+		if g.NodeMetaCount(n.ID) > 0 && g.NodeMeta(n.ID).Has(key) {
+			withKey++
+		} else {
+			withoutKey++
+		}
+	}
+	return withKey, withoutKey, nil
+}
+
+// outlineLegend explains the line format produced by Outline.
+const outlineLegend = "# id(status) -> out-neighbors"
+
+// Outline returns a compact text adjacency list of the named graph, one line
+// per node in ID order such as "a(done) -> b, c", preceded by a legend line.
+// The status is omitted for nodes without one, as is the arrow for nodes
+// without out-neighbors. It trades structure for brevity in LLM contexts.
+func (m *Manager) Outline(name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(name)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(outlineLegend)
+	for _, n := range g.Nodes() {
+		b.WriteByte('\n')
+		b.WriteString(n.ID)
+		if n.Data.Status != "" {
+			b.WriteString("(" + n.Data.Status + ")")
+		}
+		if out := g.Neighbors(n.ID); len(out) > 0 {
+			b.WriteString(" -> ")
+			b.WriteString(strings.Join(out, ", "))
+		}
+	}
+	return b.String(), nil
+}
+
+// Layers groups the nodes of the named graph by dependency depth, so a plan
+// can be described wave by wave: level 0 holds the roots and each later
+// level holds the nodes whose deepest dependency is on the level before.
+// Returns an error if the graph is undirected or has a cycle.
+func (m *Manager) Layers(name string) ([][]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(name)
+	if err != nil {
+		return nil, err
+	}
+	layers, err := spine.TopologicalGenerations(g)
+	if err != nil {
+		return nil, err
+	}
+	if layers == nil {
+		layers = make([][]string, 0)
+	}
+	return layers, nil
+}
+
+// SlowestPaths returns the n destinations reachable from src whose cheapest
+// (shortest weighted) path is the most expensive, ordered by descending cost
+// and then by ID. With latencies as edge weights these are the slowest routes
+// out of src. If n <= 0, every reachable destination is returned.
+func (m *Manager) SlowestPaths(graph, src string, n int) ([]PathResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(graph)
+	if err != nil {
+		return nil, err
+	}
+	if !g.HasNode(src) {
+		return nil, fmt.Errorf("%w: %q", ErrNodeNotFound, src)
+	}
+
+	// One Dijkstra run: each destination's path follows the predecessor tree
+	// back to src.
+	prev := make(map[string]string)
+	for _, e := range spine.ShortestPathTree(g, src) {
+		prev[e[1]] = e[0]
+	}
+	results := make([]PathResult, 0, len(prev))
+	for to := range prev {
+		res := PathResult{To: to, Path: []string{to}}
+		for cur := to; cur != src; cur = prev[cur] {
+			e, _ := g.GetEdge(prev[cur], cur)
+			res.Cost += e.Weight
+			res.Path = append(res.Path, prev[cur])
+		}
+		for i, j := 0, len(res.Path)-1; i < j; i, j = i+1, j-1 {
+			res.Path[i], res.Path[j] = res.Path[j], res.Path[i]
+		}
+		results = append(results, res)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Cost != results[j].Cost {
+			return results[i].Cost > results[j].Cost
+		}
+		return results[i].To < results[j].To
+	})
+	if n > 0 && n < len(results) {
+		results = results[:n]
+	}
+	return results, nil
+}
+
+// nodeResult builds the read representation of a node with projected metadata.
+func nodeResult(g *spine.Graph[NodeData, EdgeData], id string, keySet map[string]bool) NodeResult {
+	n, _ := g.GetNode(id)
+	return NodeResult{
+		ID:        id,
+		Label:     n.Data.Label,
+		Status:    n.Data.Status,
+		Meta:      projectMeta(g.NodeMeta(id), keySet),
+		InDegree:  len(g.InEdges(id)),
+		OutDegree: len(g.OutEdges(id)),
+	}
+}
+
 // makeKeySet builds a set from a slice. nil means "all keys".
 func makeKeySet(keys []string) map[string]bool {
 	if len(keys) == 0 {
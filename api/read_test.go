@@ -1,6 +1,8 @@
 package api
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -131,6 +133,44 @@ func TestReadIncludeEdges(t *testing.T) {
 	}
 }
 
+func TestReadIncludeEdgesUndirected(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.OpenWithDirected("u", false)
+	mgr.Upsert(UpsertRequest{
+		Graph: "u",
+		Edges: []UpsertEdge{
+			{From: "b", To: "a"},
+			{From: "b", To: "c"},
+		},
+	})
+	if err := mgr.Save("u"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reload from disk, as an imported undirected graph would be.
+	mgr, _ = NewManager(dir)
+	if _, err := mgr.Open("u"); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := mgr.ReadNodes(ReadNodesRequest{Graph: "u", IncludeEdges: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Edges) != 2 {
+		t.Fatalf("expected each undirected edge once, got %+v", resp.Edges)
+	}
+	want := [][2]string{{"a", "b"}, {"b", "c"}}
+	for i, e := range resp.Edges {
+		if e.From != want[i][0] || e.To != want[i][1] {
+			t.Errorf("edge %d: expected %v, got %s->%s", i, want[i], e.From, e.To)
+		}
+		if !e.Undirected {
+			t.Errorf("edge %d: expected undirected flag", i)
+		}
+	}
+}
+
 func TestReadDegrees(t *testing.T) {
 	mgr := setupReadGraph(t)
 	resp, _ := mgr.ReadNodes(ReadNodesRequest{
@@ -149,6 +189,51 @@ func TestReadDegrees(t *testing.T) {
 	}
 }
 
+func TestReadReachCounts(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("deps")
+	// The visualizer's "dependencies" template: a package DAG rooted at app.
+	var edges []UpsertEdge
+	for _, e := range [][2]string{
+		{"app", "api"}, {"app", "web"}, {"api", "auth"}, {"api", "db"},
+		{"web", "ui"}, {"web", "router"}, {"auth", "crypto"}, {"auth", "logger"},
+		{"db", "logger"}, {"db", "config"}, {"ui", "config"}, {"router", "utils"},
+		{"router", "logger"},
+	} {
+		edges = append(edges, UpsertEdge{From: e[0], To: e[1]})
+	}
+	var nodes []UpsertNode
+	for _, id := range []string{"app", "api", "web", "auth", "db", "ui", "router", "crypto", "logger", "config", "utils"} {
+		nodes = append(nodes, UpsertNode{ID: id})
+	}
+	mgr.Upsert(UpsertRequest{Graph: "deps", Nodes: nodes, Edges: edges})
+
+	resp, _ := mgr.ReadNodes(ReadNodesRequest{Graph: "deps", IDs: []string{"app", "db", "logger"}, IncludeReachCounts: true})
+	counts := make(map[string][2]int)
+	for _, n := range resp.Nodes {
+		if n.AncestorCount == nil || n.DescendantCount == nil {
+			t.Fatalf("expected reach counts on %s", n.ID)
+		}
+		counts[n.ID] = [2]int{*n.AncestorCount, *n.DescendantCount}
+	}
+	// The root reaches every other node.
+	if counts["app"] != [2]int{0, 10} {
+		t.Errorf("app: expected 0 ancestors and 10 descendants, got %v", counts["app"])
+	}
+	if counts["db"] != [2]int{2, 2} {
+		t.Errorf("db: expected 2 ancestors and 2 descendants, got %v", counts["db"])
+	}
+	if counts["logger"] != [2]int{6, 0} {
+		t.Errorf("logger: expected 6 ancestors and 0 descendants, got %v", counts["logger"])
+	}
+
+	resp, _ = mgr.ReadNodes(ReadNodesRequest{Graph: "deps", IDs: []string{"app"}})
+	if resp.Nodes[0].DescendantCount != nil {
+		t.Error("expected no reach counts unless requested")
+	}
+}
+
 func TestReadEdgeKeyProjection(t *testing.T) {
 	dir := tempDir(t)
 	mgr, _ := NewManager(dir)
@@ -207,3 +292,288 @@ func TestReadNotOpen(t *testing.T) {
 		t.Error("expected error for non-open graph")
 	}
 }
+
+func TestNodesByKind(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("fs")
+	// Mirrors the shape produced by the visualizer's directory loader.
+	mgr.Upsert(UpsertRequest{
+		Graph: "fs",
+		Nodes: []UpsertNode{
+			{ID: "root", Label: "root/", Meta: map[string]any{"type": "directory"}},
+			{ID: "src", Label: "src/", Meta: map[string]any{"type": "directory"}},
+			{ID: "src/main.go", Label: "main.go", Meta: map[string]any{"type": "file", "size": float64(120)}},
+			{ID: "README.md", Label: "README.md", Meta: map[string]any{"type": "file", "size": float64(40)}},
+		},
+		Edges: []UpsertEdge{
+			{From: "root", To: "src"},
+			{From: "root", To: "README.md"},
+			{From: "src", To: "src/main.go"},
+		},
+	})
+
+	dirs, err := mgr.NodesByKind("fs", "type", "directory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != 2 || dirs[0].ID != "root" || dirs[1].ID != "src" {
+		t.Fatalf("expected [root src], got %+v", dirs)
+	}
+	for _, n := range dirs {
+		if n.Meta["type"] != "directory" {
+			t.Errorf("node %s: expected type=directory, got %v", n.ID, n.Meta["type"])
+		}
+	}
+
+	files, _ := mgr.NodesByKind("fs", "type", "file")
+	if len(files) != 2 {
+		t.Errorf("expected 2 files, got %d", len(files))
+	}
+
+	none, _ := mgr.NodesByKind("fs", "type", "symlink")
+	if len(none) != 0 {
+		t.Errorf("expected no symlinks, got %d", len(none))
+	}
+}
+
+func TestReadNodesIndexed(t *testing.T) {
+	mgr := setupReadGraph(t)
+	filters := [][]MetaFilter{
+		{{Key: "tag", Op: "eq", Value: "core"}},
+		{{Key: "priority", Op: "eq", Value: float64(5)}},
+		{{Key: "priority", Op: "eq", Value: "8"}},
+		{{Key: "priority", Op: "eq", Value: float64(10)}, {Key: "status", Op: "eq", Value: "pending"}},
+	}
+	ids := func() [][]string {
+		var out [][]string
+		for _, f := range filters {
+			resp, err := mgr.ReadNodes(ReadNodesRequest{Graph: "r", Filters: f})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, n := range resp.Nodes {
+				got = append(got, n.ID)
+			}
+			out = append(out, got)
+		}
+		return out
+	}
+	scanned := ids()
+	if err := mgr.IndexMetaKey("r", "tag"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.IndexMetaKey("r", "priority"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.IndexMetaKey("nope", "tag"); err == nil {
+		t.Error("expected error for non-open graph")
+	}
+	indexed := ids()
+	for i := range filters {
+		if strings.Join(indexed[i], ",") != strings.Join(scanned[i], ",") {
+			t.Errorf("filter %v: index returned %v, scan %v", filters[i], indexed[i], scanned[i])
+		}
+	}
+
+	// The index follows later mutations and survives undo.
+	mgr.Upsert(UpsertRequest{Graph: "r", Nodes: []UpsertNode{{ID: "d", Meta: map[string]any{"tag": "core"}}}})
+	resp, _ := mgr.ReadNodes(ReadNodesRequest{Graph: "r", Filters: filters[0]})
+	if len(resp.Nodes) != 2 || resp.Nodes[1].ID != "d" {
+		t.Fatalf("expected [a d] after upsert, got %+v", resp.Nodes)
+	}
+	if err := mgr.Undo("r"); err != nil {
+		t.Fatal(err)
+	}
+	resp, _ = mgr.ReadNodes(ReadNodesRequest{Graph: "r", Filters: filters[0]})
+	if len(resp.Nodes) != 1 || resp.Nodes[0].ID != "a" {
+		t.Fatalf("expected [a] after undo, got %+v", resp.Nodes)
+	}
+	mgr.Upsert(UpsertRequest{Graph: "r", Nodes: []UpsertNode{{ID: "c", Meta: map[string]any{"tag": "core"}}}})
+	resp, _ = mgr.ReadNodes(ReadNodesRequest{Graph: "r", Filters: filters[0]})
+	if len(resp.Nodes) != 2 || resp.Nodes[1].ID != "c" {
+		t.Fatalf("expected writes after undo to be indexed, got %+v", resp.Nodes)
+	}
+	if kinds, _ := mgr.NodesByKind("r", "tag", "core"); len(kinds) != 2 || kinds[1].ID != "c" {
+		t.Errorf("expected NodesByKind to find c, got %+v", kinds)
+	}
+}
+
+func TestNodesByKindNotOpen(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	if _, err := mgr.NodesByKind("nope", "type", "file"); err == nil {
+		t.Error("expected error for non-open graph")
+	}
+}
+
+func TestCountByMetaKey(t *testing.T) {
+	mgr := setupReadGraph(t)
+	with, without, err := mgr.CountByMetaKey("r", "tag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if with != 2 || without != 2 {
+		t.Errorf("expected 2 with tag and 2 without, got %d and %d", with, without)
+	}
+
+	with, without, _ = mgr.CountByMetaKey("r", "missing")
+	if with != 0 || without != 4 {
+		t.Errorf("expected 0/4 for unknown key, got %d/%d", with, without)
+	}
+
+	if _, _, err := mgr.CountByMetaKey("nope", "tag"); err == nil {
+		t.Error("expected error for non-open graph")
+	}
+}
+
+func TestOutline(t *testing.T) {
+	mgr := setupReadGraph(t)
+	mgr.Upsert(UpsertRequest{Graph: "r", Nodes: []UpsertNode{{ID: "e"}}})
+
+	out, err := mgr.Outline("r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		outlineLegend,
+		"a(done) -> b, c",
+		"b(pending)",
+		"c(running) -> d",
+		"d(done)",
+		"e",
+	}, "\n")
+	if out != want {
+		t.Errorf("unexpected outline:\n%s\nwant:\n%s", out, want)
+	}
+
+	if _, err := mgr.Outline("nope"); err == nil {
+		t.Error("expected error for non-open graph")
+	}
+}
+
+func TestLayers(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("plan")
+	mgr.Upsert(UpsertRequest{
+		Graph: "plan",
+		Nodes: []UpsertNode{
+			{ID: "design"}, {ID: "backend"}, {ID: "frontend"}, {ID: "test"}, {ID: "deploy"},
+		},
+		Edges: []UpsertEdge{
+			{From: "design", To: "backend"},
+			{From: "design", To: "frontend"},
+			{From: "backend", To: "test"},
+			{From: "frontend", To: "test"},
+			{From: "test", To: "deploy"},
+		},
+	})
+
+	layers, err := mgr.Layers("plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layers) != 4 {
+		t.Fatalf("expected 4 layers, got %v", layers)
+	}
+	if len(layers[0]) != 1 || layers[0][0] != "design" {
+		t.Errorf("expected design at level 0, got %v", layers[0])
+	}
+	if len(layers[1]) != 2 {
+		t.Errorf("expected backend and frontend at level 1, got %v", layers[1])
+	}
+	if last := layers[len(layers)-1]; len(last) != 1 || last[0] != "deploy" {
+		t.Errorf("expected deploy at the deepest level, got %v", last)
+	}
+
+	mgr.Upsert(UpsertRequest{Graph: "plan", Edges: []UpsertEdge{{From: "deploy", To: "design"}}})
+	if _, err := mgr.Layers("plan"); err == nil {
+		t.Error("expected error for cyclic plan")
+	}
+}
+
+func TestSlowestPaths(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	if _, err := mgr.OpenFromTemplate("svc", "microservices"); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := mgr.SlowestPaths("svc", "gateway", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		to   string
+		cost float64
+	}{{"payments", 40}, {"notify", 32}, {"inventory", 25}}
+	if len(paths) != len(want) {
+		t.Fatalf("expected %d paths, got %+v", len(want), paths)
+	}
+	for i, w := range want {
+		if paths[i].To != w.to || paths[i].Cost != w.cost {
+			t.Errorf("path %d: expected %s at %v, got %s at %v", i, w.to, w.cost, paths[i].To, paths[i].Cost)
+		}
+	}
+	if got := strings.Join(paths[0].Path, ">"); got != "gateway>orders>payments" {
+		t.Errorf("unexpected slowest path: %s", got)
+	}
+
+	all, _ := mgr.SlowestPaths("svc", "gateway", 0)
+	if len(all) != 8 {
+		t.Errorf("expected 8 reachable destinations, got %d", len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Cost > all[i-1].Cost {
+			t.Errorf("expected descending cost, got %v after %v", all[i].Cost, all[i-1].Cost)
+		}
+	}
+
+	if _, err := mgr.SlowestPaths("svc", "missing", 3); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("expected ErrNodeNotFound, got %v", err)
+	}
+}
+
+func TestReadEdges(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	if _, err := mgr.OpenFromTemplate("svc", "microservices"); err != nil {
+		t.Fatal(err)
+	}
+	slow := []MetaFilter{{Key: "weight", Op: "gt", Value: 10}}
+
+	resp, err := mgr.ReadEdges(ReadEdgesRequest{Graph: "svc", Filters: slow, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Total != 5 || !resp.HasMore || len(resp.Edges) != 2 {
+		t.Fatalf("expected first page of 2 out of 5, got %+v", resp)
+	}
+	if e := resp.Edges[0]; e.From != "gateway" || e.To != "orders" || e.Weight != 15 {
+		t.Errorf("unexpected first edge: %+v", e)
+	}
+
+	resp, _ = mgr.ReadEdges(ReadEdgesRequest{Graph: "svc", Filters: slow, Offset: 4, Limit: 2})
+	if resp.HasMore || len(resp.Edges) != 1 || resp.Edges[0].From != "users" || resp.Edges[0].To != "notify" {
+		t.Errorf("expected last page with users->notify, got %+v", resp)
+	}
+
+	// Metadata filters and key projection.
+	mgr.Upsert(UpsertRequest{Graph: "svc", Edges: []UpsertEdge{
+		{From: "orders", To: "payments", Meta: map[string]any{"protocol": "grpc", "timeout_ms": 500}},
+	}})
+	resp, _ = mgr.ReadEdges(ReadEdgesRequest{
+		Graph:   "svc",
+		Filters: append(slow, MetaFilter{Key: "protocol", Op: "eq", Value: "grpc"}),
+		Keys:    []string{"protocol"},
+	})
+	if resp.Total != 1 || len(resp.Edges[0].Meta) != 1 || resp.Edges[0].Meta["protocol"] != "grpc" {
+		t.Errorf("expected orders->payments with projected metadata, got %+v", resp)
+	}
+
+	if _, err := mgr.ReadEdges(ReadEdgesRequest{Graph: "missing"}); !errors.Is(err, ErrGraphNotOpen) {
+		t.Errorf("expected ErrGraphNotOpen, got %v", err)
+	}
+}
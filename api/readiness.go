@@ -0,0 +1,104 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/imran31415/spine"
+)
+
+// ReadinessMetaKey is the node metadata key that overrides the graph's
+// readiness policy for a single node. Its value is "all", "any", or a number
+// n meaning a quorum of n done dependencies.
+const ReadinessMetaKey = "readiness"
+
+// ReadinessPolicy decides whether a pending node with total dependencies, of
+// which done are "done", should be promoted to "ready". total is always > 0.
+type ReadinessPolicy func(done, total int) bool
+
+// AllDeps readies a node once every dependency is done. It is the default.
+func AllDeps(done, total int) bool { return done == total }
+
+// AnyDep readies a node once at least one dependency is done.
+func AnyDep(done, total int) bool { return done > 0 }
+
+// QuorumDeps readies a node once at least n dependencies are done, or all of
+// them if it has fewer than n.
+func QuorumDeps(n int) ReadinessPolicy {
+	return func(done, total int) bool {
+		return done >= n || done == total
+	}
+}
+
+// SetReadinessPolicy sets the policy used when auto-promoting nodes of the
+// named graph. A nil policy restores AllDeps. Nodes may override it with the
+// ReadinessMetaKey metadata key.
+func (m *Manager) SetReadinessPolicy(graph string, p ReadinessPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if p == nil {
+		delete(m.policies, graph)
+		return
+	}
+	m.policies[graph] = p
+}
+
+// ComputeReadyWithPolicy promotes every pending node that has at least one
+// dependency (in-edge) and satisfies its readiness policy to "ready". A
+// node's ReadinessMetaKey metadata takes precedence over p; a nil p means
+// AllDeps. It returns the promoted IDs in sorted order.
+func ComputeReadyWithPolicy(g *spine.Graph[NodeData, EdgeData], p ReadinessPolicy) []string {
+	if p == nil {
+		p = AllDeps
+	}
+	var promoted []string
+	for _, n := range g.Nodes() {
+		if n.Data.Status != "pending" {
+			continue
+		}
+		inEdges := g.InEdges(n.ID)
+		if len(inEdges) == 0 {
+			continue
+		}
+		done := 0
+		for _, e := range inEdges {
+			dep, ok := g.GetNode(e.From)
+			if ok && dep.Data.Status == "done" {
+				done++
+			}
+		}
+		policy := p
+		if np := nodePolicy(g, n.ID); np != nil {
+			policy = np
+		}
+		if policy(done, len(inEdges)) {
+			nd := n.Data
+			nd.Status = "ready"
+			g.AddNode(n.ID, nd)
+			promoted = append(promoted, n.ID)
+		}
+	}
+	sort.Strings(promoted)
+	return promoted
+}
+
+// nodePolicy returns the policy named by a node's ReadinessMetaKey metadata,
+// or nil if the key is unset or holds an unrecognised value.
+func nodePolicy(g *spine.Graph[NodeData, EdgeData], id string) ReadinessPolicy {
+	if g.NodeMetaCount(id) == 0 {
+		return nil
+	}
+	v, ok := g.NodeMeta(id).Get(ReadinessMetaKey)
+	if !ok {
+		return nil
+	}
+	switch v {
+	case "all":
+		return AllDeps
+	case "any":
+		return AnyDep
+	}
+	if n, ok := toFloat64(v); ok && n >= 1 {
+		return QuorumDeps(int(n))
+	}
+	return nil
+}
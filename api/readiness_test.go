@@ -0,0 +1,91 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/imran31415/spine"
+)
+
+func fanInPlan(t *testing.T, mgr *Manager) {
+	t.Helper()
+	mgr.Open("p")
+	mgr.Upsert(UpsertRequest{
+		Graph: "p",
+		Nodes: []UpsertNode{
+			{ID: "a", Status: "running"},
+			{ID: "b", Status: "running"},
+			{ID: "c", Status: "running"},
+			{ID: "join", Status: "pending"},
+		},
+		Edges: []UpsertEdge{
+			{From: "a", To: "join"},
+			{From: "b", To: "join"},
+			{From: "c", To: "join"},
+		},
+	})
+}
+
+func TestReadinessPolicyAnyDep(t *testing.T) {
+	mgr, _ := NewManager(tempDir(t))
+	fanInPlan(t, mgr)
+	mgr.SetReadinessPolicy("p", AnyDep)
+
+	res, err := mgr.Transition(TransitionRequest{Graph: "p", ID: "a", Status: "done"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.NewlyReady) != 1 || res.NewlyReady[0] != "join" {
+		t.Fatalf("expected join ready after one dep, got %v", res.NewlyReady)
+	}
+}
+
+func TestReadinessPolicyDefaultAllDeps(t *testing.T) {
+	mgr, _ := NewManager(tempDir(t))
+	fanInPlan(t, mgr)
+
+	res, _ := mgr.Transition(TransitionRequest{Graph: "p", ID: "a", Status: "done"})
+	if len(res.NewlyReady) != 0 {
+		t.Fatalf("expected nothing ready under AllDeps, got %v", res.NewlyReady)
+	}
+}
+
+func TestReadinessPolicyNodeMetaQuorum(t *testing.T) {
+	mgr, _ := NewManager(tempDir(t))
+	fanInPlan(t, mgr)
+	mgr.Upsert(UpsertRequest{
+		Graph: "p",
+		Nodes: []UpsertNode{{ID: "join", Meta: map[string]any{ReadinessMetaKey: float64(2)}}},
+	})
+
+	res, _ := mgr.Transition(TransitionRequest{Graph: "p", ID: "a", Status: "done"})
+	if len(res.NewlyReady) != 0 {
+		t.Fatalf("expected quorum of 2 unmet, got %v", res.NewlyReady)
+	}
+	res, _ = mgr.Transition(TransitionRequest{Graph: "p", ID: "b", Status: "done"})
+	if len(res.NewlyReady) != 1 || res.NewlyReady[0] != "join" {
+		t.Fatalf("expected join ready at quorum, got %v", res.NewlyReady)
+	}
+}
+
+func TestQuorumDepsCapped(t *testing.T) {
+	p := QuorumDeps(5)
+	if p(1, 2) {
+		t.Error("expected quorum unmet with 1 of 2 done")
+	}
+	if !p(2, 2) {
+		t.Error("expected quorum capped at total deps")
+	}
+}
+
+func TestComputeReadyNodeOverride(t *testing.T) {
+	g := spine.NewGraph[NodeData, EdgeData](true)
+	g.AddNode("a", NodeData{Status: "done"})
+	g.AddNode("b", NodeData{Status: "running"})
+	g.AddNodeWithMeta("c", NodeData{Status: "pending"}, map[string]any{ReadinessMetaKey: "any"})
+	g.AddEdge("a", "c", EdgeData{}, 0)
+	g.AddEdge("b", "c", EdgeData{}, 0)
+
+	if ready := ComputeReady(g); len(ready) != 1 || ready[0] != "c" {
+		t.Fatalf("expected node-level any policy to ready c, got %v", ready)
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/imran31415/spine"
+)
+
+// labelSchemasKey is the graph config key holding per-label node schemas.
+const labelSchemasKey = "label_schemas"
+
+// SetLabelSchema registers the metadata schema that nodes with the given
+// label must satisfy, replacing any previous schema for that label. A nil
+// schema removes it. Label schemas are stored in the graph's config, so they
+// are persisted on Save.
+func (m *Manager) SetLabelSchema(graph, label string, schema spine.Schema) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(graph)
+	if err != nil {
+		return err
+	}
+	schemas, err := labelSchemas(g)
+	if err != nil {
+		return err
+	}
+	undo, rollback := m.rollbackSnapshot(g)
+	mark := m.markLocked(graph)
+	m.pushUndoLocked(graph, undo)
+	if schema == nil {
+		delete(schemas, label)
+	} else {
+		schemas[label] = schema
+	}
+	if len(schemas) == 0 {
+		g.GraphMeta().Delete(labelSchemasKey)
+	} else {
+		g.GraphMeta().Set(labelSchemasKey, schemas)
+	}
+	m.bumpVersion(graph)
+	if err := m.logLocked(opSetLabelSchema, graph, labelSchemaRequest{Label: label, Schema: schema}); err != nil {
+		m.abortLocked(graph, g, rollback, mark)
+		return err
+	}
+	return nil
+}
+
+// LabelSchemas returns the per-label schemas registered on the named graph.
+func (m *Manager) LabelSchemas(graph string) (map[string]spine.Schema, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(graph)
+	if err != nil {
+		return nil, err
+	}
+	return labelSchemas(g)
+}
+
+// ValidateGraph checks the internal consistency of the named graph and
+// validates each node's metadata against the schema registered for its
+// label. Schema violations are reported with type "schema".
+func (m *Manager) ValidateGraph(graph string) (spine.ValidationResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(graph)
+	if err != nil {
+		return spine.ValidationResult{}, err
+	}
+	res := spine.Validate(g)
+	schemaErrs, err := labelSchemaErrors(g)
+	if err != nil {
+		return spine.ValidationResult{}, err
+	}
+	res.Errors = append(res.Errors, schemaErrs...)
+	sort.Slice(res.Errors, func(i, j int) bool {
+		if res.Errors[i].Type != res.Errors[j].Type {
+			return res.Errors[i].Type < res.Errors[j].Type
+		}
+		return res.Errors[i].Message < res.Errors[j].Message
+	})
+	res.Valid = len(res.Errors) == 0
+	return res, nil
+}
+
+// labelSchemaErrors validates each node's metadata against the schema
+// registered for its label.
+func labelSchemaErrors(g *spine.Graph[NodeData, EdgeData]) ([]spine.ValidationError, error) {
+	schemas, err := labelSchemas(g)
+	if err != nil {
+		return nil, err
+	}
+	var errs []spine.ValidationError
+	for _, n := range g.Nodes() {
+		schema, ok := schemas[n.Data.Label]
+		if !ok {
+			continue
+		}
+		store := spine.NewStore()
+		if g.NodeMetaCount(n.ID) > 0 {
+			store = g.NodeMeta(n.ID).Copy()
+		}
+		store.SetSchema(schema)
+		for _, verr := range store.Validate() {
+			errs = append(errs, spine.ValidationError{
+				Type:    "schema",
+				Message: fmt.Sprintf("node %q (%s): %v", n.ID, n.Data.Label, verr),
+				NodeID:  n.ID,
+			})
+		}
+	}
+	return errs, nil
+}
+
+// labelSchemas decodes the label schemas from g's config. The value is a
+// typed map in memory but a plain JSON object after loading from disk, so it
+// is normalised through JSON either way.
+func labelSchemas(g *spine.Graph[NodeData, EdgeData]) (map[string]spine.Schema, error) {
+	schemas := make(map[string]spine.Schema)
+	raw, ok := g.GraphMeta().Get(labelSchemasKey)
+	if !ok {
+		return schemas, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("label schemas: %w", err)
+	}
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		return nil, fmt.Errorf("label schemas: %w", err)
+	}
+	return schemas, nil
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/imran31415/spine"
+)
+
+func TestLabelSchemaValidation(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("fs")
+	mgr.Upsert(UpsertRequest{
+		Graph: "fs",
+		Nodes: []UpsertNode{
+			{ID: "/", Label: "dir"},
+			{ID: "/a.go", Label: "file", Meta: map[string]any{"size": 120}},
+			{ID: "/b.go", Label: "file"},
+		},
+	})
+	err := mgr.SetLabelSchema("fs", "file", spine.Schema{
+		"size": {Type: spine.FieldAny, Required: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := mgr.ValidateGraph("fs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Valid || len(res.Errors) != 1 {
+		t.Fatalf("expected 1 schema error, got %+v", res)
+	}
+	if e := res.Errors[0]; e.Type != "schema" || e.NodeID != "/b.go" {
+		t.Errorf("expected schema error on /b.go, got %+v", e)
+	}
+
+	// Schemas persist with the graph.
+	mgr.Save("fs")
+	reloaded, _ := NewManager(dir)
+	reloaded.Open("fs")
+	schemas, err := reloaded.LabelSchemas("fs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def, ok := schemas["file"]["size"]; !ok || !def.Required {
+		t.Fatalf("expected file schema to survive reload, got %v", schemas)
+	}
+	if res, _ := reloaded.ValidateGraph("fs"); len(res.Errors) != 1 {
+		t.Errorf("expected 1 schema error after reload, got %+v", res)
+	}
+
+	// Removing the schema clears the error.
+	mgr.SetLabelSchema("fs", "file", nil)
+	if res, _ := mgr.ValidateGraph("fs"); !res.Valid {
+		t.Errorf("expected valid graph after removing schema, got %+v", res)
+	}
+}
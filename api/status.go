@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/imran31415/spine"
+)
+
+// StatusMachineKey is the graph config key holding a custom status machine.
+const StatusMachineKey = "status_machine"
+
+// StatusMachine defines the statuses a graph's nodes may take and the
+// transitions allowed between them. Transitions maps each status to the
+// statuses it may move to; the "" entry lists the statuses a node without
+// one may enter. Colors optionally maps statuses to display colors.
+type StatusMachine struct {
+	Transitions map[string][]string `json:"transitions"`
+	Colors      map[string]string   `json:"colors,omitempty"`
+}
+
+// DefaultStatusMachine returns the built-in task lifecycle used by graphs
+// without a custom status machine.
+func DefaultStatusMachine() StatusMachine {
+	sm := StatusMachine{Transitions: make(map[string][]string, len(validTransitions))}
+	for from, tos := range validTransitions {
+		for to := range tos {
+			sm.Transitions[from] = append(sm.Transitions[from], to)
+		}
+		sort.Strings(sm.Transitions[from])
+	}
+	return sm
+}
+
+// Allows reports whether a node may move from one status to another.
+func (sm StatusMachine) Allows(from, to string) bool {
+	for _, s := range sm.Transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// path returns the shortest sequence of statuses leading from one status to
+// another, excluding from, and false if to cannot be reached. Ties are
+// broken by status name so the result is deterministic.
+func (sm StatusMachine) path(from, to string) ([]string, bool) {
+	if from == to {
+		return nil, true
+	}
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		next := append([]string(nil), sm.Transitions[cur]...)
+		sort.Strings(next)
+		for _, s := range next {
+			if _, seen := prev[s]; seen {
+				continue
+			}
+			prev[s] = cur
+			if s != to {
+				queue = append(queue, s)
+				continue
+			}
+			var steps []string
+			for ; s != from; s = prev[s] {
+				steps = append(steps, s)
+			}
+			slices.Reverse(steps)
+			return steps, true
+		}
+	}
+	return nil, false
+}
+
+// Valid reports whether status appears anywhere in the machine.
+func (sm StatusMachine) Valid(status string) bool {
+	for _, s := range sm.Statuses() {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Statuses returns every non-empty status named by the machine, sorted.
+func (sm StatusMachine) Statuses() []string {
+	seen := make(map[string]bool)
+	for from, tos := range sm.Transitions {
+		seen[from] = true
+		for _, to := range tos {
+			seen[to] = true
+		}
+	}
+	delete(seen, "")
+	out := make([]string, 0, len(seen))
+	for s := range seen {
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// StatusMachineFor returns the status machine stored in g's config, or the
+// default machine if none is set. The value is a typed struct in memory but
+// a plain JSON object after loading from disk, so it is normalised through
+// JSON either way.
+func StatusMachineFor(g *spine.Graph[NodeData, EdgeData]) (StatusMachine, error) {
+	raw, ok := g.GraphMeta().Get(StatusMachineKey)
+	if !ok {
+		return DefaultStatusMachine(), nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return StatusMachine{}, fmt.Errorf("status machine: %w", err)
+	}
+	var sm StatusMachine
+	if err := json.Unmarshal(data, &sm); err != nil {
+		return StatusMachine{}, fmt.Errorf("status machine: %w", err)
+	}
+	return sm, nil
+}
+
+// SetStatusMachine stores sm in g's config, replacing the default status
+// machine. A nil sm restores the default.
+func SetStatusMachine(g *spine.Graph[NodeData, EdgeData], sm *StatusMachine) {
+	if sm == nil {
+		g.GraphMeta().Delete(StatusMachineKey)
+		return
+	}
+	g.GraphMeta().Set(StatusMachineKey, *sm)
+}
+
+// SetStatusMachine sets the status machine that Transition enforces on the
+// named graph. It is stored in the graph's config, so it is persisted on
+// Save. A nil sm restores the default.
+func (m *Manager) SetStatusMachine(graph string, sm *StatusMachine) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(graph)
+	if err != nil {
+		return err
+	}
+	undo, rollback := m.rollbackSnapshot(g)
+	mark := m.markLocked(graph)
+	m.pushUndoLocked(graph, undo)
+	SetStatusMachine(g, sm)
+	m.bumpVersion(graph)
+	if err := m.logLocked(opSetStatusMachine, graph, statusMachineRequest{Machine: sm}); err != nil {
+		m.abortLocked(graph, g, rollback, mark)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStatusMachineCustomStatus(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("sm")
+	mgr.Upsert(UpsertRequest{
+		Graph: "sm",
+		Nodes: []UpsertNode{{ID: "a", Status: "pending"}, {ID: "b", Status: "pending"}},
+	})
+
+	err := mgr.SetStatusMachine("sm", &StatusMachine{
+		Transitions: map[string][]string{
+			"pending": {"review"},
+			"review":  {"blocked", "done"},
+			"blocked": {"review"},
+		},
+		Colors: map[string]string{"review": "#a78bfa"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := mgr.Transition(TransitionRequest{Graph: "sm", ID: "a", Status: "review"}); err != nil {
+		t.Fatalf("expected pending -> review to be allowed: %v", err)
+	}
+	if _, err := mgr.Transition(TransitionRequest{Graph: "sm", ID: "a", Status: "running"}); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected undefined status to be rejected, got %v", err)
+	}
+	// The default pending -> ready is not part of the custom machine.
+	if _, err := mgr.Transition(TransitionRequest{Graph: "sm", ID: "b", Status: "ready"}); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected pending -> ready to be rejected, got %v", err)
+	}
+
+	// The machine survives a save and reload.
+	if err := mgr.Save("sm"); err != nil {
+		t.Fatal(err)
+	}
+	fresh, _ := NewManager(dir)
+	fresh.Open("sm")
+	if _, err := fresh.Transition(TransitionRequest{Graph: "sm", ID: "a", Status: "blocked"}); err != nil {
+		t.Fatalf("expected review -> blocked after reload: %v", err)
+	}
+	g, _ := fresh.OpenGraph("sm")
+	sm, err := StatusMachineFor(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sm.Colors["review"] != "#a78bfa" {
+		t.Errorf("expected review color to persist, got %v", sm.Colors)
+	}
+	if got := sm.Statuses(); len(got) != 4 || got[0] != "blocked" {
+		t.Errorf("unexpected statuses %v", got)
+	}
+
+	// Clearing the machine restores the defaults.
+	fresh.SetStatusMachine("sm", nil)
+	if _, err := fresh.Transition(TransitionRequest{Graph: "sm", ID: "b", Status: "ready"}); err != nil {
+		t.Errorf("expected default pending -> ready after reset: %v", err)
+	}
+}
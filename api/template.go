@@ -0,0 +1,153 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/imran31415/spine"
+)
+
+// TemplateBuilder constructs a fresh starter graph.
+type TemplateBuilder func() *spine.Graph[NodeData, EdgeData]
+
+var (
+	templatesMu sync.RWMutex
+	templates   = map[string]TemplateBuilder{
+		"workflow":      workflowTemplate,
+		"microservices": microservicesTemplate,
+	}
+)
+
+// RegisterTemplate adds or replaces a named template builder.
+func RegisterTemplate(name string, build TemplateBuilder) {
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	templates[name] = build
+}
+
+// TemplateNames returns the registered template names in sorted order.
+func TemplateNames() []string {
+	templatesMu.RLock()
+	defer templatesMu.RUnlock()
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuildFromTemplate returns a new graph built by the named template.
+func BuildFromTemplate(name string) (*spine.Graph[NodeData, EdgeData], error) {
+	templatesMu.RLock()
+	build, ok := templates[name]
+	templatesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown template %q", name)
+	}
+	return build(), nil
+}
+
+// OpenFromTemplate creates a new in-memory graph named name from the given
+// template. It fails if the graph is already open or exists on disk.
+func (m *Manager) OpenFromTemplate(name, template string) (*GraphInfo, error) {
+	g, err := BuildFromTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.graphs[name]; ok {
+		return nil, fmt.Errorf("graph %q already open", name)
+	}
+	path, err := m.graphPath(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("graph %q already exists", name)
+	}
+	m.graphs[name] = g
+	return m.graphInfo(name, g), nil
+}
+
+// workflowTemplate is a fan-out/fan-in CI/CD pipeline.
+func workflowTemplate() *spine.Graph[NodeData, EdgeData] {
+	g := spine.NewGraph[NodeData, EdgeData](true)
+	nodes := []struct {
+		id, label string
+		meta      map[string]any
+	}{
+		{"push", "Push", map[string]any{"trigger": "on_push", "branch": "main"}},
+		{"lint", "Lint", map[string]any{"tool": "golangci-lint", "config": ".golangci.yml"}},
+		{"test", "Test", map[string]any{"framework": "go test", "flags": "-race -v", "min_coverage": "80%"}},
+		{"build", "Build", map[string]any{"output": "./bin/app", "os": "linux", "arch": "amd64"}},
+		{"scan", "Security Scan", map[string]any{"tool": "gosec", "severity": "high"}},
+		{"coverage", "Coverage", nil},
+		{"docker", "Docker", map[string]any{"base_image": "golang:1.22-alpine", "registry": "ghcr.io", "tag": "latest"}},
+		{"staging", "Staging", nil},
+		{"deploy", "Deploy", map[string]any{"environment": "production", "region": "us-east-1", "replicas": 3}},
+	}
+	for _, n := range nodes {
+		status := "pending"
+		if n.id == "push" {
+			status = "ready"
+		}
+		g.AddNodeWithMeta(n.id, NodeData{Label: n.label, Status: status}, n.meta)
+	}
+	edges := [][2]string{
+		{"push", "lint"}, {"push", "test"}, {"push", "build"},
+		{"lint", "scan"}, {"test", "coverage"}, {"build", "docker"},
+		{"scan", "staging"}, {"coverage", "staging"}, {"docker", "staging"},
+		{"staging", "deploy"},
+	}
+	for _, e := range edges {
+		g.AddEdge(e[0], e[1], EdgeData{}, 1)
+	}
+	return g
+}
+
+// microservicesTemplate is a service call graph with latencies (in ms) as
+// edge weights.
+func microservicesTemplate() *spine.Graph[NodeData, EdgeData] {
+	g := spine.NewGraph[NodeData, EdgeData](true)
+	nodes := []struct {
+		id, label string
+		meta      map[string]any
+	}{
+		{"gateway", "Gateway", map[string]any{"port": 8080, "rate_limit": "1000/min", "timeout_ms": 30000}},
+		{"auth", "Auth", map[string]any{"strategy": "JWT", "token_ttl": "1h", "issuer": "auth-service"}},
+		{"users", "Users", nil},
+		{"orders", "Orders", nil},
+		{"products", "Products", nil},
+		{"payments", "Payments", nil},
+		{"inventory", "Inventory", nil},
+		{"notify", "Notify", map[string]any{"channels": []any{"email", "slack", "webhook"}, "retry_count": 3}},
+		{"cache", "Cache", map[string]any{"engine": "Redis", "ttl_seconds": 300, "max_memory": "256mb"}},
+		{"db", "Database", map[string]any{"engine": "PostgreSQL", "version": "15", "pool_size": 20}},
+	}
+	for _, n := range nodes {
+		g.AddNodeWithMeta(n.id, NodeData{Label: n.label}, n.meta)
+	}
+	edges := []struct {
+		from, to string
+		latency  float64
+	}{
+		{"gateway", "auth", 5}, {"gateway", "users", 12}, {"gateway", "orders", 15},
+		{"auth", "users", 8}, {"auth", "cache", 2},
+		{"users", "notify", 20},
+		{"orders", "payments", 25}, {"orders", "inventory", 10},
+		{"products", "inventory", 7}, {"products", "cache", 3},
+		{"payments", "notify", 15},
+		{"inventory", "db", 5},
+		{"cache", "db", 4},
+	}
+	for _, e := range edges {
+		g.AddEdge(e.from, e.to, EdgeData{}, e.latency)
+	}
+	return g
+}
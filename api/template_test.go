@@ -0,0 +1,41 @@
+package api
+
+import "testing"
+
+func TestBuildFromTemplateWorkflow(t *testing.T) {
+	g, err := BuildFromTemplate("workflow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Order() != 9 || g.Size() != 10 {
+		t.Errorf("expected 9 nodes and 10 edges, got %d and %d", g.Order(), g.Size())
+	}
+	if _, err := BuildFromTemplate("nope"); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
+
+func TestOpenFromTemplate(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+
+	info, err := mgr.OpenFromTemplate("ci", "workflow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.NodeCount != 9 || info.EdgeCount != 10 {
+		t.Errorf("expected 9 nodes and 10 edges, got %d and %d", info.NodeCount, info.EdgeCount)
+	}
+
+	res, err := mgr.ReadNodes(ReadNodesRequest{Graph: "ci", Filters: []MetaFilter{{Key: "status", Op: "eq", Value: "ready"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 1 || res.Nodes[0].ID != "push" {
+		t.Errorf("expected only push ready, got %+v", res.Nodes)
+	}
+
+	if _, err := mgr.OpenFromTemplate("ci", "workflow"); err == nil {
+		t.Error("expected error when graph already open")
+	}
+}
@@ -1,8 +1,13 @@
 package api
 
-import "fmt"
+import (
+	"fmt"
 
-// validTransitions defines allowed status changes.
+	"github.com/imran31415/spine"
+)
+
+// validTransitions defines the allowed status changes of the default status
+// machine, used by graphs that don't configure their own.
 var validTransitions = map[string]map[string]bool{
 	"":        {"pending": true, "ready": true},
 	"pending": {"ready": true, "skipped": true},
@@ -11,13 +16,149 @@ var validTransitions = map[string]map[string]bool{
 	"failed":  {"pending": true},
 }
 
-// Transition moves a node to a new status, enforcing valid transitions.
+// Transition moves a node to a new status, enforcing the graph's status
+// machine (see SetStatusMachine).
 // When a node becomes "done", downstream nodes whose deps are all done
-// are automatically promoted to "ready".
+// are automatically promoted to "ready". When a node becomes "skipped" and
+// req.CascadeSkip is set, pending descendants are transitively skipped.
+// Callbacks registered with OnReady are invoked after the lock is released.
 func (m *Manager) Transition(req TransitionRequest) (*TransitionResult, error) {
+	m.mu.Lock()
+	var res *TransitionResult
+	g, err := m.getGraph(req.Graph)
+	if err == nil {
+		undo, rollback := m.rollbackSnapshot(g)
+		mark := m.markLocked(req.Graph)
+		if res, err = m.transitionLocked(req); err == nil {
+			m.pushUndoLocked(req.Graph, undo)
+			if err = m.logLocked(opTransition, req.Graph, req); err != nil {
+				m.abortLocked(req.Graph, g, rollback, mark)
+				res = nil
+			}
+		}
+	}
+	var hooks []func([]string)
+	if err == nil && len(res.NewlyReady) > 0 {
+		hooks = append(hooks, m.readyHooks[req.Graph]...)
+	}
+	m.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn(res.NewlyReady)
+	}
+	return res, err
+}
+
+// OnReady registers fn to be called with the newly-ready node IDs whenever a
+// transition on the named graph promotes nodes to "ready". Callbacks run
+// synchronously on the transitioning goroutine, outside the Manager lock, so
+// they may call back into the Manager.
+func (m *Manager) OnReady(graph string, fn func(ids []string)) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.readyHooks[graph] = append(m.readyHooks[graph], fn)
+}
 
+// AdvancePlan marks each completed node done, stepping it through the
+// intermediate statuses on the shortest path to "done" in the graph's status
+// machine, and returns the nodes readied along the way together with the
+// plan's overall progress. Every node is checked before anything is changed,
+// and if any step fails the graph is left as it was; nodes with no path to
+// "done" (such as skipped nodes under the default machine) cannot be
+// advanced. Each step is logged as an individual transition once all of them
+// succeeded, the whole call is undone as one, and OnReady callbacks are
+// invoked once with all newly-ready nodes after the lock is released.
+func (m *Manager) AdvancePlan(graph string, completed []string) (*AdvanceResult, error) {
+	m.mu.Lock()
+	res, err := m.advanceLocked(graph, completed)
+	var hooks []func([]string)
+	if err == nil && len(res.NewlyReady) > 0 {
+		hooks = append(hooks, m.readyHooks[graph]...)
+	}
+	m.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn(res.NewlyReady)
+	}
+	return res, err
+}
+
+func (m *Manager) advanceLocked(graph string, completed []string) (*AdvanceResult, error) {
+	g, err := m.getGraph(graph)
+	if err != nil {
+		return nil, err
+	}
+	sm, err := StatusMachineFor(g)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range completed {
+		node, ok := g.GetNode(id)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrNodeNotFound, id)
+		}
+		if _, ok := sm.path(node.Data.Status, "done"); !ok {
+			return nil, fmt.Errorf("%w: %q -> %q", ErrInvalidTransition, node.Data.Status, "done")
+		}
+	}
+
+	undo, rollback := m.rollbackSnapshot(g)
+	mark := m.markLocked(graph)
+	var steps []TransitionRequest
+	readied := make(map[string]bool)
+	for _, id := range completed {
+		// Paths are taken from the current status: earlier steps may have
+		// readied this node.
+		node, _ := g.GetNode(id)
+		path, ok := sm.path(node.Data.Status, "done")
+		if !ok {
+			g.Restore(rollback)
+			return nil, fmt.Errorf("%w: %q -> %q", ErrInvalidTransition, node.Data.Status, "done")
+		}
+		for _, status := range path {
+			req := TransitionRequest{Graph: graph, ID: id, Status: status}
+			tr, err := m.transitionLocked(req)
+			if err != nil {
+				g.Restore(rollback)
+				return nil, err
+			}
+			steps = append(steps, req)
+			for _, r := range tr.NewlyReady {
+				readied[r] = true
+			}
+		}
+	}
+	m.pushUndoLocked(graph, undo)
+	for _, req := range steps {
+		if err := m.logLocked(opTransition, graph, req); err != nil {
+			m.abortLocked(graph, g, rollback, mark)
+			return nil, err
+		}
+	}
+
+	res := &AdvanceResult{
+		Completed:    append(make([]string, 0, len(completed)), completed...),
+		NewlyReady:   make([]string, 0),
+		Ready:        make([]string, 0),
+		StatusCounts: make(map[string]int),
+	}
+	for _, n := range g.Nodes() {
+		res.StatusCounts[n.Data.Status]++
+		switch n.Data.Status {
+		case "ready":
+			res.Ready = append(res.Ready, n.ID)
+			if readied[n.ID] {
+				res.NewlyReady = append(res.NewlyReady, n.ID)
+			}
+		case "done":
+			res.Done++
+		}
+	}
+	res.Total = g.Order()
+	return res, nil
+}
+
+func (m *Manager) transitionLocked(req TransitionRequest) (*TransitionResult, error) {
 	g, err := m.getGraph(req.Graph)
 	if err != nil {
 		return nil, err
@@ -25,21 +166,25 @@ func (m *Manager) Transition(req TransitionRequest) (*TransitionResult, error) {
 
 	node, ok := g.GetNode(req.ID)
 	if !ok {
-		return nil, fmt.Errorf("node %q not found", req.ID)
+		return nil, fmt.Errorf("%w: %q", ErrNodeNotFound, req.ID)
 	}
 
 	oldStatus := node.Data.Status
 	newStatus := req.Status
 
-	allowed, exists := validTransitions[oldStatus]
-	if !exists || !allowed[newStatus] {
-		return nil, fmt.Errorf("invalid transition: %q -> %q", oldStatus, newStatus)
+	sm, err := StatusMachineFor(g)
+	if err != nil {
+		return nil, err
+	}
+	if !sm.Allows(oldStatus, newStatus) {
+		return nil, fmt.Errorf("%w: %q -> %q", ErrInvalidTransition, oldStatus, newStatus)
 	}
 
 	// Apply the transition.
 	nd := node.Data
 	nd.Status = newStatus
 	g.AddNode(req.ID, nd)
+	m.bumpVersion(req.Graph)
 
 	res := &TransitionResult{
 		ID:        req.ID,
@@ -49,28 +194,64 @@ func (m *Manager) Transition(req TransitionRequest) (*TransitionResult, error) {
 
 	// Auto-ready propagation: when status becomes "done", check downstream.
 	if newStatus == "done" {
-		for _, outEdge := range g.OutEdges(req.ID) {
-			downstream, ok := g.GetNode(outEdge.To)
-			if !ok || downstream.Data.Status != "pending" {
-				continue
-			}
-			// Check if ALL incoming deps are done.
-			allDone := true
-			for _, inEdge := range g.InEdges(outEdge.To) {
-				dep, ok := g.GetNode(inEdge.From)
-				if !ok || dep.Data.Status != "done" {
-					allDone = false
-					break
+		res.NewlyReady = ComputeReadyWithPolicy(g, m.policies[req.Graph])
+	}
+
+	// Skip cascade: pending dependents of a skipped node can never run.
+	if newStatus == "skipped" && req.CascadeSkip {
+		queue := []string{req.ID}
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+			for _, outEdge := range g.OutEdges(cur) {
+				downstream, ok := g.GetNode(outEdge.To)
+				if !ok || downstream.Data.Status != "pending" {
+					continue
 				}
-			}
-			if allDone {
 				dd := downstream.Data
-				dd.Status = "ready"
+				dd.Status = "skipped"
 				g.AddNode(outEdge.To, dd)
-				res.NewlyReady = append(res.NewlyReady, outEdge.To)
+				res.NewlySkipped = append(res.NewlySkipped, outEdge.To)
+				queue = append(queue, outEdge.To)
 			}
 		}
 	}
 
+	if req.Verbose {
+		res.Readiness = explainReadiness(g, req.ID, res.NewlyReady)
+	}
+
 	return res, nil
 }
+
+// explainReadiness reports, for each direct downstream node of id, its
+// status, whether it was just readied, and which dependencies are not done.
+func explainReadiness(g *spine.Graph[NodeData, EdgeData], id string, readied []string) []ReadinessCheck {
+	wasReadied := make(map[string]bool, len(readied))
+	for _, r := range readied {
+		wasReadied[r] = true
+	}
+	checks := make([]ReadinessCheck, 0)
+	for _, e := range g.OutEdges(id) {
+		n, ok := g.GetNode(e.To)
+		if !ok {
+			continue
+		}
+		check := ReadinessCheck{ID: e.To, Status: n.Data.Status, Readied: wasReadied[e.To]}
+		for _, in := range g.InEdges(e.To) {
+			if dep, ok := g.GetNode(in.From); !ok || dep.Data.Status != "done" {
+				check.Blocking = append(check.Blocking, in.From)
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// ComputeReady promotes every pending node that has at least one dependency
+// (in-edge) and whose dependencies are all "done" to "ready", unless the node
+// overrides the rule with ReadinessMetaKey metadata. It returns the promoted
+// IDs in sorted order. Nodes without dependencies are left alone.
+func ComputeReady(g *spine.Graph[NodeData, EdgeData]) []string {
+	return ComputeReadyWithPolicy(g, AllDeps)
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"errors"
 	"testing"
+
+	"github.com/imran31415/spine"
 )
 
 func TestTransitionBasic(t *testing.T) {
@@ -149,3 +152,264 @@ func TestTransitionNotOpen(t *testing.T) {
 		t.Error("expected error for non-open graph")
 	}
 }
+
+func TestTransitionSkipCascade(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("t")
+	mgr.Upsert(UpsertRequest{
+		Graph: "t",
+		Nodes: []UpsertNode{
+			{ID: "a", Status: "done"},
+			{ID: "b", Status: "ready"},
+			{ID: "c", Status: "pending"},
+			{ID: "d", Status: "pending"},
+			{ID: "e", Status: "running"},
+		},
+		Edges: []UpsertEdge{
+			{From: "a", To: "b"},
+			{From: "b", To: "c"},
+			{From: "c", To: "d"},
+			{From: "b", To: "e"},
+		},
+	})
+
+	res, err := mgr.Transition(TransitionRequest{Graph: "t", ID: "b", Status: "skipped", CascadeSkip: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.NewlySkipped) != 2 || res.NewlySkipped[0] != "c" || res.NewlySkipped[1] != "d" {
+		t.Errorf("expected [c d] newly skipped, got %v", res.NewlySkipped)
+	}
+
+	g, _ := mgr.OpenGraph("t")
+	for id, want := range map[string]string{"c": "skipped", "d": "skipped", "e": "running", "a": "done"} {
+		n, _ := g.GetNode(id)
+		if n.Data.Status != want {
+			t.Errorf("%s: expected %s, got %s", id, want, n.Data.Status)
+		}
+	}
+}
+
+func TestTransitionSkipNoCascade(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("t")
+	mgr.Upsert(UpsertRequest{
+		Graph: "t",
+		Nodes: []UpsertNode{
+			{ID: "a", Status: "ready"},
+			{ID: "b", Status: "pending"},
+		},
+		Edges: []UpsertEdge{{From: "a", To: "b"}},
+	})
+
+	res, err := mgr.Transition(TransitionRequest{Graph: "t", ID: "a", Status: "skipped"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.NewlySkipped) != 0 {
+		t.Errorf("expected no cascade, got %v", res.NewlySkipped)
+	}
+	g, _ := mgr.OpenGraph("t")
+	if n, _ := g.GetNode("b"); n.Data.Status != "pending" {
+		t.Errorf("expected b pending, got %s", n.Data.Status)
+	}
+}
+
+func TestComputeReadyFanIn(t *testing.T) {
+	g := spine.NewGraph[NodeData, EdgeData](true)
+	g.AddNode("a", NodeData{Status: "done"})
+	g.AddNode("b", NodeData{Status: "running"})
+	g.AddNode("c", NodeData{Status: "pending"}) // depends on both a and b
+	g.AddNode("root", NodeData{Status: "pending"})
+	g.AddEdge("a", "c", EdgeData{}, 0)
+	g.AddEdge("b", "c", EdgeData{}, 0)
+
+	if ready := ComputeReady(g); len(ready) != 0 {
+		t.Fatalf("expected nothing ready while b runs, got %v", ready)
+	}
+
+	g.AddNode("b", NodeData{Status: "done"})
+	ready := ComputeReady(g)
+	if len(ready) != 1 || ready[0] != "c" {
+		t.Fatalf("expected [c] ready, got %v", ready)
+	}
+	if n, _ := g.GetNode("c"); n.Data.Status != "ready" {
+		t.Errorf("expected c promoted to ready, got %s", n.Data.Status)
+	}
+	if n, _ := g.GetNode("root"); n.Data.Status != "pending" {
+		t.Errorf("expected dependency-free root left pending, got %s", n.Data.Status)
+	}
+}
+
+func TestOnReadyCallback(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("t")
+	mgr.Upsert(UpsertRequest{
+		Graph: "t",
+		Nodes: []UpsertNode{
+			{ID: "a", Status: "running"},
+			{ID: "b", Status: "pending"},
+		},
+		Edges: []UpsertEdge{{From: "a", To: "b"}},
+	})
+
+	var got []string
+	mgr.OnReady("t", func(ids []string) {
+		got = append(got, ids...)
+		// Must not deadlock: the callback runs outside the lock.
+		mgr.Transition(TransitionRequest{Graph: "t", ID: ids[0], Status: "running"})
+	})
+
+	if _, err := mgr.Transition(TransitionRequest{Graph: "t", ID: "a", Status: "done"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != "b" {
+		t.Fatalf("expected callback with [b], got %v", got)
+	}
+	g, _ := mgr.OpenGraph("t")
+	if n, _ := g.GetNode("b"); n.Data.Status != "running" {
+		t.Errorf("expected callback to start b, got %s", n.Data.Status)
+	}
+
+	// Transitions that ready nothing do not fire the callback.
+	mgr.Transition(TransitionRequest{Graph: "t", ID: "b", Status: "done"})
+	if len(got) != 1 {
+		t.Errorf("expected no further callbacks, got %v", got)
+	}
+}
+
+func TestTransitionVerbose(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("v")
+	mgr.Upsert(UpsertRequest{
+		Graph: "v",
+		Nodes: []UpsertNode{
+			{ID: "build", Status: "running"},
+			{ID: "lint", Status: "running"},
+			{ID: "docs", Status: "pending"},
+			{ID: "deploy", Status: "pending"},
+		},
+		Edges: []UpsertEdge{
+			{From: "build", To: "deploy"},
+			{From: "lint", To: "deploy"},
+			{From: "build", To: "docs"},
+		},
+	})
+
+	res, err := mgr.Transition(TransitionRequest{Graph: "v", ID: "build", Status: "done", Verbose: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Readiness) != 2 {
+		t.Fatalf("expected 2 downstream checks, got %+v", res.Readiness)
+	}
+	deploy, docs := res.Readiness[0], res.Readiness[1]
+	if deploy.ID != "deploy" || deploy.Readied || deploy.Status != "pending" {
+		t.Errorf("expected deploy left pending, got %+v", deploy)
+	}
+	if len(deploy.Blocking) != 1 || deploy.Blocking[0] != "lint" {
+		t.Errorf("expected deploy blocked by lint, got %v", deploy.Blocking)
+	}
+	if docs.ID != "docs" || !docs.Readied || len(docs.Blocking) != 0 {
+		t.Errorf("expected docs readied with no blockers, got %+v", docs)
+	}
+
+	// Without Verbose no explanation is returned.
+	res, _ = mgr.Transition(TransitionRequest{Graph: "v", ID: "lint", Status: "done"})
+	if res.Readiness != nil {
+		t.Errorf("expected no readiness explanation, got %+v", res.Readiness)
+	}
+}
+
+func TestAdvancePlan(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("plan")
+	mgr.Upsert(UpsertRequest{
+		Graph: "plan",
+		Nodes: []UpsertNode{
+			{ID: "root", Status: "ready"},
+			{ID: "build", Status: "pending"},
+			{ID: "ship", Status: "pending"},
+		},
+		Edges: []UpsertEdge{
+			{From: "root", To: "build"},
+			{From: "build", To: "ship"},
+		},
+	})
+
+	res, err := mgr.AdvancePlan("plan", []string{"root"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.NewlyReady) != 1 || res.NewlyReady[0] != "build" {
+		t.Errorf("expected build newly ready, got %v", res.NewlyReady)
+	}
+	if len(res.Ready) != 1 || res.Ready[0] != "build" {
+		t.Errorf("expected ready [build], got %v", res.Ready)
+	}
+	if res.Done != 1 || res.Total != 3 || res.StatusCounts["pending"] != 1 {
+		t.Errorf("unexpected progress: %+v", res)
+	}
+
+	// A pending node is stepped through ready and running to done.
+	res, err = mgr.AdvancePlan("plan", []string{"build", "ship"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Done != 3 || len(res.Ready) != 0 {
+		t.Errorf("expected plan complete, got %+v", res)
+	}
+
+	mgr.Upsert(UpsertRequest{Graph: "plan", Nodes: []UpsertNode{{ID: "x", Status: "skipped"}}})
+	if _, err := mgr.AdvancePlan("plan", []string{"x"}); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition for skipped node, got %v", err)
+	}
+}
+
+func TestAdvancePlanStatusMachine(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("sm")
+	mgr.Upsert(UpsertRequest{
+		Graph: "sm",
+		Nodes: []UpsertNode{{ID: "a", Status: "pending"}, {ID: "b", Status: "pending"}, {ID: "c", Status: "dropped"}},
+	})
+	mgr.SetStatusMachine("sm", &StatusMachine{
+		Transitions: map[string][]string{
+			"pending": {"review", "dropped"},
+			"review":  {"blocked", "done"},
+			"blocked": {"review"},
+		},
+	})
+	g, _ := mgr.OpenGraph("sm")
+
+	// A node without a path to done fails the call before anything changes.
+	if _, err := mgr.AdvancePlan("sm", []string{"a", "c"}); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition for dropped node, got %v", err)
+	}
+	if a, _ := g.GetNode("a"); a.Data.Status != "pending" {
+		t.Fatalf("expected a untouched, got %q", a.Data.Status)
+	}
+
+	// Nodes step through the custom machine's path, undone as one.
+	res, err := mgr.AdvancePlan("sm", []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Done != 2 || res.StatusCounts["dropped"] != 1 {
+		t.Errorf("unexpected progress: %+v", res)
+	}
+	if err := mgr.Undo("sm"); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if n, _ := g.GetNode(id); n.Data.Status != "pending" {
+			t.Errorf("expected %s pending after one undo, got %q", id, n.Data.Status)
+		}
+	}
+}
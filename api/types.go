@@ -25,21 +25,33 @@ type UpsertRequest struct {
 
 // UpsertNode describes a node to create or update.
 type UpsertNode struct {
-	ID     string         `json:"id"`
-	Label  string         `json:"label,omitempty"`
-	Status string         `json:"status,omitempty"`
-	Meta   map[string]any `json:"meta,omitempty"`
-	Delete []string       `json:"delete,omitempty"`
+	ID      string         `json:"id"`
+	Label   string         `json:"label,omitempty"`
+	Status  string         `json:"status,omitempty"`
+	Meta    map[string]any `json:"meta,omitempty"`
+	Delete  []string       `json:"delete,omitempty"`
+	MetaOps []MetaOp       `json:"meta_ops,omitempty"`
 }
 
 // UpsertEdge describes an edge to create or update.
 type UpsertEdge struct {
-	From   string         `json:"from"`
-	To     string         `json:"to"`
-	Label  string         `json:"label,omitempty"`
-	Weight *float64       `json:"weight,omitempty"`
-	Meta   map[string]any `json:"meta,omitempty"`
-	Delete []string       `json:"delete,omitempty"`
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	Label   string         `json:"label,omitempty"`
+	Weight  *float64       `json:"weight,omitempty"`
+	Meta    map[string]any `json:"meta,omitempty"`
+	Delete  []string       `json:"delete,omitempty"`
+	MetaOps []MetaOp       `json:"meta_ops,omitempty"`
+}
+
+// MetaOp is an incremental metadata update applied after Meta and Delete.
+// Supported ops: "incr" (add Value, default 1, to a numeric key; missing keys
+// start at 0), "append" (append Value to a list key; missing keys start empty)
+// and "set_if_absent" (set Value only if the key is missing).
+type MetaOp struct {
+	Key   string `json:"key"`
+	Op    string `json:"op"`
+	Value any    `json:"value,omitempty"`
 }
 
 // UpsertResult summarises the side-effects of an upsert.
@@ -63,9 +75,17 @@ type ReadNodesRequest struct {
 	IncludeEdges bool         `json:"include_edges,omitempty"`
 	Offset       int          `json:"offset,omitempty"`
 	Limit        int          `json:"limit,omitempty"`
+	// IncludeReachCounts sets AncestorCount and DescendantCount on each node.
+	IncludeReachCounts bool `json:"include_reach_counts,omitempty"`
 }
 
 // MetaFilter is a single filter predicate applied to node metadata or structural fields.
+// The reserved keys "upstream_status" and "downstream_status" match nodes with
+// at least one predecessor or successor whose status satisfies Op and Value.
+// The "empty" and "notempty" ops treat a missing key, nil, "", and empty
+// slices or maps as empty. A Value of the form {"$field": "other"} compares
+// against the node's own "other" field instead of a literal, e.g. nodes whose
+// "current" is below their "target".
 type MetaFilter struct {
 	Key   string `json:"key"`
 	Op    string `json:"op"`
@@ -80,15 +100,22 @@ type NodeResult struct {
 	Meta      map[string]any `json:"meta,omitempty"`
 	InDegree  int            `json:"in_degree"`
 	OutDegree int            `json:"out_degree"`
+	// AncestorCount and DescendantCount are the number of nodes that can
+	// reach this node and that it can reach. They are set only when the
+	// request asks for reach counts.
+	AncestorCount   *int `json:"ancestor_count,omitempty"`
+	DescendantCount *int `json:"descendant_count,omitempty"`
 }
 
 // EdgeResult is a single edge in a read response.
+// Undirected edges are reported once with From <= To and Undirected set.
 type EdgeResult struct {
-	From   string         `json:"from"`
-	To     string         `json:"to"`
-	Label  string         `json:"label"`
-	Weight float64        `json:"weight,omitempty"`
-	Meta   map[string]any `json:"meta,omitempty"`
+	From       string         `json:"from"`
+	To         string         `json:"to"`
+	Label      string         `json:"label"`
+	Weight     float64        `json:"weight,omitempty"`
+	Meta       map[string]any `json:"meta,omitempty"`
+	Undirected bool           `json:"undirected,omitempty"`
 }
 
 // ReadNodesResponse is the response to a ReadNodes request.
@@ -99,6 +126,25 @@ type ReadNodesResponse struct {
 	HasMore bool         `json:"has_more"`
 }
 
+// ReadEdgesRequest describes a selective read of edges with optional
+// filtering, metadata projection, and pagination. Filters use the same ops as
+// ReadNodesRequest; the keys "from", "to", "label" and "weight" match the
+// edge's structural fields and any other key matches its metadata.
+type ReadEdgesRequest struct {
+	Graph   string       `json:"graph"`
+	Keys    []string     `json:"keys,omitempty"`
+	Filters []MetaFilter `json:"filters,omitempty"`
+	Offset  int          `json:"offset,omitempty"`
+	Limit   int          `json:"limit,omitempty"`
+}
+
+// ReadEdgesResponse is the response to a ReadEdges request.
+type ReadEdgesResponse struct {
+	Edges   []EdgeResult `json:"edges"`
+	Total   int          `json:"total"`
+	HasMore bool         `json:"has_more"`
+}
+
 // --- Lifecycle ---
 
 // GraphInfo describes a graph at a glance.
@@ -107,6 +153,7 @@ type GraphInfo struct {
 	NodeCount int    `json:"node_count"`
 	EdgeCount int    `json:"edge_count"`
 	Directed  bool   `json:"directed"`
+	Version   int    `json:"version"`
 }
 
 // GraphSummary extends GraphInfo with structural statistics.
@@ -128,14 +175,42 @@ type TransitionRequest struct {
 	Graph  string `json:"graph"`
 	ID     string `json:"id"`
 	Status string `json:"status"`
+	// CascadeSkip marks pending descendants as skipped when the node is skipped,
+	// since they can never become ready.
+	CascadeSkip bool `json:"cascade_skip,omitempty"`
+	// Verbose adds a readiness explanation for each downstream node.
+	Verbose bool `json:"verbose,omitempty"`
 }
 
 // TransitionResult describes what happened after a status transition.
 type TransitionResult struct {
-	ID        string   `json:"id"`
-	OldStatus string   `json:"old_status"`
-	NewStatus string   `json:"new_status"`
-	NewlyReady []string `json:"newly_ready,omitempty"`
+	ID           string   `json:"id"`
+	OldStatus    string   `json:"old_status"`
+	NewStatus    string   `json:"new_status"`
+	NewlyReady   []string `json:"newly_ready,omitempty"`
+	NewlySkipped []string `json:"newly_skipped,omitempty"`
+	// Readiness is set when the request is Verbose.
+	Readiness []ReadinessCheck `json:"readiness,omitempty"`
+}
+
+// AdvanceResult reports the state of a plan after AdvancePlan.
+type AdvanceResult struct {
+	Completed  []string `json:"completed"`
+	NewlyReady []string `json:"newly_ready"`
+	// Ready lists every node that is ready after the advance, sorted.
+	Ready        []string       `json:"ready"`
+	Done         int            `json:"done"`
+	Total        int            `json:"total"`
+	StatusCounts map[string]int `json:"status_counts"`
+}
+
+// ReadinessCheck explains why a downstream node was or was not readied by a
+// transition. Blocking lists its dependencies that are not yet done.
+type ReadinessCheck struct {
+	ID       string   `json:"id"`
+	Status   string   `json:"status"`
+	Readied  bool     `json:"readied"`
+	Blocking []string `json:"blocking,omitempty"`
 }
 
 // --- Remove ---
@@ -158,3 +233,89 @@ type RemoveResult struct {
 	NodesRemoved int `json:"nodes_removed"`
 	EdgesRemoved int `json:"edges_removed"`
 }
+
+// SimulationResult previews the impact of a RemoveRequest without applying it.
+type SimulationResult struct {
+	RemoveResult
+	// Unreachable lists nodes reachable from some root before the removal
+	// and from none after it.
+	Unreachable []string `json:"unreachable"`
+	// Orphaned lists nodes that would lose all of their in-edges.
+	Orphaned []string `json:"orphaned"`
+	// BrokenPaths lists root -> node pairs that would no longer be connected.
+	BrokenPaths []EdgeKey `json:"broken_paths"`
+}
+
+// --- Progress ---
+
+// StatusChange records a node whose status differs between two plan states.
+type StatusChange struct {
+	ID   string `json:"id"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ProgressReport compares the task status of two plan states matched by node ID.
+type ProgressReport struct {
+	Advanced     []StatusChange `json:"advanced"`
+	Regressed    []StatusChange `json:"regressed"`
+	Added        []string       `json:"added,omitempty"`
+	Removed      []string       `json:"removed,omitempty"`
+	DoneBefore   int            `json:"done_before"`
+	DoneAfter    int            `json:"done_after"`
+	NetCompleted int            `json:"net_completed"`
+}
+
+// --- Integrity ---
+
+// EdgeKey identifies an edge by its endpoints.
+type EdgeKey struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// IntegrityReport lists problems found in a graph snapshot by CheckIntegrity.
+type IntegrityReport struct {
+	// OrphanNodeMeta holds IDs with metadata but no node.
+	OrphanNodeMeta []string `json:"orphan_node_meta"`
+	// OrphanEdgeMeta holds edges with metadata but no edge.
+	OrphanEdgeMeta []EdgeKey `json:"orphan_edge_meta"`
+	// OrphanDirectedEdgeMeta holds edge directions with metadata of their own
+	// (see DirectedEdgeMeta) but no edge.
+	OrphanDirectedEdgeMeta []EdgeKey `json:"orphan_directed_edge_meta"`
+	SelfLoops              []EdgeKey `json:"self_loops"`
+	// DuplicateLabels maps a label to the sorted IDs of the nodes sharing it.
+	DuplicateLabels map[string][]string `json:"duplicate_labels"`
+	Clean           bool                `json:"clean"`
+	// Repaired is set when orphaned metadata was dropped.
+	Repaired bool `json:"repaired"`
+}
+
+// PlanCheck is the outcome of one category of ValidatePlan.
+type PlanCheck struct {
+	Passed   bool     `json:"passed"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// PlanValidation is the report produced by ValidatePlan. Valid is set when
+// every check passed.
+type PlanValidation struct {
+	Valid bool `json:"valid"`
+	// Acyclic fails if the dependency graph has a cycle; Cycle holds one.
+	Acyclic PlanCheck `json:"acyclic"`
+	Cycle   []string  `json:"cycle,omitempty"`
+	// Dependencies fails if an edge references a missing node.
+	Dependencies PlanCheck `json:"dependencies"`
+	// Statuses fails if a node has a status its status machine does not
+	// know, or has started before its dependencies allow.
+	Statuses PlanCheck `json:"statuses"`
+	// Schema fails if node metadata violates its label schema.
+	Schema PlanCheck `json:"schema"`
+}
+
+// PathResult is the cheapest path from a source node to one destination.
+type PathResult struct {
+	To   string   `json:"to"`
+	Path []string `json:"path"`
+	Cost float64  `json:"cost"`
+}
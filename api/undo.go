@@ -0,0 +1,135 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/imran31415/spine"
+)
+
+// DefaultUndoDepth is the number of snapshots kept per graph for Undo when
+// Manager.UndoDepth is zero.
+const DefaultUndoDepth = 50
+
+// undoDepth returns the configured undo bound; 0 means undo is disabled.
+func (m *Manager) undoDepth() int {
+	switch {
+	case m.UndoDepth < 0:
+		return 0
+	case m.UndoDepth == 0:
+		return DefaultUndoDepth
+	}
+	return m.UndoDepth
+}
+
+// undoSnapshot copies g for the undo stack, or returns nil if undo is
+// disabled.
+func (m *Manager) undoSnapshot(g *spine.Graph[NodeData, EdgeData]) *spine.Graph[NodeData, EdgeData] {
+	if m.undoDepth() == 0 {
+		return nil
+	}
+	return g.Copy()
+}
+
+// rollbackSnapshot returns the undo snapshot of g, nil if undo is disabled,
+// and the state to restore g to if the mutation about to be made fails or
+// cannot be logged: the undo snapshot itself, or a copy of g when there is
+// none.
+func (m *Manager) rollbackSnapshot(g *spine.Graph[NodeData, EdgeData]) (undo, rollback *spine.Graph[NodeData, EdgeData]) {
+	undo = m.undoSnapshot(g)
+	if undo == nil {
+		return nil, g.Copy()
+	}
+	return undo, undo
+}
+
+// mutationMark holds the undo and redo stacks and version of a graph before a
+// mutation, for abortLocked.
+type mutationMark struct {
+	undo, redo []*spine.Graph[NodeData, EdgeData]
+	version    int
+}
+
+// markLocked captures the undo and redo stacks and version of the named
+// graph. Caller must hold m.mu.
+func (m *Manager) markLocked(name string) mutationMark {
+	return mutationMark{
+		undo:    slices.Clone(m.undo[name]),
+		redo:    slices.Clone(m.redo[name]),
+		version: m.versions[name],
+	}
+}
+
+// abortLocked backs out a mutation of the named graph whose log entry could
+// not be written: g is restored to rollback and the undo and redo stacks and
+// version to mark, so retrying does not apply the mutation twice. Caller must
+// hold m.mu.
+func (m *Manager) abortLocked(name string, g, rollback *spine.Graph[NodeData, EdgeData], mark mutationMark) {
+	g.Restore(rollback)
+	m.undo[name], m.versions[name] = mark.undo, mark.version
+	if mark.redo != nil {
+		m.redo[name] = mark.redo
+	}
+}
+
+// pushUndoLocked records snap, the state of the named graph before a
+// mutation, dropping the oldest snapshot if the stack is full. A new mutation
+// invalidates anything that could be redone. Caller must hold m.mu.
+func (m *Manager) pushUndoLocked(name string, snap *spine.Graph[NodeData, EdgeData]) {
+	if snap == nil {
+		return
+	}
+	stack := append(m.undo[name], snap)
+	if over := len(stack) - m.undoDepth(); over > 0 {
+		clear(stack[:over])
+		stack = stack[over:]
+	}
+	m.undo[name] = stack
+	delete(m.redo, name)
+}
+
+// Undo restores the named graph to its state before its most recent logged
+// mutation (see NewManagerWithLog) other than Undo and Redo. Up to UndoDepth
+// steps can be undone. The graph is restored in place, so graphs obtained
+// from OpenGraph see the change. Returns ErrNothingToUndo if there is
+// nothing left to undo.
+func (m *Manager) Undo(graph string) error {
+	return m.step(graph, opUndo, m.undo, m.redo, ErrNothingToUndo)
+}
+
+// Redo reapplies the most recently undone change to the named graph. Any new
+// mutation clears the redo stack. Returns ErrNothingToRedo if there is
+// nothing to redo.
+func (m *Manager) Redo(graph string) error {
+	return m.step(graph, opRedo, m.redo, m.undo, ErrNothingToRedo)
+}
+
+// step pops a snapshot from one stack, pushes the current state onto the
+// other and restores the snapshot in place.
+func (m *Manager) step(graph, op string, from, to map[string][]*spine.Graph[NodeData, EdgeData], empty error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(graph)
+	if err != nil {
+		return err
+	}
+	stack := from[graph]
+	if len(stack) == 0 {
+		return fmt.Errorf("%w: %q", empty, graph)
+	}
+	mark := m.markLocked(graph)
+	snap := stack[len(stack)-1]
+	stack[len(stack)-1] = nil
+	from[graph] = stack[:len(stack)-1]
+	cur := g.Copy()
+	to[graph] = append(to[graph], cur)
+
+	g.Restore(snap)
+	m.bumpVersion(graph)
+	if err := m.logLocked(op, graph, nil); err != nil {
+		m.abortLocked(graph, g, cur, mark)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestUndoRedo(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("u")
+	g, _ := mgr.OpenGraph("u")
+
+	if err := mgr.Undo("u"); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("expected ErrNothingToUndo, got %v", err)
+	}
+
+	mgr.Upsert(UpsertRequest{Graph: "u", Nodes: []UpsertNode{{ID: "a", Status: "pending"}}})
+	if err := mgr.Undo("u"); err != nil {
+		t.Fatal(err)
+	}
+	if g.HasNode("a") {
+		t.Error("expected node a to be gone after undo")
+	}
+	if err := mgr.Redo("u"); err != nil {
+		t.Fatal(err)
+	}
+	if !g.HasNode("a") {
+		t.Error("expected node a to be back after redo")
+	}
+	if err := mgr.Redo("u"); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("expected ErrNothingToRedo, got %v", err)
+	}
+
+	// Transitions and removals are undoable too.
+	mgr.Transition(TransitionRequest{Graph: "u", ID: "a", Status: "ready"})
+	mgr.Remove(RemoveRequest{Graph: "u", Nodes: []string{"a"}})
+	mgr.Undo("u")
+	node, ok := g.GetNode("a")
+	if !ok || node.Data.Status != "ready" {
+		t.Fatalf("expected a ready after undoing remove, got %+v", node)
+	}
+	mgr.Undo("u")
+	node, _ = g.GetNode("a")
+	if node.Data.Status != "pending" {
+		t.Errorf("expected a pending after undoing transition, got %q", node.Data.Status)
+	}
+
+	// A new mutation discards the redo stack.
+	mgr.Upsert(UpsertRequest{Graph: "u", Nodes: []UpsertNode{{ID: "b"}}})
+	if err := mgr.Redo("u"); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("expected redo stack to be cleared, got %v", err)
+	}
+
+	if err := mgr.Undo("missing"); !errors.Is(err, ErrGraphNotOpen) {
+		t.Errorf("expected ErrGraphNotOpen, got %v", err)
+	}
+}
+
+func TestUndoDepthBounded(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.UndoDepth = 3
+	mgr.Open("u")
+
+	for i := 0; i < 5; i++ {
+		mgr.Upsert(UpsertRequest{Graph: "u", Nodes: []UpsertNode{{ID: fmt.Sprintf("n%d", i)}}})
+	}
+	if n := len(mgr.undo["u"]); n != 3 {
+		t.Fatalf("expected undo stack of 3, got %d", n)
+	}
+	for i := 0; i < 3; i++ {
+		if err := mgr.Undo("u"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mgr.Undo("u"); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected ErrNothingToUndo past the bound, got %v", err)
+	}
+	g, _ := mgr.OpenGraph("u")
+	if g.Order() != 2 {
+		t.Errorf("expected the two oldest nodes to remain, got %d nodes", g.Order())
+	}
+
+	off, _ := NewManager(filepath.Join(dir, "off"))
+	off.UndoDepth = -1
+	off.Open("u")
+	off.Upsert(UpsertRequest{Graph: "u", Nodes: []UpsertNode{{ID: "a"}}})
+	if err := off.Undo("u"); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected undo to be disabled, got %v", err)
+	}
+}
+
+func TestUndoReplay(t *testing.T) {
+	dir := tempDir(t)
+	logPath := filepath.Join(dir, "mutations.log")
+	mgr, _ := NewManagerWithLog(dir, logPath)
+	mgr.Open("u")
+	mgr.Upsert(UpsertRequest{Graph: "u", Nodes: []UpsertNode{{ID: "a"}}})
+	mgr.Upsert(UpsertRequest{Graph: "u", Nodes: []UpsertNode{{ID: "b"}}})
+	mgr.Undo("u")
+
+	fresh, _ := NewManager(filepath.Join(dir, "b"))
+	if err := fresh.Replay(logPath); err != nil {
+		t.Fatal(err)
+	}
+	g, _ := fresh.OpenGraph("u")
+	if !g.HasNode("a") || g.HasNode("b") {
+		t.Errorf("expected replay to apply the undo, got nodes %v", sortedNodeIDs(g))
+	}
+}
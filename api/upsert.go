@@ -1,8 +1,16 @@
 package api
 
-import "github.com/imran31415/spine"
+import (
+	"fmt"
+	"sort"
+
+	"github.com/imran31415/spine"
+)
 
 // Upsert performs a batch of idempotent node and edge create/update operations.
+// The batch is atomic: an invalid meta op aborts the upsert with an error and
+// leaves the graph unchanged. The changes are staged in a Graph.Batch, so
+// OnChange listeners only see the changes of a successful upsert.
 func (m *Manager) Upsert(req UpsertRequest) (*UpsertResult, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -12,8 +20,28 @@ func (m *Manager) Upsert(req UpsertRequest) (*UpsertResult, error) {
 		return nil, err
 	}
 
+	undo, rollback := m.rollbackSnapshot(g)
+	mark := m.markLocked(req.Graph)
 	res := &UpsertResult{}
+	err = g.Batch(func(tx *spine.Tx[NodeData, EdgeData]) error {
+		return applyUpsert(tx.Graph, req, res)
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.pushUndoLocked(req.Graph, undo)
+
+	m.bumpVersion(req.Graph)
+	if err := m.logLocked(opUpsert, req.Graph, req); err != nil {
+		m.abortLocked(req.Graph, g, rollback, mark)
+		return nil, err
+	}
+	return res, nil
+}
 
+// applyUpsert applies req to g, counting the changes in res. It stops at the
+// first error, leaving the changes made so far.
+func applyUpsert(g *spine.Graph[NodeData, EdgeData], req UpsertRequest, res *UpsertResult) error {
 	// Process nodes.
 	for _, un := range req.Nodes {
 		if un.ID == "" {
@@ -42,8 +70,17 @@ func (m *Manager) Upsert(req UpsertRequest) (*UpsertResult, error) {
 		}
 
 		// Metadata operations.
-		res.MetaKeysSet += setMeta(g.NodeMeta(un.ID), un.Meta)
+		n, err := setMeta(g.NodeMeta(un.ID), un.Meta)
+		res.MetaKeysSet += n
+		if err != nil {
+			return fmt.Errorf("node %q: %w", un.ID, err)
+		}
 		res.MetaKeysDeleted += deleteMeta(g.NodeMeta(un.ID), un.Delete)
+		n, err = applyMetaOps(g.NodeMeta(un.ID), un.MetaOps)
+		res.MetaKeysSet += n
+		if err != nil {
+			return fmt.Errorf("node %q: %w", un.ID, err)
+		}
 	}
 
 	// Process edges: auto-create endpoint nodes if missing.
@@ -90,23 +127,115 @@ func (m *Manager) Upsert(req UpsertRequest) (*UpsertResult, error) {
 
 		// Edge metadata.
 		store := g.EdgeMeta(ue.From, ue.To)
-		res.MetaKeysSet += setMeta(store, ue.Meta)
+		n, err := setMeta(store, ue.Meta)
+		res.MetaKeysSet += n
+		if err != nil {
+			return fmt.Errorf("edge %s->%s: %w", ue.From, ue.To, err)
+		}
 		res.MetaKeysDeleted += deleteMeta(store, ue.Delete)
+		n, err = applyMetaOps(store, ue.MetaOps)
+		res.MetaKeysSet += n
+		if err != nil {
+			return fmt.Errorf("edge %s->%s: %w", ue.From, ue.To, err)
+		}
 	}
+	return nil
+}
 
-	return res, nil
+// SetMetaBulk sets every key in meta on each of the given nodes and returns
+// the number of keys set. All nodes must exist and accept every value;
+// otherwise nothing is changed.
+func (m *Manager) SetMetaBulk(graph string, ids []string, meta map[string]any) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(graph)
+	if err != nil {
+		return 0, err
+	}
+	if err := requireNodes(g, ids); err != nil {
+		return 0, err
+	}
+	undo, rollback := m.rollbackSnapshot(g)
+	mark := m.markLocked(graph)
+	count := 0
+	for _, id := range ids {
+		n, err := setMeta(g.NodeMeta(id), meta)
+		if err != nil {
+			g.Restore(rollback)
+			return 0, fmt.Errorf("node %q: %w", id, err)
+		}
+		count += n
+	}
+	m.pushUndoLocked(graph, undo)
+	m.bumpVersion(graph)
+	if err := m.logLocked(opSetMeta, graph, metaBulkRequest{IDs: ids, Meta: meta}); err != nil {
+		m.abortLocked(graph, g, rollback, mark)
+		return 0, err
+	}
+	return count, nil
 }
 
-func setMeta(store *spine.Store, meta map[string]any) int {
+// DeleteMetaBulk removes each of keys from the given nodes and returns the
+// number of keys actually deleted. All nodes must exist; otherwise nothing is
+// changed.
+func (m *Manager) DeleteMetaBulk(graph string, ids []string, keys []string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(graph)
+	if err != nil {
+		return 0, err
+	}
+	if err := requireNodes(g, ids); err != nil {
+		return 0, err
+	}
+	undo, rollback := m.rollbackSnapshot(g)
+	mark := m.markLocked(graph)
+	m.pushUndoLocked(graph, undo)
+	count := 0
+	for _, id := range ids {
+		if g.NodeMetaCount(id) > 0 {
+			count += deleteMeta(g.NodeMeta(id), keys)
+		}
+	}
+	m.bumpVersion(graph)
+	if err := m.logLocked(opDeleteMeta, graph, metaBulkRequest{IDs: ids, Keys: keys}); err != nil {
+		m.abortLocked(graph, g, rollback, mark)
+		return 0, err
+	}
+	return count, nil
+}
+
+// requireNodes returns ErrNodeNotFound for the first of ids missing from g.
+func requireNodes(g *spine.Graph[NodeData, EdgeData], ids []string) error {
+	for _, id := range ids {
+		if !g.HasNode(id) {
+			return fmt.Errorf("%w: %q", ErrNodeNotFound, id)
+		}
+	}
+	return nil
+}
+
+// setMeta sets every entry of meta on store, in key order, and returns the
+// number of keys set. It stops at the first value the store rejects.
+func setMeta(store *spine.Store, meta map[string]any) (int, error) {
 	if store == nil || len(meta) == 0 {
-		return 0
+		return 0, nil
+	}
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 	count := 0
-	for k, v := range meta {
-		store.Set(k, v)
+	for _, k := range keys {
+		if err := store.Set(k, meta[k]); err != nil {
+			return count, err
+		}
 		count++
 	}
-	return count
+	return count, nil
 }
 
 func deleteMeta(store *spine.Store, keys []string) int {
@@ -121,3 +250,82 @@ func deleteMeta(store *spine.Store, keys []string) int {
 	}
 	return count
 }
+
+// applyMetaOps applies ops to store in order and returns the number of keys
+// written. It stops at the first invalid op.
+func applyMetaOps(store *spine.Store, ops []MetaOp) (int, error) {
+	if store == nil || len(ops) == 0 {
+		return 0, nil
+	}
+	count := 0
+	for _, op := range ops {
+		if op.Key == "" {
+			return count, fmt.Errorf("meta op %q: missing key", op.Op)
+		}
+		cur, exists := store.Get(op.Key)
+		switch op.Op {
+		case "incr":
+			delta := op.Value
+			if delta == nil {
+				delta = 1
+			}
+			if !exists {
+				cur = 0
+			}
+			sum, ok := addNumbers(cur, delta)
+			if !ok {
+				return count, fmt.Errorf("meta op incr %q: non-numeric operand", op.Key)
+			}
+			if err := store.Set(op.Key, sum); err != nil {
+				return count, err
+			}
+			count++
+		case "append":
+			var list []any
+			switch v := cur.(type) {
+			case nil:
+			case []any:
+				list = append(list, v...)
+			case []string:
+				for _, s := range v {
+					list = append(list, s)
+				}
+			default:
+				return count, fmt.Errorf("meta op append %q: value is not a list", op.Key)
+			}
+			if err := store.Set(op.Key, append(list, op.Value)); err != nil {
+				return count, err
+			}
+			count++
+		case "set_if_absent":
+			if !exists {
+				if err := store.Set(op.Key, op.Value); err != nil {
+					return count, err
+				}
+				count++
+			}
+		default:
+			return count, fmt.Errorf("unknown meta op %q", op.Op)
+		}
+	}
+	return count, nil
+}
+
+// addNumbers adds two numeric values. The sum stays an int when both operands
+// are ints, so Go callers keep their types; otherwise it is a float64.
+func addNumbers(a, b any) (any, bool) {
+	ai, aInt := a.(int)
+	bi, bInt := b.(int)
+	if aInt && bInt {
+		return ai + bi, true
+	}
+	af, ok := toFloat64(a)
+	if !ok {
+		return nil, false
+	}
+	bf, ok := toFloat64(b)
+	if !ok {
+		return nil, false
+	}
+	return af + bf, true
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"errors"
 	"testing"
+
+	"github.com/imran31415/spine"
 )
 
 func floatPtr(f float64) *float64 { return &f }
@@ -135,6 +138,81 @@ func TestUpsertMeta(t *testing.T) {
 	}
 }
 
+func TestUpsertMetaOps(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("u")
+
+	mgr.Upsert(UpsertRequest{
+		Graph: "u",
+		Nodes: []UpsertNode{{ID: "a", Meta: map[string]any{"retries": 1, "log": []any{"start"}}}},
+	})
+
+	res, err := mgr.Upsert(UpsertRequest{
+		Graph: "u",
+		Nodes: []UpsertNode{{ID: "a", MetaOps: []MetaOp{
+			{Key: "retries", Op: "incr"},
+			{Key: "log", Op: "append", Value: "retry"},
+			{Key: "owner", Op: "set_if_absent", Value: "alice"},
+			{Key: "owner", Op: "set_if_absent", Value: "bob"},
+		}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.MetaKeysSet != 3 {
+		t.Errorf("expected 3 meta keys set, got %d", res.MetaKeysSet)
+	}
+
+	g, _ := mgr.OpenGraph("u")
+	store := g.NodeMeta("a")
+	if v, _ := store.Get("retries"); v != 2 {
+		t.Errorf("expected retries 2, got %v", v)
+	}
+	log, _ := store.Get("log")
+	if list, ok := log.([]any); !ok || len(list) != 2 || list[1] != "retry" {
+		t.Errorf("expected [start retry], got %v", log)
+	}
+	if v, _ := store.Get("owner"); v != "alice" {
+		t.Errorf("expected owner alice, got %v", v)
+	}
+}
+
+func TestUpsertMetaOpsInvalid(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("u")
+	g, _ := mgr.OpenGraph("u")
+	var seen []spine.HistoryOp[NodeData, EdgeData]
+	g.OnChange(func(op spine.HistoryOp[NodeData, EdgeData]) { seen = append(seen, op) })
+
+	_, err := mgr.Upsert(UpsertRequest{
+		Graph: "u",
+		Nodes: []UpsertNode{{ID: "a", Meta: map[string]any{"name": "x"}, MetaOps: []MetaOp{{Key: "name", Op: "incr"}}}},
+	})
+	if err == nil {
+		t.Error("expected error incrementing a string")
+	}
+	_, err = mgr.Upsert(UpsertRequest{
+		Graph: "u",
+		Edges: []UpsertEdge{{From: "a", To: "b", MetaOps: []MetaOp{{Key: "k", Op: "bogus"}}}},
+	})
+	if err == nil {
+		t.Error("expected error for unknown op")
+	}
+
+	// A failed upsert is rolled back as a whole, unseen by listeners.
+	if g.Order() != 0 || g.Size() != 0 {
+		t.Errorf("expected failed upserts to leave the graph empty, got %d nodes and %d edges", g.Order(), g.Size())
+	}
+	if len(seen) != 0 {
+		t.Errorf("expected listeners to see no changes of failed upserts, got %v", seen)
+	}
+	if err := mgr.Undo("u"); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected no undo entry for failed upserts, got %v", err)
+	}
+}
+
 func TestUpsertEdgeWeightZero(t *testing.T) {
 	dir := tempDir(t)
 	mgr, _ := NewManager(dir)
@@ -174,3 +252,66 @@ func TestUpsertNotOpen(t *testing.T) {
 		t.Error("expected error for non-open graph")
 	}
 }
+
+func TestSetMetaBulk(t *testing.T) {
+	mgr := setupReadGraph(t)
+	n, err := mgr.SetMetaBulk("r", []string{"a", "b", "c"}, map[string]any{"reviewed": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 keys set, got %d", n)
+	}
+	resp, _ := mgr.ReadNodes(ReadNodesRequest{
+		Graph:   "r",
+		Filters: []MetaFilter{{Key: "reviewed", Op: "eq", Value: true}},
+	})
+	if resp.Total != 3 {
+		t.Errorf("expected 3 reviewed nodes, got %d", resp.Total)
+	}
+
+	n, err = mgr.DeleteMetaBulk("r", []string{"a", "b", "d"}, []string{"reviewed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 keys deleted, got %d", n)
+	}
+
+	if _, err := mgr.SetMetaBulk("r", []string{"a", "ghost"}, map[string]any{"x": 1}); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("expected ErrNodeNotFound, got %v", err)
+	}
+	if resp, _ := mgr.ReadNodes(ReadNodesRequest{Graph: "r", IDs: []string{"a"}}); resp.Nodes[0].Meta["x"] != nil {
+		t.Error("expected no changes when a node is missing")
+	}
+}
+
+func TestMetaRejectedByStrictStore(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("u")
+	mgr.Upsert(UpsertRequest{Graph: "u", Nodes: []UpsertNode{{ID: "a"}, {ID: "b"}}})
+	g, _ := mgr.OpenGraph("u")
+	store := g.NodeMeta("b")
+	store.SetSchema(spine.Schema{"n": {Type: spine.FieldInt}})
+	store.SetStrict(true)
+
+	_, err := mgr.Upsert(UpsertRequest{Graph: "u", Nodes: []UpsertNode{{ID: "b", Meta: map[string]any{"n": "x"}}}})
+	if err == nil {
+		t.Error("expected the upsert to fail on a rejected value")
+	}
+	if _, err := mgr.SetMetaBulk("u", []string{"a", "b"}, map[string]any{"n": "x"}); err == nil {
+		t.Error("expected SetMetaBulk to fail on a rejected value")
+	}
+	if g.NodeMetaCount("a") != 0 || g.NodeMetaCount("b") != 0 {
+		t.Error("expected rejected writes to leave the graph unchanged")
+	}
+	if !g.NodeMeta("b").Strict() {
+		t.Error("expected the rollback to keep the store strict")
+	}
+
+	n, err := mgr.SetMetaBulk("u", []string{"a", "b"}, map[string]any{"n": 1})
+	if err != nil || n != 2 {
+		t.Errorf("expected 2 keys set, got %d, %v", n, err)
+	}
+}
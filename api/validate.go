@@ -0,0 +1,86 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/imran31415/spine"
+)
+
+// ValidatePlan runs the checks a plan should pass before it is executed or
+// presented: the graph is acyclic, every dependency references an existing
+// node, every status is known to the graph's status machine and no node is
+// ready, running or done before its readiness policy allows, and node
+// metadata satisfies the label schemas. Each category is reported
+// separately; a failing plan is not an error.
+func (m *Manager) ValidatePlan(graph string) (*PlanValidation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	g, err := m.getGraph(graph)
+	if err != nil {
+		return nil, err
+	}
+	sm, err := StatusMachineFor(g)
+	if err != nil {
+		return nil, err
+	}
+	schemaErrs, err := labelSchemaErrors(g)
+	if err != nil {
+		return nil, err
+	}
+
+	pv := &PlanValidation{}
+	if !g.Directed {
+		pv.Acyclic.Problems = append(pv.Acyclic.Problems, "plan graph is undirected")
+	} else if found, cycle := spine.CycleDetect(g); found {
+		pv.Cycle = cycle
+		pv.Acyclic.Problems = append(pv.Acyclic.Problems, fmt.Sprintf("cycle: %v", cycle))
+	}
+
+	for _, verr := range spine.Validate(g).Errors {
+		pv.Dependencies.Problems = append(pv.Dependencies.Problems, verr.Message)
+	}
+
+	policy := m.policies[graph]
+	if policy == nil {
+		policy = AllDeps
+	}
+	for _, n := range g.Nodes() {
+		status := n.Data.Status
+		if status != "" && !sm.Valid(status) {
+			pv.Statuses.Problems = append(pv.Statuses.Problems, fmt.Sprintf("node %q has unknown status %q", n.ID, status))
+			continue
+		}
+		switch status {
+		case "ready", "running", "done":
+		default:
+			continue
+		}
+		inEdges := g.InEdges(n.ID)
+		done := 0
+		for _, e := range inEdges {
+			if dep, ok := g.GetNode(e.From); ok && dep.Data.Status == "done" {
+				done++
+			}
+		}
+		p := policy
+		if np := nodePolicy(g, n.ID); np != nil {
+			p = np
+		}
+		if len(inEdges) > 0 && !p(done, len(inEdges)) {
+			pv.Statuses.Problems = append(pv.Statuses.Problems,
+				fmt.Sprintf("node %q is %s but only %d of %d dependencies are done", n.ID, status, done, len(inEdges)))
+		}
+	}
+
+	for _, verr := range schemaErrs {
+		pv.Schema.Problems = append(pv.Schema.Problems, verr.Message)
+	}
+
+	pv.Acyclic.Passed = len(pv.Acyclic.Problems) == 0
+	pv.Dependencies.Passed = len(pv.Dependencies.Problems) == 0
+	pv.Statuses.Passed = len(pv.Statuses.Problems) == 0
+	pv.Schema.Passed = len(pv.Schema.Problems) == 0
+	pv.Valid = pv.Acyclic.Passed && pv.Dependencies.Passed && pv.Statuses.Passed && pv.Schema.Passed
+	return pv, nil
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/imran31415/spine"
+)
+
+func TestValidatePlan(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("plan")
+	mgr.Upsert(UpsertRequest{
+		Graph: "plan",
+		Nodes: []UpsertNode{
+			{ID: "design", Label: "task", Status: "done", Meta: map[string]any{"owner": "ana"}},
+			{ID: "build", Label: "task", Status: "running", Meta: map[string]any{"owner": "bo"}},
+			{ID: "ship", Label: "task", Status: "pending", Meta: map[string]any{"owner": "cy"}},
+		},
+		Edges: []UpsertEdge{{From: "design", To: "build"}, {From: "build", To: "ship"}},
+	})
+	mgr.SetLabelSchema("plan", "task", spine.Schema{"owner": {Type: spine.FieldString, Required: true}})
+
+	pv, err := mgr.ValidatePlan("plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pv.Valid || !pv.Acyclic.Passed || !pv.Dependencies.Passed || !pv.Statuses.Passed || !pv.Schema.Passed {
+		t.Fatalf("expected every check to pass, got %+v", pv)
+	}
+
+	// A node started before its dependency is done, and one missing its
+	// required metadata.
+	mgr.Upsert(UpsertRequest{Graph: "plan", Nodes: []UpsertNode{{ID: "ship", Status: "ready"}, {ID: "docs", Label: "task"}}})
+	pv, _ = mgr.ValidatePlan("plan")
+	if pv.Valid || pv.Statuses.Passed || pv.Schema.Passed || !pv.Acyclic.Passed {
+		t.Errorf("expected status and schema checks to fail, got %+v", pv)
+	}
+
+	if _, err := mgr.ValidatePlan("missing"); !errors.Is(err, ErrGraphNotOpen) {
+		t.Errorf("expected ErrGraphNotOpen, got %v", err)
+	}
+}
+
+func TestValidatePlanCycle(t *testing.T) {
+	dir := tempDir(t)
+	mgr, _ := NewManager(dir)
+	mgr.Open("loop")
+	mgr.Upsert(UpsertRequest{
+		Graph: "loop",
+		Nodes: []UpsertNode{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+		Edges: []UpsertEdge{{From: "a", To: "b"}, {From: "b", To: "c"}, {From: "c", To: "a"}},
+	})
+
+	pv, err := mgr.ValidatePlan("loop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pv.Valid || pv.Acyclic.Passed {
+		t.Fatalf("expected the cycle check to fail, got %+v", pv)
+	}
+	cycle := append([]string(nil), pv.Cycle...)
+	sort.Strings(cycle)
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(cycle, want) {
+		t.Errorf("expected cycle through %v, got %v", want, pv.Cycle)
+	}
+	if !pv.Dependencies.Passed || !pv.Statuses.Passed || !pv.Schema.Passed {
+		t.Errorf("expected the other checks to pass, got %+v", pv)
+	}
+}
@@ -0,0 +1,36 @@
+package spine
+
+// Tx is the staging view of a graph inside Batch. It embeds a working copy
+// of the graph, so every Graph method (reads, AddNode, AddEdge, RemoveNode,
+// NodeMeta(...).Set and so on) is available, but changes only reach the real
+// graph when the batch commits.
+type Tx[N, E any] struct {
+	*Graph[N, E]
+}
+
+// Batch runs fn against a working copy of g and, if fn returns nil, commits
+// every change to g at once; if fn returns an error, g is left untouched and
+// the error is returned. On commit, history and OnChange listeners see each
+// staged operation in order. Staging copies the graph, so Batch costs
+// O(nodes + edges) on top of the operations themselves.
+func (g *Graph[N, E]) Batch(fn func(tx *Tx[N, E]) error) error {
+	work := g.Copy()
+	work.history = &history[N, E]{}
+	work.watchAllMeta()
+
+	if err := fn(&Tx[N, E]{Graph: work}); err != nil {
+		return err
+	}
+
+	g.nodes, g.out, g.in = work.nodes, work.out, work.in
+	g.nodeMeta, g.edgeMeta, g.dirEdgeMeta, g.graphMeta = work.nodeMeta, work.edgeMeta, work.dirEdgeMeta, work.graphMeta
+	g.rawEdgeCount = work.rawEdgeCount
+	g.components, g.compStale = work.components, work.compStale
+	g.metaIndexes = work.metaIndexes
+	// Rebind the committed stores to g (or unhook them if g does not watch).
+	g.watchAllMeta()
+	for _, op := range work.history.ops {
+		g.record(op)
+	}
+	return nil
+}
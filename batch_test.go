@@ -0,0 +1,77 @@
+package spine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBatchCommit(t *testing.T) {
+	g := NewGraph[string, string](true)
+	g.AddNode("a", "A")
+	var events int
+	g.OnChange(func(HistoryOp[string, string]) { events++ })
+
+	err := g.Batch(func(tx *Tx[string, string]) error {
+		tx.AddNode("b", "B")
+		if err := tx.AddEdge("a", "b", "dep", 1); err != nil {
+			return err
+		}
+		tx.NodeMeta("b").Set("owner", "ana")
+		if !tx.HasEdge("a", "b") {
+			t.Error("expected staged edge to be visible inside the batch")
+		}
+		if g.HasNode("b") {
+			t.Error("expected staged node to be invisible outside the batch")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.HasEdge("a", "b") || g.Order() != 2 || g.Size() != 1 {
+		t.Fatalf("expected committed node and edge, got %d nodes and %d edges", g.Order(), g.Size())
+	}
+	if v, _ := g.NodeMeta("b").Get("owner"); v != "ana" {
+		t.Errorf("expected committed metadata, got %v", v)
+	}
+	if events != 3 {
+		t.Errorf("expected 3 change events on commit, got %d", events)
+	}
+
+	// Metadata set after the commit is still reported to listeners.
+	g.NodeMeta("b").Set("owner", "bo")
+	if events != 4 {
+		t.Errorf("expected committed stores to be rebound to the graph, got %d events", events)
+	}
+}
+
+func TestBatchRollback(t *testing.T) {
+	g := NewGraphWithHistory[string, string](true, 0)
+	g.AddNode("a", "A")
+	g.AddNode("b", "B")
+	g.AddEdge("a", "b", "", 1)
+	g.NodeMeta("a").Set("k", "v")
+
+	boom := errors.New("boom")
+	err := g.Batch(func(tx *Tx[string, string]) error {
+		tx.RemoveNode("b")
+		tx.AddNode("c", "C")
+		tx.NodeMeta("a").Delete("k")
+		if err := tx.AddEdge("c", "missing", "", 1); err != nil {
+			return boom
+		}
+		return nil
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected the batch error, got %v", err)
+	}
+	if !g.HasNode("b") || g.HasNode("c") || !g.HasEdge("a", "b") {
+		t.Error("expected the graph to be unchanged after rollback")
+	}
+	if v, _ := g.NodeMeta("a").Get("k"); v != "v" {
+		t.Errorf("expected metadata to be unchanged, got %v", v)
+	}
+	if g.HistoryLen() != 4 {
+		t.Errorf("expected no history for a rolled back batch, got %d ops", g.HistoryLen())
+	}
+}
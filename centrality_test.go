@@ -162,3 +162,55 @@ func TestPageRankEmpty(t *testing.T) {
 		t.Fatalf("expected empty scores, got %v", result.Scores)
 	}
 }
+
+func TestPersonalizedPageRank(t *testing.T) {
+	// Two disjoint cycles: seeding one should concentrate rank there.
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"a", "b", "c", "x", "y"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", 0, 1)
+	g.AddEdge("b", "c", 0, 1)
+	g.AddEdge("c", "a", 0, 1)
+	g.AddEdge("x", "y", 0, 1)
+	g.AddEdge("y", "x", 0, 1)
+
+	result := PersonalizedPageRank(g, map[string]float64{"x": 1, "missing": 5}, 0.85, 200, 1e-9)
+	if !result.Converged {
+		t.Fatal("expected convergence")
+	}
+	if result.Scores["a"] != 0 || result.Scores["b"] != 0 || result.Scores["c"] != 0 {
+		t.Fatalf("expected no rank outside the seeded cycle, got %v", result.Scores)
+	}
+	if math.Abs(result.Scores["x"]+result.Scores["y"]-1) > 1e-6 || result.Scores["x"] <= result.Scores["y"] {
+		t.Fatalf("expected rank split between x and y favouring x, got %v", result.Scores)
+	}
+
+	plain := PageRank(g, 0.85, 200, 1e-9)
+	fallback := PersonalizedPageRank(g, map[string]float64{"missing": 1}, 0.85, 200, 1e-9)
+	for id, s := range plain.Scores {
+		if fallback.Scores[id] != s {
+			t.Fatalf("expected plain PageRank without valid seeds, got %v", fallback.Scores)
+		}
+	}
+}
+
+func TestCentralityTop(t *testing.T) {
+	r := CentralityResult{Scores: map[string]float64{"a": 0.2, "b": 0.5, "c": 0.2, "d": 0.1}}
+	top := r.Top(3)
+	want := []ScoredNode{{"b", 0.5}, {"a", 0.2}, {"c", 0.2}}
+	if len(top) != len(want) {
+		t.Fatalf("expected %d nodes, got %v", len(want), top)
+	}
+	for i := range want {
+		if top[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, top)
+		}
+	}
+	if all := r.Top(0); len(all) != 4 || all[3].ID != "d" {
+		t.Fatalf("expected all nodes for k=0, got %v", all)
+	}
+	if got := (PageRankResult{Scores: r.Scores}).Top(1); len(got) != 1 || got[0].ID != "b" {
+		t.Fatalf("unexpected PageRank top %v", got)
+	}
+}
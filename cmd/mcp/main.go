@@ -15,6 +15,8 @@ func main() {
 	log.SetOutput(os.Stderr)
 
 	dir := flag.String("dir", "", "graph storage directory (default: SPINE_GRAPH_DIR or current dir)")
+	journal := flag.Bool("journal", true, "journal unsaved mutations so they survive a restart")
+	undoDepth := flag.Int("undo-depth", 0, "changes kept per graph for undo (0: default, negative: disable undo)")
 	flag.Parse()
 
 	if *dir == "" {
@@ -34,6 +36,8 @@ func main() {
 		fmt.Fprintf(os.Stderr, "failed to create manager: %v\n", err)
 		os.Exit(1)
 	}
+	mgr.Journal = *journal
+	mgr.UndoDepth = *undoDepth
 
 	srv := mcp.NewServer(mgr)
 	log.Printf("spine-mcp server started (dir=%s)", *dir)
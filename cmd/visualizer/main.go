@@ -10,10 +10,13 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/imran31415/spine"
+	"github.com/imran31415/spine/api"
 )
 
 //go:embed static/index.html
@@ -25,19 +28,15 @@ type Position struct {
 	Y float64 `json:"y"`
 }
 
-// NodeData is the data stored in each graph node.
-type NodeData struct {
-	Label  string `json:"label"`
-	Status string `json:"status,omitempty"`
-}
+// NodeData is the data stored in each graph node. It is shared with the api
+// package so the visualizer can reuse its graph helpers.
+type NodeData = api.NodeData
 
 // EdgeData is the data stored in each graph edge.
-type EdgeData struct {
-	Label string `json:"label"`
-}
+type EdgeData = api.EdgeData
 
 type server struct {
-	mu        sync.Mutex
+	mu        sync.RWMutex // read-only handlers share the read lock
 	graph     *spine.Graph[NodeData, EdgeData]
 	positions map[string]Position
 }
@@ -55,6 +54,11 @@ type removeNodeReq struct {
 	ID string `json:"id"`
 }
 
+type focusReq struct {
+	ID     string `json:"id"`
+	Radius int    `json:"radius"`
+}
+
 type addEdgeReq struct {
 	From   string  `json:"from"`
 	To     string  `json:"to"`
@@ -74,8 +78,19 @@ type updatePosReq struct {
 }
 
 type algoReq struct {
-	Start string `json:"start"`
-	End   string `json:"end"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	MaxNodes int    `json:"maxNodes,omitempty"` // BFS/DFS cap; defaults to maxTraversalNodes
+}
+
+// maxTraversalNodes caps BFS and DFS results unless a request sets maxNodes.
+const maxTraversalNodes = 10000
+
+func (r algoReq) traverseOptions() spine.TraverseOptions {
+	if r.MaxNodes <= 0 {
+		return spine.TraverseOptions{MaxNodes: maxTraversalNodes}
+	}
+	return spine.TraverseOptions{MaxNodes: r.MaxNodes}
 }
 
 type graphResp struct {
@@ -83,6 +98,9 @@ type graphResp struct {
 	Nodes    []nodeResp      `json:"nodes"`
 	Edges    []edgeResp      `json:"edges"`
 	Result   *algoResultResp `json:"result,omitempty"`
+
+	// StatusMachine is set when the graph defines custom statuses.
+	StatusMachine *api.StatusMachine `json:"statusMachine,omitempty"`
 }
 
 type nodeResp struct {
@@ -105,6 +123,7 @@ type edgeResp struct {
 type algoResultResp struct {
 	Algorithm      string      `json:"algorithm"`
 	VisitedOrder   []string    `json:"visitedOrder,omitempty"`
+	Truncated      bool        `json:"truncated,omitempty"`
 	Path           []string    `json:"path,omitempty"`
 	Cost           float64     `json:"cost,omitempty"`
 	HasCycle       bool        `json:"hasCycle,omitempty"`
@@ -118,8 +137,13 @@ type algoResultResp struct {
 	HighlightEdges [][2]string `json:"highlightEdges,omitempty"`
 	MSTEdges       [][2]string `json:"mstEdges,omitempty"`
 	MSTWeight      float64     `json:"mstWeight,omitempty"`
+	MSTComponents  int         `json:"mstComponents,omitempty"`
 	Analytics      any         `json:"analytics,omitempty"`
 	Error          string      `json:"error,omitempty"`
+
+	// EdgeCosts maps "from->to" to the weight of each edge on a weighted
+	// path, so the frontend can label them.
+	EdgeCosts map[string]float64 `json:"edgeCosts,omitempty"`
 }
 
 // Metadata API types
@@ -144,7 +168,13 @@ func newServer(directed bool) *server {
 }
 
 func (s *server) buildGraphResp(result *algoResultResp) graphResp {
-	nodes := s.graph.Nodes()
+	return s.buildGraphRespFor(s.graph, result)
+}
+
+// buildGraphRespFor builds a response for g, which is s.graph or a subgraph
+// of it, using the server's stored positions.
+func (s *server) buildGraphRespFor(g *spine.Graph[NodeData, EdgeData], result *algoResultResp) graphResp {
+	nodes := g.Nodes()
 	nr := make([]nodeResp, len(nodes))
 	for i, n := range nodes {
 		pos := s.positions[n.ID]
@@ -154,10 +184,10 @@ func (s *server) buildGraphResp(result *algoResultResp) graphResp {
 			X:         pos.X,
 			Y:         pos.Y,
 			Status:    n.Data.Status,
-			MetaCount: s.graph.NodeMetaCount(n.ID),
+			MetaCount: g.NodeMetaCount(n.ID),
 		}
 	}
-	edges := s.graph.Edges()
+	edges := g.Edges()
 	er := make([]edgeResp, len(edges))
 	for i, e := range edges {
 		er[i] = edgeResp{
@@ -165,15 +195,24 @@ func (s *server) buildGraphResp(result *algoResultResp) graphResp {
 			To:        e.To,
 			Label:     e.Data.Label,
 			Weight:    e.Weight,
-			MetaCount: s.graph.EdgeMetaCount(e.From, e.To),
+			MetaCount: g.EdgeMetaCount(e.From, e.To),
 		}
 	}
-	return graphResp{
-		Directed: s.graph.Directed,
+	resp := graphResp{
+		Directed: g.Directed,
 		Nodes:    nr,
 		Edges:    er,
 		Result:   result,
 	}
+	if g.GraphMetaCount() == 0 {
+		return resp
+	}
+	if _, ok := g.GraphMeta().Get(api.StatusMachineKey); ok {
+		if sm, err := api.StatusMachineFor(g); err == nil {
+			resp.StatusMachine = &sm
+		}
+	}
+	return resp
 }
 
 func writeJSON(w http.ResponseWriter, v interface{}) {
@@ -186,11 +225,34 @@ func readJSON(r *http.Request, v interface{}) error {
 }
 
 func (s *server) handleGetGraph(w http.ResponseWriter, r *http.Request) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	writeJSON(w, s.buildGraphResp(nil))
 }
 
+// handleFocus returns the subgraph induced by a node and every node within
+// radius hops of it (in either direction), with their current positions.
+// The server's graph is not modified.
+func (s *server) handleFocus(w http.ResponseWriter, r *http.Request) {
+	var req focusReq
+	if err := readJSON(r, &req); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if req.Radius < 0 {
+		http.Error(w, "radius must not be negative", 400)
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.graph.HasNode(req.ID) {
+		http.Error(w, fmt.Sprintf("node %q not found", req.ID), 404)
+		return
+	}
+	ids := append([]string{req.ID}, spine.NeighborsWithin(s.graph, req.ID, req.Radius)...)
+	writeJSON(w, s.buildGraphRespFor(spine.Subgraph(s.graph, ids), nil))
+}
+
 func (s *server) handleAddNode(w http.ResponseWriter, r *http.Request) {
 	var req addNodeReq
 	if err := readJSON(r, &req); err != nil {
@@ -307,8 +369,8 @@ func (s *server) handleAlgo(w http.ResponseWriter, r *http.Request) {
 	var req algoReq
 	readJSON(r, &req)
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	result := &algoResultResp{Algorithm: algo}
 
@@ -318,8 +380,9 @@ func (s *server) handleAlgo(w http.ResponseWriter, r *http.Request) {
 			result.Error = "start node required"
 			break
 		}
-		order := spine.BFS(s.graph, req.Start, nil)
+		order, truncated := spine.BFSWithOptions(s.graph, req.Start, nil, req.traverseOptions())
 		result.VisitedOrder = order
+		result.Truncated = truncated
 		result.HighlightNodes = order
 		result.HighlightEdges = pathToEdges(order)
 
@@ -328,8 +391,9 @@ func (s *server) handleAlgo(w http.ResponseWriter, r *http.Request) {
 			result.Error = "start node required"
 			break
 		}
-		order := spine.DFS(s.graph, req.Start, nil)
+		order, truncated := spine.DFSWithOptions(s.graph, req.Start, nil, req.traverseOptions())
 		result.VisitedOrder = order
+		result.Truncated = truncated
 		result.HighlightNodes = order
 		result.HighlightEdges = pathToEdges(order)
 
@@ -347,6 +411,24 @@ func (s *server) handleAlgo(w http.ResponseWriter, r *http.Request) {
 		result.Cost = cost
 		result.HighlightNodes = path
 		result.HighlightEdges = pathToEdges(path)
+		result.EdgeCosts = s.edgeCosts(result.HighlightEdges)
+
+	case "shortest-path-tree":
+		if req.Start == "" {
+			result.Error = "start node required"
+			break
+		}
+		if !s.graph.HasNode(req.Start) {
+			result.Error = "start node not found"
+			break
+		}
+		tree := spine.ShortestPathTree(s.graph, req.Start)
+		nodes := []string{req.Start}
+		for _, e := range tree {
+			nodes = append(nodes, e[1])
+		}
+		result.HighlightNodes = nodes
+		result.HighlightEdges = tree
 
 	case "topo-sort":
 		order, err := spine.TopologicalSort(s.graph)
@@ -421,12 +503,13 @@ func (s *server) handleAlgo(w http.ResponseWriter, r *http.Request) {
 		result.HighlightNodes = all
 
 	case "mst":
-		edges, totalWeight, err := spine.MinimumSpanningTree(s.graph)
+		edges, totalWeight, components, err := spine.MinimumSpanningTree(s.graph)
 		if err != nil {
 			result.Error = err.Error()
 			break
 		}
 		result.MSTWeight = totalWeight
+		result.MSTComponents = components
 		mstEdges := make([][2]string, len(edges))
 		for i, e := range edges {
 			mstEdges[i] = [2]string{e.From, e.To}
@@ -465,6 +548,17 @@ func pathToEdges(path []string) [][2]string {
 	return edges
 }
 
+// edgeCosts returns the weight of each edge keyed by "from->to".
+func (s *server) edgeCosts(edges [][2]string) map[string]float64 {
+	costs := make(map[string]float64, len(edges))
+	for _, e := range edges {
+		if edge, ok := s.graph.GetEdge(e[0], e[1]); ok {
+			costs[e[0]+"->"+e[1]] = edge.Weight
+		}
+	}
+	return costs
+}
+
 func (s *server) handleGetTemplates(w http.ResponseWriter, r *http.Request) {
 	summaries := make([]templateSummary, len(templates))
 	for i, t := range templates {
@@ -493,62 +587,23 @@ func (s *server) handleLoadTemplate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	g, err := tmpl.build()
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.graph = spine.NewGraph[NodeData, EdgeData](tmpl.Directed)
+	s.graph = g
 	s.positions = make(map[string]Position)
 	for _, n := range tmpl.Nodes {
-		s.graph.AddNode(n.ID, NodeData{Label: n.Label, Status: n.Status})
 		s.positions[n.ID] = Position{X: n.X, Y: n.Y}
-		if len(n.Meta) > 0 {
-			store := s.graph.NodeMeta(n.ID)
-			for k, v := range n.Meta {
-				store.Set(k, v)
-			}
-		}
-	}
-	for _, e := range tmpl.Edges {
-		s.graph.AddEdge(e.From, e.To, EdgeData{Label: e.Label}, e.Weight)
 	}
-	s.computeReady()
+	api.ComputeReady(s.graph)
 	writeJSON(w, s.buildGraphResp(nil))
 }
 
-var validStatuses = map[string]bool{
-	"pending": true, "ready": true, "running": true,
-	"done": true, "failed": true, "skipped": true,
-}
-
-// validTransitions defines allowed status changes.
-var validTransitions = map[string]map[string]bool{
-	"":        {"pending": true, "ready": true},
-	"pending": {"ready": true, "skipped": true},
-	"ready":   {"running": true, "skipped": true},
-	"running": {"done": true, "failed": true},
-	"failed":  {"pending": true},
-}
-
-// computeReady auto-promotes pending nodes to ready when all in-edge sources are done.
-func (s *server) computeReady() {
-	for _, n := range s.graph.Nodes() {
-		if n.Data.Status != "pending" {
-			continue
-		}
-		inEdges := s.graph.InEdges(n.ID)
-		allDone := true
-		for _, e := range inEdges {
-			src, ok := s.graph.GetNode(e.From)
-			if !ok || src.Data.Status != "done" {
-				allDone = false
-				break
-			}
-		}
-		if allDone && len(inEdges) > 0 {
-			s.graph.AddNode(n.ID, NodeData{Label: n.Data.Label, Status: "ready"})
-		}
-	}
-}
-
 func (s *server) handleUpdateNodeStatus(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		ID     string `json:"id"`
@@ -558,25 +613,49 @@ func (s *server) handleUpdateNodeStatus(w http.ResponseWriter, r *http.Request)
 		http.Error(w, err.Error(), 400)
 		return
 	}
-	if !validStatuses[req.Status] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Statuses and transitions come from the graph's status machine, or the
+	// api defaults if it has none.
+	sm, err := api.StatusMachineFor(s.graph)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	if !sm.Valid(req.Status) {
 		http.Error(w, fmt.Sprintf("invalid status: %s", req.Status), 400)
 		return
 	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
 	n, ok := s.graph.GetNode(req.ID)
 	if !ok {
 		http.Error(w, "node not found", 404)
 		return
 	}
 	// Validate transition
-	allowed, exists := validTransitions[n.Data.Status]
-	if !exists || !allowed[req.Status] {
+	if !sm.Allows(n.Data.Status, req.Status) {
 		http.Error(w, fmt.Sprintf("invalid transition: %q -> %q", n.Data.Status, req.Status), 400)
 		return
 	}
 	s.graph.AddNode(req.ID, NodeData{Label: n.Data.Label, Status: req.Status})
-	s.computeReady()
+	api.ComputeReady(s.graph)
+	writeJSON(w, s.buildGraphResp(nil))
+}
+
+// handleSetStatusMachine replaces the graph's status machine. A null body
+// restores the default.
+func (s *server) handleSetStatusMachine(w http.ResponseWriter, r *http.Request) {
+	var sm *api.StatusMachine
+	if err := readJSON(r, &sm); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if sm != nil && len(sm.Transitions) == 0 {
+		http.Error(w, "status machine needs at least one transition", 400)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	api.SetStatusMachine(s.graph, sm)
 	writeJSON(w, s.buildGraphResp(nil))
 }
 
@@ -604,69 +683,8 @@ func (s *server) handleLoadPlan(w http.ResponseWriter, r *http.Request) {
 	s.graph = spine.NewGraph[NodeData, EdgeData](true)
 	s.positions = make(map[string]Position)
 
-	// Build dependency map for auto-layout
-	depMap := make(map[string][]string)
-	taskMap := make(map[string]*planTask)
-	for i := range req.Tasks {
-		t := &req.Tasks[i]
-		taskMap[t.ID] = t
-		depMap[t.ID] = t.Dependencies
-	}
-
-	// Auto-layout: compute topological depth for each node
-	needsLayout := false
-	for _, t := range req.Tasks {
-		if t.X == 0 && t.Y == 0 {
-			needsLayout = true
-			break
-		}
-	}
-
-	if needsLayout {
-		depth := make(map[string]int)
-		var computeDepth func(id string) int
-		computeDepth = func(id string) int {
-			if d, ok := depth[id]; ok {
-				return d
-			}
-			depth[id] = 0
-			maxDep := 0
-			for _, dep := range depMap[id] {
-				d := computeDepth(dep) + 1
-				if d > maxDep {
-					maxDep = d
-				}
-			}
-			depth[id] = maxDep
-			return maxDep
-		}
-		for _, t := range req.Tasks {
-			computeDepth(t.ID)
-		}
-
-		// Group by depth level
-		levels := make(map[int][]string)
-		maxLevel := 0
-		for _, t := range req.Tasks {
-			d := depth[t.ID]
-			levels[d] = append(levels[d], t.ID)
-			if d > maxLevel {
-				maxLevel = d
-			}
-		}
-
-		// Assign positions
-		for level := 0; level <= maxLevel; level++ {
-			ids := levels[level]
-			for i, id := range ids {
-				t := taskMap[id]
-				t.X = 150 + float64(i)*200
-				t.Y = 80 + float64(level)*150
-			}
-		}
-	}
-
 	// Create nodes
+	needsLayout := false
 	for _, t := range req.Tasks {
 		status := t.Status
 		if status == "" {
@@ -678,6 +696,9 @@ func (s *server) handleLoadPlan(w http.ResponseWriter, r *http.Request) {
 		}
 		s.graph.AddNode(t.ID, NodeData{Label: label, Status: status})
 		s.positions[t.ID] = Position{X: t.X, Y: t.Y}
+		if t.X == 0 && t.Y == 0 {
+			needsLayout = true
+		}
 	}
 
 	// Create dependency edges (dep → task)
@@ -687,7 +708,24 @@ func (s *server) handleLoadPlan(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	s.computeReady()
+	// Auto-layout: one row per dependency generation. A plan with a cycle
+	// has no generations, so it is laid out in a single row.
+	if needsLayout {
+		levels, err := spine.TopologicalGenerations(s.graph)
+		if err != nil {
+			levels = [][]string{make([]string, len(req.Tasks))}
+			for i, t := range req.Tasks {
+				levels[0][i] = t.ID
+			}
+		}
+		for level, ids := range levels {
+			for i, id := range ids {
+				s.positions[id] = Position{X: 150 + float64(i)*200, Y: 80 + float64(level)*150}
+			}
+		}
+	}
+
+	api.ComputeReady(s.graph)
 	writeJSON(w, s.buildGraphResp(nil))
 }
 
@@ -744,6 +782,72 @@ type loadDirReq struct {
 	RootName   string     `json:"rootName"`
 	Entries    []dirEntry `json:"entries"`
 	Extensions []string   `json:"extensions,omitempty"`
+	// IDMode selects how entry paths become node IDs: "slug" (the default)
+	// or "path" to use the raw paths.
+	IDMode string `json:"idMode,omitempty"`
+}
+
+// dirIDs assigns node IDs to directory entry paths. In slug mode each path
+// maps to a lowercase slug with separators replaced by '-', suffixed with
+// "-2", "-3", ... when two paths slugify alike. Assignments are memoized, so
+// a path always maps to the same ID within a load.
+type dirIDs struct {
+	raw    bool
+	byPath map[string]string
+	used   map[string]bool
+}
+
+func newDirIDs(mode string) (*dirIDs, error) {
+	switch mode {
+	case "", "slug", "path":
+	default:
+		return nil, fmt.Errorf("unknown idMode %q", mode)
+	}
+	return &dirIDs{
+		raw:    mode == "path",
+		byPath: make(map[string]string),
+		used:   make(map[string]bool),
+	}, nil
+}
+
+func (d *dirIDs) id(path string) string {
+	if id, ok := d.byPath[path]; ok {
+		return id
+	}
+	id := path
+	if !d.raw {
+		base := slugify(path)
+		id = base
+		for n := 2; d.used[id]; n++ {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+	}
+	d.byPath[path] = id
+	d.used[id] = true
+	return id
+}
+
+// slugify lowercases s and replaces every run of characters other than
+// letters, digits, '.' and '_' with a single '-'.
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '_' {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	out := strings.TrimSuffix(b.String(), "-")
+	if out == "" {
+		return "node"
+	}
+	return out
 }
 
 func (s *server) handleLoadDirectory(w http.ResponseWriter, r *http.Request) {
@@ -752,20 +856,87 @@ func (s *server) handleLoadDirectory(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 400)
 		return
 	}
-	if req.RootName == "" {
-		req.RootName = "root"
-	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	prev, prevPositions := s.graph, s.positions
 	s.graph = spine.NewGraph[NodeData, EdgeData](true)
 	s.positions = make(map[string]Position)
+	rootID, added, err := s.addDirEntries(req)
+	if err != nil {
+		s.graph, s.positions = prev, prevPositions
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	s.layoutDirNodes(rootID, added)
+	writeJSON(w, s.buildGraphResp(nil))
+}
+
+// handleAppendDirectory adds a batch of directory entries to the current
+// graph without clearing it, so large directories can be uploaded in several
+// requests. Only the newly added nodes are laid out.
+func (s *server) handleAppendDirectory(w http.ResponseWriter, r *http.Request) {
+	var req loadDirReq
+	if err := readJSON(r, &req); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rootID, added, err := s.addDirEntries(req)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	s.layoutDirNodes(rootID, added)
+	writeJSON(w, s.buildGraphResp(nil))
+}
 
-	// Add root node.
+// addDirEntries adds req's entries to s.graph under the root directory node,
+// creating the root and any unlisted parent directories as needed. IDs already
+// assigned to paths in the graph (recorded in "path" metadata) are reused, so
+// batches appended to one directory link up. It returns the root ID and the
+// original path of every node it created. Caller must hold s.mu.
+func (s *server) addDirEntries(req loadDirReq) (string, map[string]string, error) {
+	if req.RootName == "" {
+		req.RootName = "root"
+	}
+	ids, err := newDirIDs(req.IDMode)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, n := range s.graph.Nodes() {
+		ids.used[n.ID] = true
+		if s.graph.NodeMetaCount(n.ID) == 0 {
+			continue
+		}
+		if p, ok := s.graph.NodeMeta(n.ID).Get("path"); ok {
+			if path, ok := p.(string); ok {
+				ids.byPath[path] = n.ID
+			}
+		}
+	}
+	// Assign IDs in path order so they don't depend on upload order.
+	sort.SliceStable(req.Entries, func(i, j int) bool { return req.Entries[i].Path < req.Entries[j].Path })
+
+	added := make(map[string]string) // node ID -> original path
+
+	// The root is keyed by "." since that is what filepath.Dir returns for
+	// top-level entries.
 	rootID := req.RootName
-	s.graph.AddNode(rootID, NodeData{Label: rootID + "/"})
-	s.graph.NodeMeta(rootID).Set("type", "directory")
+	if !ids.raw {
+		rootID = slugify(rootID)
+	}
+	ids.byPath["."] = rootID
+	ids.used[rootID] = true
+	if !s.graph.HasNode(rootID) {
+		s.graph.AddNode(rootID, NodeData{Label: req.RootName + "/"})
+		s.graph.NodeMeta(rootID).Set("type", "directory")
+		added[rootID] = ""
+	}
 
 	// Build extension filter set (lowercase, with leading dot).
 	extSet := make(map[string]bool, len(req.Extensions))
@@ -780,13 +951,28 @@ func (s *server) handleLoadDirectory(w http.ResponseWriter, r *http.Request) {
 	}
 	filterActive := len(extSet) > 0
 
-	// Collect unique directories and files from entries.
+	// Directories created by this call, candidates for pruning.
 	dirs := make(map[string]bool)
-	dirs[rootID] = true
 
-	for _, e := range req.Entries {
-		nodeID := e.Path
+	// ensureDir returns the ID of the directory node for path, creating it
+	// and linking it to its own parent if the entries didn't list it.
+	var ensureDir func(path string) string
+	ensureDir = func(path string) string {
+		id := ids.id(path)
+		if s.graph.HasNode(id) {
+			return id
+		}
+		s.graph.AddNode(id, NodeData{Label: filepath.Base(path) + "/"})
+		meta := s.graph.NodeMeta(id)
+		meta.Set("type", "directory")
+		meta.Set("path", path)
+		added[id] = path
+		dirs[id] = true
+		s.graph.AddEdge(ensureDir(filepath.Dir(path)), id, EdgeData{}, 1)
+		return id
+	}
 
+	for _, e := range req.Entries {
 		// When filtering, skip files whose extension doesn't match.
 		if filterActive && !e.IsDir {
 			ext := strings.ToLower(filepath.Ext(e.Name))
@@ -794,16 +980,25 @@ func (s *server) handleLoadDirectory(w http.ResponseWriter, r *http.Request) {
 				continue
 			}
 		}
+		nodeID := ids.id(e.Path)
+		isNew := !s.graph.HasNode(nodeID)
+		if isNew {
+			added[nodeID] = e.Path
+		}
 
 		if e.IsDir {
-			dirs[nodeID] = true
+			if isNew {
+				dirs[nodeID] = true
+			}
 			s.graph.AddNode(nodeID, NodeData{Label: e.Name + "/"})
 			meta := s.graph.NodeMeta(nodeID)
 			meta.Set("type", "directory")
+			meta.Set("path", e.Path)
 		} else {
 			s.graph.AddNode(nodeID, NodeData{Label: e.Name})
 			meta := s.graph.NodeMeta(nodeID)
 			meta.Set("type", "file")
+			meta.Set("path", e.Path)
 			meta.Set("size", e.Size)
 			if ext := filepath.Ext(e.Name); ext != "" {
 				meta.Set("extension", ext) // e.g. ".go", ".py", ".js"
@@ -817,18 +1012,7 @@ func (s *server) handleLoadDirectory(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		// Build parent edge. Parent is filepath.Dir(path) or rootName if top-level.
-		parent := filepath.Dir(nodeID)
-		if parent == "." || parent == "" {
-			parent = rootID
-		}
-		// Ensure parent directory nodes exist in the graph.
-		if !s.graph.HasNode(parent) {
-			s.graph.AddNode(parent, NodeData{Label: filepath.Base(parent) + "/"})
-			s.graph.NodeMeta(parent).Set("type", "directory")
-			dirs[parent] = true
-		}
-		s.graph.AddEdge(parent, nodeID, EdgeData{}, 1)
+		s.graph.AddEdge(ensureDir(filepath.Dir(e.Path)), nodeID, EdgeData{}, 1)
 	}
 
 	// When filtering, prune directory nodes that have no children (empty after filtering).
@@ -836,67 +1020,72 @@ func (s *server) handleLoadDirectory(w http.ResponseWriter, r *http.Request) {
 		changed := true
 		for changed {
 			changed = false
-			for _, n := range s.graph.Nodes() {
-				if n.ID == rootID {
-					continue
-				}
-				if !dirs[n.ID] {
-					continue
-				}
+			for id := range dirs {
 				// A directory with no outgoing edges has no children.
-				if len(s.graph.OutEdges(n.ID)) == 0 {
-					s.graph.RemoveNode(n.ID)
-					delete(dirs, n.ID)
+				if len(s.graph.OutEdges(id)) == 0 {
+					s.graph.RemoveNode(id)
+					delete(dirs, id)
+					delete(added, id)
 					changed = true
 				}
 			}
 		}
 	}
+	return rootID, added, nil
+}
 
-	// Compute tree layout: group nodes by depth (number of '/' separators).
-	type nodeDepth struct {
-		id    string
-		depth int
-	}
-	var allNodes []nodeDepth
+// layoutDirNodes positions the given newly added nodes in a tree layout,
+// one row per depth (number of '/' separators in the original path). Rows
+// with no existing nodes are centered; otherwise new nodes are placed to the
+// right of the row's existing nodes. Caller must hold s.mu.
+func (s *server) layoutDirNodes(rootID string, added map[string]string) {
+	rowY := func(level int) float64 { return 80 + float64(level)*150 }
+
+	// Group by depth level.
+	levels := make(map[int][]string)
+	maxLevel := 0
 	for _, n := range s.graph.Nodes() {
+		path, ok := added[n.ID]
+		if !ok {
+			continue
+		}
 		d := 0
 		if n.ID != rootID {
-			for _, c := range n.ID {
-				if c == '/' {
-					d++
-				}
-			}
+			d = strings.Count(path, "/")
 			// Files/dirs at top level under root still have depth 1.
 			d++ // +1 because root is depth 0
 		}
-		allNodes = append(allNodes, nodeDepth{id: n.ID, depth: d})
-	}
-
-	// Group by depth level.
-	levels := make(map[int][]string)
-	maxLevel := 0
-	for _, nd := range allNodes {
-		levels[nd.depth] = append(levels[nd.depth], nd.id)
-		if nd.depth > maxLevel {
-			maxLevel = nd.depth
-		}
+		levels[d] = append(levels[d], n.ID)
+		maxLevel = max(maxLevel, d)
 	}
 
-	// Assign positions: center each level horizontally.
 	for level := 0; level <= maxLevel; level++ {
 		ids := levels[level]
+		if len(ids) == 0 {
+			continue
+		}
 		totalWidth := float64(len(ids)-1) * 200
 		startX := 400 - totalWidth/2
+		placed := false
+		rightmost := 0.0
+		for id, p := range s.positions {
+			if _, isNew := added[id]; !isNew && p.Y == rowY(level) {
+				if !placed || p.X > rightmost {
+					rightmost = p.X
+				}
+				placed = true
+			}
+		}
+		if placed {
+			startX = rightmost + 200
+		}
 		for i, id := range ids {
 			s.positions[id] = Position{
 				X: startX + float64(i)*200,
-				Y: 80 + float64(level)*150,
+				Y: rowY(level),
 			}
 		}
 	}
-
-	writeJSON(w, s.buildGraphResp(nil))
 }
 
 func (s *server) handleClear(w http.ResponseWriter, r *http.Request) {
@@ -910,9 +1099,18 @@ func (s *server) handleClear(w http.ResponseWriter, r *http.Request) {
 
 // ---- Export / Import handlers ----
 
+// exportMeta is a quick summary included in the export wrapper. Import
+// ignores it; the snapshot remains the source of truth.
+type exportMeta struct {
+	Nodes      int       `json:"nodes"`
+	Edges      int       `json:"edges"`
+	Directed   bool      `json:"directed"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
 func (s *server) handleExport(w http.ResponseWriter, r *http.Request) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	snapBytes, err := spine.Marshal(s.graph, nil)
 	if err != nil {
@@ -929,6 +1127,12 @@ func (s *server) handleExport(w http.ResponseWriter, r *http.Request) {
 	wrapper := map[string]any{
 		"positions": s.positions,
 		"snapshot":  snapshot,
+		"meta": exportMeta{
+			Nodes:      s.graph.Order(),
+			Edges:      s.graph.Size(),
+			Directed:   s.graph.Directed,
+			ExportedAt: time.Now().UTC(),
+		},
 	}
 
 	out, err := json.MarshalIndent(wrapper, "", "  ")
@@ -992,13 +1196,16 @@ func (s *server) handleNodeMeta(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 400)
 		return
 	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	if !s.graph.HasNode(req.ID) {
 		http.Error(w, "node not found", 404)
 		return
 	}
-	store := s.graph.NodeMeta(req.ID)
+	store := spine.NewStore()
+	if s.graph.NodeMetaCount(req.ID) > 0 {
+		store = s.graph.NodeMeta(req.ID)
+	}
 	if req.Limit <= 0 {
 		req.Limit = 50
 	}
@@ -1064,13 +1271,16 @@ func (s *server) handleEdgeMeta(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 400)
 		return
 	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	if !s.graph.HasEdge(req.From, req.To) {
 		http.Error(w, "edge not found", 404)
 		return
 	}
-	store := s.graph.EdgeMeta(req.From, req.To)
+	store := spine.NewStore()
+	if s.graph.EdgeMetaCount(req.From, req.To) > 0 {
+		store = s.graph.EdgeMeta(req.From, req.To)
+	}
 	if req.Limit <= 0 {
 		req.Limit = 50
 	}
@@ -1418,12 +1628,15 @@ func main() {
 	mux.HandleFunc("/api/node/position", s.handleUpdatePos)
 	mux.HandleFunc("/api/graph/directed", s.handleSetDirected)
 	mux.HandleFunc("/api/graph/clear", s.handleClear)
+	mux.HandleFunc("/api/graph/focus", s.handleFocus)
 	mux.HandleFunc("/api/templates", s.handleGetTemplates)
 	mux.HandleFunc("/api/template/load", s.handleLoadTemplate)
 	mux.HandleFunc("/api/algo", s.handleAlgo)
 	mux.HandleFunc("/api/node/status", s.handleUpdateNodeStatus)
+	mux.HandleFunc("/api/graph/status-machine", s.handleSetStatusMachine)
 	mux.HandleFunc("/api/plan/load", s.handleLoadPlan)
 	mux.HandleFunc("/api/directory/load", s.handleLoadDirectory)
+	mux.HandleFunc("/api/directory/append", s.handleAppendDirectory)
 
 	// Export/Import API routes.
 	mux.HandleFunc("/api/graph/export", s.handleExport)
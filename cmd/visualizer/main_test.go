@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/imran31415/spine/api"
 )
 
 // --- Test helpers ---
@@ -158,6 +160,19 @@ func TestExportImport(t *testing.T) {
 	}
 	exported := w.Body.Bytes()
 
+	var wrapper struct {
+		Meta exportMeta `json:"meta"`
+	}
+	if err := json.Unmarshal(exported, &wrapper); err != nil {
+		t.Fatal(err)
+	}
+	if wrapper.Meta.Nodes != 2 || wrapper.Meta.Edges != 1 || !wrapper.Meta.Directed {
+		t.Errorf("unexpected export meta: %+v", wrapper.Meta)
+	}
+	if wrapper.Meta.ExportedAt.IsZero() {
+		t.Error("expected export timestamp")
+	}
+
 	// Clear
 	doJSON(t, s.handleClear, map[string]string{})
 
@@ -252,6 +267,40 @@ func TestAlgoBFS(t *testing.T) {
 	}
 }
 
+func TestAlgoShortestPathEdgeCosts(t *testing.T) {
+	s := newTestServer(t)
+	decodeGraphResp(t, doJSON(t, s.handleLoadTemplate, map[string]string{"id": "microservices"}))
+
+	req := httptest.NewRequest("POST", "/api/algo?algo=shortest-path", bytes.NewBufferString(`{"start":"gateway","end":"db"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.handleAlgo(w, req)
+	resp := decodeGraphResp(t, w)
+
+	res := resp.Result
+	if res == nil || res.Error != "" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if len(res.EdgeCosts) != len(res.Path)-1 {
+		t.Fatalf("expected %d edge costs, got %v", len(res.Path)-1, res.EdgeCosts)
+	}
+	total := 0.0
+	for i := 0; i < len(res.Path)-1; i++ {
+		key := res.Path[i] + "->" + res.Path[i+1]
+		cost, ok := res.EdgeCosts[key]
+		if !ok {
+			t.Errorf("missing cost for %s", key)
+		}
+		total += cost
+	}
+	if total != res.Cost {
+		t.Errorf("edge costs sum to %v, path cost is %v", total, res.Cost)
+	}
+	if res.EdgeCosts["gateway->auth"] != 5 {
+		t.Errorf("expected gateway->auth cost 5, got %v", res.EdgeCosts)
+	}
+}
+
 func TestLoadTemplate(t *testing.T) {
 	s := newTestServer(t)
 	if len(templates) == 0 {
@@ -268,6 +317,30 @@ func TestLoadTemplate(t *testing.T) {
 	}
 }
 
+func TestLoadAPITemplate(t *testing.T) {
+	s := newTestServer(t)
+	resp := decodeGraphResp(t, doJSON(t, s.handleLoadTemplate, map[string]string{"id": "workflow"}))
+
+	want, err := api.BuildFromTemplate("workflow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Nodes) != want.Order() || len(resp.Edges) != want.Size() {
+		t.Fatalf("expected the api template's %d nodes and %d edges, got %d and %d",
+			want.Order(), want.Size(), len(resp.Nodes), len(resp.Edges))
+	}
+	for _, n := range resp.Nodes {
+		if n.ID == "push" && (n.Label != "Push" || n.X != 450 || n.Y != 50) {
+			t.Errorf("expected push labelled by the api template at the layout position, got %+v", n)
+		}
+	}
+	for _, ts := range templates {
+		if sum := ts.summary(); ts.API != "" && (sum.NodeCount == 0 || sum.EdgeCount == 0) {
+			t.Errorf("expected %s summary counts from the api template, got %+v", ts.ID, sum)
+		}
+	}
+}
+
 // File-related tests (cannot be parallel because they share graphDir).
 
 func TestListFiles(t *testing.T) {
@@ -358,6 +431,30 @@ func TestSaveFile(t *testing.T) {
 	}
 }
 
+func TestAlgoShortestPathTree(t *testing.T) {
+	s := newTestServer(t)
+	doJSON(t, s.handleAddNode, addNodeReq{ID: "1"})
+	doJSON(t, s.handleAddNode, addNodeReq{ID: "2"})
+	doJSON(t, s.handleAddNode, addNodeReq{ID: "3"})
+	doJSON(t, s.handleAddEdge, addEdgeReq{From: "1", To: "2", Weight: 1})
+	doJSON(t, s.handleAddEdge, addEdgeReq{From: "1", To: "3", Weight: 5})
+	doJSON(t, s.handleAddEdge, addEdgeReq{From: "2", To: "3", Weight: 1})
+
+	req := httptest.NewRequest("POST", "/api/algo?algo=shortest-path-tree", bytes.NewBufferString(`{"start":"1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.handleAlgo(w, req)
+	resp := decodeGraphResp(t, w)
+
+	if resp.Result == nil || resp.Result.Error != "" {
+		t.Fatalf("unexpected result: %+v", resp.Result)
+	}
+	edges := resp.Result.HighlightEdges
+	if len(edges) != 2 || edges[0] != [2]string{"1", "2"} || edges[1] != [2]string{"2", "3"} {
+		t.Fatalf("expected [[1 2] [2 3]], got %v", edges)
+	}
+}
+
 func TestAlgoSCC(t *testing.T) {
 	s := newTestServer(t)
 	// Create a cycle: 1->2->3->1
@@ -480,3 +577,180 @@ func TestSaveFilePathTraversal(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadDirectoryNormalizesIDs(t *testing.T) {
+	s := newTestServer(t)
+	w := doJSON(t, s.handleLoadDirectory, loadDirReq{
+		RootName: "My Project",
+		Entries: []dirEntry{
+			{Path: "src/pkg/util.go", Name: "util.go"},
+			{Path: "src", Name: "src", IsDir: true},
+			{Path: "src/Main.go", Name: "Main.go"},
+			{Path: "src-main.go", Name: "src-main.go"},
+			{Path: "docs/guide.md", Name: "guide.md"},
+		},
+	})
+	resp := decodeGraphResp(t, w)
+
+	edges := make(map[string]bool)
+	for _, e := range resp.Edges {
+		edges[e.From+" -> "+e.To] = true
+	}
+	for _, want := range []string{
+		"my-project -> src",
+		"my-project -> docs",
+		"my-project -> src-main.go",
+		"docs -> docs-guide.md",
+		"src -> src-pkg",
+		"src -> src-main.go-2",
+		"src-pkg -> src-pkg-util.go",
+	} {
+		if !edges[want] {
+			t.Errorf("missing edge %s; got %v", want, edges)
+		}
+	}
+	if len(resp.Edges) != 7 {
+		t.Errorf("expected 7 edges, got %d", len(resp.Edges))
+	}
+
+	for id, path := range map[string]string{
+		"src-pkg-util.go": "src/pkg/util.go",
+		"src-pkg":         "src/pkg",
+		"src-main.go":     "src-main.go",
+		"src-main.go-2":   "src/Main.go",
+	} {
+		if got, _ := s.graph.NodeMeta(id).Get("path"); got != path {
+			t.Errorf("node %s: expected path %q, got %v", id, path, got)
+		}
+	}
+
+	// Raw path IDs remain available.
+	w = doJSON(t, s.handleLoadDirectory, loadDirReq{
+		RootName: "root",
+		IDMode:   "path",
+		Entries:  []dirEntry{{Path: "a/b.go", Name: "b.go"}},
+	})
+	resp = decodeGraphResp(t, w)
+	if len(resp.Edges) != 2 || !s.graph.HasEdge("a", "a/b.go") || !s.graph.HasEdge("root", "a") {
+		t.Errorf("unexpected raw-path edges: %+v", resp.Edges)
+	}
+
+	if w := doJSON(t, s.handleLoadDirectory, loadDirReq{IDMode: "bogus"}); w.Code != 400 {
+		t.Errorf("expected 400 for unknown idMode, got %d", w.Code)
+	}
+}
+
+func TestAppendDirectory(t *testing.T) {
+	s := newTestServer(t)
+	decodeGraphResp(t, doJSON(t, s.handleLoadDirectory, loadDirReq{
+		RootName: "repo",
+		Entries: []dirEntry{
+			{Path: "src", Name: "src", IsDir: true},
+			{Path: "src/a.go", Name: "a.go"},
+		},
+	}))
+	before := s.positions["src-a.go"]
+
+	resp := decodeGraphResp(t, doJSON(t, s.handleAppendDirectory, loadDirReq{
+		RootName: "repo",
+		Entries: []dirEntry{
+			{Path: "src/b.go", Name: "b.go"},
+			{Path: "src/util/c.go", Name: "c.go"},
+			{Path: "README.md", Name: "README.md"},
+		},
+	}))
+
+	if len(resp.Nodes) != 7 {
+		t.Fatalf("expected 7 nodes after append, got %d", len(resp.Nodes))
+	}
+	for _, e := range [][2]string{
+		{"repo", "src"},
+		{"src", "src-a.go"},
+		{"src", "src-b.go"},
+		{"src", "src-util"},
+		{"src-util", "src-util-c.go"},
+		{"repo", "readme.md"},
+	} {
+		if !s.graph.HasEdge(e[0], e[1]) {
+			t.Errorf("missing edge %s -> %s", e[0], e[1])
+		}
+	}
+	if s.positions["src-a.go"] != before {
+		t.Errorf("existing node moved: %v -> %v", before, s.positions["src-a.go"])
+	}
+	if p := s.positions["src-b.go"]; p.Y != before.Y || p.X <= before.X {
+		t.Errorf("expected src-b.go to the right of src-a.go, got %v vs %v", p, before)
+	}
+}
+
+func TestFocus(t *testing.T) {
+	s := newTestServer(t)
+	for _, id := range []string{"hub", "a", "b", "c", "far"} {
+		doJSON(t, s.handleAddNode, addNodeReq{ID: id, X: 1, Y: 2})
+	}
+	doJSON(t, s.handleAddEdge, addEdgeReq{From: "hub", To: "a"})
+	doJSON(t, s.handleAddEdge, addEdgeReq{From: "b", To: "hub"})
+	doJSON(t, s.handleAddEdge, addEdgeReq{From: "a", To: "c"})
+	doJSON(t, s.handleAddEdge, addEdgeReq{From: "c", To: "far"})
+
+	resp := decodeGraphResp(t, doJSON(t, s.handleFocus, focusReq{ID: "hub", Radius: 1}))
+	var ids []string
+	for _, n := range resp.Nodes {
+		ids = append(ids, n.ID)
+		if n.X != 1 || n.Y != 2 {
+			t.Errorf("node %s: expected stored position, got (%v, %v)", n.ID, n.X, n.Y)
+		}
+	}
+	if len(ids) != 3 || ids[0] != "a" || ids[1] != "b" || ids[2] != "hub" {
+		t.Errorf("expected [a b hub], got %v", ids)
+	}
+	if len(resp.Edges) != 2 {
+		t.Errorf("expected 2 edges, got %+v", resp.Edges)
+	}
+	if s.graph.Order() != 5 {
+		t.Errorf("focus should not modify the graph, order=%d", s.graph.Order())
+	}
+
+	if w := doJSON(t, s.handleFocus, focusReq{ID: "missing", Radius: 1}); w.Code != 404 {
+		t.Errorf("expected 404 for missing node, got %d", w.Code)
+	}
+}
+
+func TestCustomStatusMachine(t *testing.T) {
+	s := newTestServer(t)
+	doJSON(t, s.handleAddNode, addNodeReq{ID: "a"})
+	resp := decodeGraphResp(t, doJSON(t, s.handleSetStatusMachine, map[string]any{
+		"transitions": map[string][]string{"": {"review"}, "review": {"done"}},
+		"colors":      map[string]string{"review": "#a78bfa"},
+	}))
+	if resp.StatusMachine == nil || resp.StatusMachine.Colors["review"] != "#a78bfa" {
+		t.Fatalf("expected status machine in response, got %+v", resp.StatusMachine)
+	}
+
+	w := doJSON(t, s.handleUpdateNodeStatus, map[string]string{"id": "a", "status": "review"})
+	if w.Code != 200 {
+		t.Fatalf("expected custom status to be accepted, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := doJSON(t, s.handleUpdateNodeStatus, map[string]string{"id": "a", "status": "running"}); w.Code != 400 {
+		t.Errorf("expected undefined status to be rejected, got %d", w.Code)
+	}
+}
+
+func TestLoadPlanAutoLayout(t *testing.T) {
+	s := newTestServer(t)
+	w := doJSON(t, s.handleLoadPlan, map[string]any{"tasks": []planTask{
+		{ID: "design"},
+		{ID: "build", Dependencies: []string{"design"}},
+		{ID: "docs", Dependencies: []string{"design"}},
+		{ID: "ship", Dependencies: []string{"build", "docs"}},
+	}})
+	resp := decodeGraphResp(t, w)
+
+	rows := make(map[string]float64)
+	for _, n := range resp.Nodes {
+		rows[n.ID] = n.Y
+	}
+	if rows["design"] >= rows["build"] || rows["build"] != rows["docs"] || rows["docs"] >= rows["ship"] {
+		t.Errorf("expected one row per dependency generation, got %v", rows)
+	}
+}
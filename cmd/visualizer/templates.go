@@ -1,11 +1,19 @@
 package main
 
-// Template defines a pre-built graph that users can load.
+import (
+	"github.com/imran31415/spine"
+	"github.com/imran31415/spine/api"
+)
+
+// Template defines a pre-built graph that users can load. A template with API
+// set is built by the api template of that name (see api.BuildFromTemplate),
+// and its Nodes only supply the layout.
 type Template struct {
 	ID          string         `json:"id"`
 	Name        string         `json:"name"`
 	Description string         `json:"description"`
 	Directed    bool           `json:"directed"`
+	API         string         `json:"api,omitempty"`
 	Nodes       []templateNode `json:"nodes"`
 	Edges       []templateEdge `json:"edges"`
 }
@@ -37,7 +45,7 @@ type templateSummary struct {
 }
 
 func (t Template) summary() templateSummary {
-	return templateSummary{
+	s := templateSummary{
 		ID:          t.ID,
 		Name:        t.Name,
 		Description: t.Description,
@@ -45,6 +53,28 @@ func (t Template) summary() templateSummary {
 		NodeCount:   len(t.Nodes),
 		EdgeCount:   len(t.Edges),
 	}
+	if t.API != "" {
+		if g, err := api.BuildFromTemplate(t.API); err == nil {
+			s.Directed, s.NodeCount, s.EdgeCount = g.Directed, g.Order(), g.Size()
+		}
+	}
+	return s
+}
+
+// build returns the graph of t: the api template it names, or its own nodes
+// and edges.
+func (t Template) build() (*spine.Graph[NodeData, EdgeData], error) {
+	if t.API != "" {
+		return api.BuildFromTemplate(t.API)
+	}
+	g := spine.NewGraph[NodeData, EdgeData](t.Directed)
+	for _, n := range t.Nodes {
+		g.AddNodeWithMeta(n.ID, NodeData{Label: n.Label, Status: n.Status}, n.Meta)
+	}
+	for _, e := range t.Edges {
+		g.AddEdge(e.From, e.To, EdgeData{Label: e.Label}, e.Weight)
+	}
+	return g, nil
 }
 
 var templates = []Template{
@@ -52,43 +82,17 @@ var templates = []Template{
 		ID:          "workflow",
 		Name:        "CI/CD Workflow",
 		Description: "Fan-out/fan-in DAG — try topo sort, roots, and leaves",
-		Directed:    true,
+		API:         "workflow",
 		Nodes: []templateNode{
-			{ID: "push", Label: "Push", X: 450, Y: 50, Meta: map[string]any{
-				"trigger": "on_push", "branch": "main",
-			}},
-			{ID: "lint", Label: "Lint", X: 200, Y: 180, Meta: map[string]any{
-				"tool": "golangci-lint", "config": ".golangci.yml",
-			}},
-			{ID: "test", Label: "Test", X: 450, Y: 180, Meta: map[string]any{
-				"framework": "go test", "flags": "-race -v", "min_coverage": "80%",
-			}},
-			{ID: "build", Label: "Build", X: 700, Y: 180, Meta: map[string]any{
-				"output": "./bin/app", "os": "linux", "arch": "amd64",
-			}},
-			{ID: "scan", Label: "Security Scan", X: 200, Y: 330, Meta: map[string]any{
-				"tool": "gosec", "severity": "high",
-			}},
-			{ID: "coverage", Label: "Coverage", X: 450, Y: 330},
-			{ID: "docker", Label: "Docker", X: 700, Y: 330, Meta: map[string]any{
-				"base_image": "golang:1.22-alpine", "registry": "ghcr.io", "tag": "latest",
-			}},
-			{ID: "staging", Label: "Staging", X: 450, Y: 480},
-			{ID: "deploy", Label: "Deploy", X: 450, Y: 620, Meta: map[string]any{
-				"environment": "production", "region": "us-east-1", "replicas": 3,
-			}},
-		},
-		Edges: []templateEdge{
-			{From: "push", To: "lint", Weight: 1},
-			{From: "push", To: "test", Weight: 1},
-			{From: "push", To: "build", Weight: 1},
-			{From: "lint", To: "scan", Weight: 1},
-			{From: "test", To: "coverage", Weight: 1},
-			{From: "build", To: "docker", Weight: 1},
-			{From: "scan", To: "staging", Weight: 1},
-			{From: "coverage", To: "staging", Weight: 1},
-			{From: "docker", To: "staging", Weight: 1},
-			{From: "staging", To: "deploy", Weight: 1},
+			{ID: "push", X: 450, Y: 50},
+			{ID: "lint", X: 200, Y: 180},
+			{ID: "test", X: 450, Y: 180},
+			{ID: "build", X: 700, Y: 180},
+			{ID: "scan", X: 200, Y: 330},
+			{ID: "coverage", X: 450, Y: 330},
+			{ID: "docker", X: 700, Y: 330},
+			{ID: "staging", X: 450, Y: 480},
+			{ID: "deploy", X: 450, Y: 620},
 		},
 	},
 	{
@@ -232,43 +236,18 @@ var templates = []Template{
 		ID:          "microservices",
 		Name:        "Microservices",
 		Description: "Weighted edges (latency in ms) — try shortest path",
-		Directed:    true,
+		API:         "microservices",
 		Nodes: []templateNode{
-			{ID: "gateway", Label: "Gateway", X: 450, Y: 50, Meta: map[string]any{
-				"port": 8080, "rate_limit": "1000/min", "timeout_ms": 30000,
-			}},
-			{ID: "auth", Label: "Auth", X: 200, Y: 180, Meta: map[string]any{
-				"strategy": "JWT", "token_ttl": "1h", "issuer": "auth-service",
-			}},
-			{ID: "users", Label: "Users", X: 450, Y: 180},
-			{ID: "orders", Label: "Orders", X: 700, Y: 180},
-			{ID: "products", Label: "Products", X: 200, Y: 350},
-			{ID: "payments", Label: "Payments", X: 450, Y: 350},
-			{ID: "inventory", Label: "Inventory", X: 700, Y: 350},
-			{ID: "notify", Label: "Notify", X: 300, Y: 520, Meta: map[string]any{
-				"channels": []any{"email", "slack", "webhook"}, "retry_count": 3,
-			}},
-			{ID: "cache", Label: "Cache", X: 550, Y: 520, Meta: map[string]any{
-				"engine": "Redis", "ttl_seconds": 300, "max_memory": "256mb",
-			}},
-			{ID: "db", Label: "Database", X: 700, Y: 520, Meta: map[string]any{
-				"engine": "PostgreSQL", "version": "15", "pool_size": 20,
-			}},
-		},
-		Edges: []templateEdge{
-			{From: "gateway", To: "auth", Weight: 5},
-			{From: "gateway", To: "users", Weight: 12},
-			{From: "gateway", To: "orders", Weight: 15},
-			{From: "auth", To: "users", Weight: 8},
-			{From: "auth", To: "cache", Weight: 2},
-			{From: "users", To: "notify", Weight: 20},
-			{From: "orders", To: "payments", Weight: 25},
-			{From: "orders", To: "inventory", Weight: 10},
-			{From: "products", To: "inventory", Weight: 7},
-			{From: "products", To: "cache", Weight: 3},
-			{From: "payments", To: "notify", Weight: 15},
-			{From: "inventory", To: "db", Weight: 5},
-			{From: "cache", To: "db", Weight: 4},
+			{ID: "gateway", X: 450, Y: 50},
+			{ID: "auth", X: 200, Y: 180},
+			{ID: "users", X: 450, Y: 180},
+			{ID: "orders", X: 700, Y: 180},
+			{ID: "products", X: 200, Y: 350},
+			{ID: "payments", X: 450, Y: 350},
+			{ID: "inventory", X: 700, Y: 350},
+			{ID: "notify", X: 300, Y: 520},
+			{ID: "cache", X: 550, Y: 520},
+			{ID: "db", X: 700, Y: 520},
 		},
 	},
 	{
@@ -0,0 +1,37 @@
+package spine
+
+// GreedyColoring assigns each node the smallest color (starting at 0) not used
+// by any already-colored neighbor, visiting nodes in sorted ID order. Edge
+// direction is ignored, so no two adjacent nodes share a color. Self-loops are
+// ignored.
+func GreedyColoring[N, E any](g *Graph[N, E]) map[string]int {
+	colors := make(map[string]int, g.Order())
+	for _, n := range g.Nodes() {
+		used := make(map[int]bool)
+		for _, e := range g.OutEdges(n.ID) {
+			if c, ok := colors[e.To]; ok && e.To != n.ID {
+				used[c] = true
+			}
+		}
+		for _, e := range g.InEdges(n.ID) {
+			if c, ok := colors[e.From]; ok && e.From != n.ID {
+				used[c] = true
+			}
+		}
+		c := 0
+		for used[c] {
+			c++
+		}
+		colors[n.ID] = c
+	}
+	return colors
+}
+
+// ColorCount returns the number of distinct colors in a coloring.
+func ColorCount(colors map[string]int) int {
+	seen := make(map[int]bool)
+	for _, c := range colors {
+		seen[c] = true
+	}
+	return len(seen)
+}
@@ -0,0 +1,54 @@
+package spine
+
+import (
+	"testing"
+)
+
+func assertProperColoring(t *testing.T, g *Graph[string, int], colors map[string]int) {
+	t.Helper()
+	for _, e := range g.Edges() {
+		if colors[e.From] == colors[e.To] {
+			t.Errorf("adjacent nodes %s and %s share color %d", e.From, e.To, colors[e.From])
+		}
+	}
+}
+
+func TestGreedyColoringComplete(t *testing.T) {
+	g := NewGraph[string, int](false)
+	ids := []string{"a", "b", "c", "d"}
+	for _, id := range ids {
+		g.AddNode(id, id)
+	}
+	for i := range ids {
+		for j := i + 1; j < len(ids); j++ {
+			g.AddEdge(ids[i], ids[j], 0, 1)
+		}
+	}
+
+	colors := GreedyColoring(g)
+	assertProperColoring(t, g, colors)
+	if n := ColorCount(colors); n != 4 {
+		t.Fatalf("expected K4 to need 4 colors, got %d", n)
+	}
+}
+
+func TestGreedyColoringBipartite(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"l1", "l2", "l3", "r1", "r2"} {
+		g.AddNode(id, id)
+	}
+	for _, l := range []string{"l1", "l2", "l3"} {
+		for _, r := range []string{"r1", "r2"} {
+			g.AddEdge(l, r, 0, 1)
+		}
+	}
+
+	colors := GreedyColoring(g)
+	assertProperColoring(t, g, colors)
+	if n := ColorCount(colors); n != 2 {
+		t.Fatalf("expected bipartite graph to need 2 colors, got %d", n)
+	}
+	if colors["l1"] != 0 || colors["r1"] != 1 {
+		t.Errorf("expected deterministic assignment, got %v", colors)
+	}
+}
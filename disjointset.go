@@ -0,0 +1,93 @@
+package spine
+
+import "sort"
+
+// DisjointSet is a union-find structure over string elements using path
+// compression and union by rank. The zero value is not usable; create one
+// with NewDisjointSet.
+type DisjointSet struct {
+	parent map[string]string
+	rank   map[string]int
+	count  int
+}
+
+// NewDisjointSet returns a disjoint set with each of ids in its own set.
+func NewDisjointSet(ids ...string) *DisjointSet {
+	ds := &DisjointSet{
+		parent: make(map[string]string, len(ids)),
+		rank:   make(map[string]int, len(ids)),
+	}
+	for _, id := range ids {
+		ds.MakeSet(id)
+	}
+	return ds
+}
+
+// MakeSet adds x as a singleton set. It is a no-op if x is already present.
+func (ds *DisjointSet) MakeSet(x string) {
+	if _, ok := ds.parent[x]; ok {
+		return
+	}
+	ds.parent[x] = x
+	ds.count++
+}
+
+// Find returns the representative of x's set, adding x as a singleton if it
+// is not yet present.
+func (ds *DisjointSet) Find(x string) string {
+	ds.MakeSet(x)
+	for ds.parent[x] != x {
+		ds.parent[x] = ds.parent[ds.parent[x]] // path compression
+		x = ds.parent[x]
+	}
+	return x
+}
+
+// Union merges the sets containing x and y and reports whether they were
+// previously separate. Missing elements are added first.
+func (ds *DisjointSet) Union(x, y string) bool {
+	rx, ry := ds.Find(x), ds.Find(y)
+	if rx == ry {
+		return false
+	}
+	if ds.rank[rx] < ds.rank[ry] {
+		rx, ry = ry, rx
+	}
+	ds.parent[ry] = rx
+	if ds.rank[rx] == ds.rank[ry] {
+		ds.rank[rx]++
+	}
+	ds.count--
+	return true
+}
+
+// Connected reports whether x and y are in the same set.
+func (ds *DisjointSet) Connected(x, y string) bool {
+	return ds.Find(x) == ds.Find(y)
+}
+
+// Count returns the number of disjoint sets.
+func (ds *DisjointSet) Count() int {
+	return ds.count
+}
+
+// Sets returns the current partition. Each set is sorted, and sets are
+// ordered by their first element. Unlike Find, Sets does not modify ds, so
+// concurrent calls are safe.
+func (ds *DisjointSet) Sets() [][]string {
+	groups := make(map[string][]string)
+	for x := range ds.parent {
+		root := x
+		for ds.parent[root] != root {
+			root = ds.parent[root]
+		}
+		groups[root] = append(groups[root], x)
+	}
+	sets := make([][]string, 0, len(groups))
+	for _, members := range groups {
+		sort.Strings(members)
+		sets = append(sets, members)
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i][0] < sets[j][0] })
+	return sets
+}
@@ -0,0 +1,47 @@
+package spine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDisjointSetPartition(t *testing.T) {
+	ds := NewDisjointSet("a", "b", "c", "d", "e")
+	if ds.Count() != 5 {
+		t.Fatalf("expected 5 singleton sets, got %d", ds.Count())
+	}
+
+	if !ds.Union("a", "b") || !ds.Union("c", "d") || !ds.Union("b", "d") {
+		t.Fatal("expected unions of separate sets to report true")
+	}
+	if ds.Union("a", "c") {
+		t.Fatal("expected union within one set to report false")
+	}
+
+	want := [][]string{{"a", "b", "c", "d"}, {"e"}}
+	if got := ds.Sets(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if ds.Count() != 2 {
+		t.Fatalf("expected 2 sets, got %d", ds.Count())
+	}
+}
+
+func TestDisjointSetFindConsistent(t *testing.T) {
+	ds := NewDisjointSet()
+	ds.Union("x", "y")
+	ds.Union("y", "z")
+
+	root := ds.Find("x")
+	for _, id := range []string{"x", "y", "z"} {
+		if ds.Find(id) != root {
+			t.Errorf("expected %s to share root %s, got %s", id, root, ds.Find(id))
+		}
+	}
+	if ds.Connected("x", "w") {
+		t.Error("expected w to be separate")
+	}
+	if ds.Find("w") != "w" || ds.Count() != 2 {
+		t.Errorf("expected w added as a singleton, count %d", ds.Count())
+	}
+}
@@ -0,0 +1,108 @@
+package spine
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DOTOptions controls MarshalDOT output. The zero value labels nodes with
+// their IDs and edges with their weights.
+type DOTOptions[N, E any] struct {
+	// Name is the graph name; it defaults to "G".
+	Name string
+	// LabelKey names a node metadata key whose value is used as the node
+	// label when set.
+	LabelKey string
+	// ColorKey names a node metadata key whose value is looked up in Colors
+	// to fill the node, e.g. ColorKey "status" with Colors {"done": "green"}.
+	ColorKey string
+	Colors   map[string]string
+	// HideWeights omits the weight labels on edges.
+	HideWeights bool
+	// NodeAttrs and EdgeAttrs return extra attributes for a node or edge.
+	// They are applied last, so they override the attributes above.
+	NodeAttrs func(n Node[N]) map[string]string
+	EdgeAttrs func(e Edge[E]) map[string]string
+}
+
+// MarshalDOT renders g in the Graphviz DOT language. Nodes are written
+// sorted by ID and edges by From then To; undirected edges are written once.
+// A nil opts is the same as the zero DOTOptions.
+func MarshalDOT[N, E any](g *Graph[N, E], opts *DOTOptions[N, E]) ([]byte, error) {
+	if opts == nil {
+		opts = &DOTOptions[N, E]{}
+	}
+	name := opts.Name
+	if name == "" {
+		name = "G"
+	}
+	kind, arrow := "graph", "--"
+	if g.Directed {
+		kind, arrow = "digraph", "->"
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s {\n", kind, dotQuote(name))
+	for _, n := range g.Nodes() {
+		attrs := map[string]string{"label": n.ID}
+		if store := g.nodeMeta[n.ID]; store != nil {
+			if v, ok := store.Get(opts.LabelKey); ok && opts.LabelKey != "" {
+				attrs["label"] = fmt.Sprint(v)
+			}
+			if v, ok := store.Get(opts.ColorKey); ok && opts.ColorKey != "" {
+				if color, ok := opts.Colors[fmt.Sprint(v)]; ok {
+					attrs["style"] = "filled"
+					attrs["fillcolor"] = color
+				}
+			}
+		}
+		if opts.NodeAttrs != nil {
+			for k, v := range opts.NodeAttrs(n) {
+				attrs[k] = v
+			}
+		}
+		fmt.Fprintf(&buf, "  %s%s;\n", dotQuote(n.ID), dotAttrs(attrs))
+	}
+
+	edges, _ := g.EdgesPage(0, 0)
+	for _, e := range edges {
+		attrs := make(map[string]string)
+		if !opts.HideWeights {
+			attrs["label"] = strconv.FormatFloat(e.Weight, 'g', -1, 64)
+		}
+		if opts.EdgeAttrs != nil {
+			for k, v := range opts.EdgeAttrs(e) {
+				attrs[k] = v
+			}
+		}
+		fmt.Fprintf(&buf, "  %s %s %s%s;\n", dotQuote(e.From), arrow, dotQuote(e.To), dotAttrs(attrs))
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+// dotAttrs formats an attribute list sorted by key, or "" if attrs is empty.
+func dotAttrs(attrs map[string]string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + dotQuote(attrs[k])
+	}
+	return " [" + strings.Join(parts, ", ") + "]"
+}
+
+// dotQuote returns s as a double-quoted DOT string.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,53 @@
+package spine
+
+import "testing"
+
+func TestMarshalDOT(t *testing.T) {
+	g := NewGraph[string, string](true)
+	g.AddNode("b", "")
+	g.AddNode("a", "")
+	g.AddEdge("a", "b", "", 2.5)
+	g.NodeMeta("a").Set("name", `Say "hi"`)
+	g.NodeMeta("a").Set("status", "done")
+	g.NodeMeta("b").Set("status", "pending")
+
+	data, err := MarshalDOT(g, &DOTOptions[string, string]{
+		Name:     "plan",
+		LabelKey: "name",
+		ColorKey: "status",
+		Colors:   map[string]string{"done": "palegreen"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph "plan" {
+  "a" [fillcolor="palegreen", label="Say \"hi\"", style="filled"];
+  "b" [label="b"];
+  "a" -> "b" [label="2.5"];
+}
+`
+	if string(data) != want {
+		t.Errorf("unexpected DOT output:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestMarshalDOTUndirected(t *testing.T) {
+	g := NewGraph[int, int](false)
+	g.AddNode("x", 0)
+	g.AddNode("y", 0)
+	g.AddEdge("y", "x", 7, 1)
+
+	data, _ := MarshalDOT(g, &DOTOptions[int, int]{
+		HideWeights: true,
+		EdgeAttrs:   func(e Edge[int]) map[string]string { return map[string]string{"color": "red"} },
+	})
+	want := `graph "G" {
+  "x" [label="x"];
+  "y" [label="y"];
+  "x" -- "y" [color="red"];
+}
+`
+	if string(data) != want {
+		t.Errorf("unexpected DOT output:\n%s\nwant:\n%s", data, want)
+	}
+}
@@ -0,0 +1,143 @@
+// Package format converts spine graphs to and from the GraphML and GEXF
+// interchange formats, so they can be exchanged with tools such as Gephi,
+// yEd and NetworkX. Node and edge metadata become typed attributes; node
+// and edge data are stored as JSON in a reserved attribute.
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/imran31415/spine"
+)
+
+// Reserved attribute names.
+const (
+	// DataAttr holds the JSON encoding of a node's or edge's data.
+	DataAttr = "spine.data"
+	// WeightAttr holds an edge's weight in GraphML. Edge metadata under this
+	// key is not exported.
+	WeightAttr = "weight"
+)
+
+// Attribute types shared by GraphML and GEXF.
+const (
+	typeString  = "string"
+	typeBoolean = "boolean"
+	typeLong    = "long"
+	typeDouble  = "double"
+)
+
+// attrSet collects the attributes of one domain (graph, node or edge) and
+// settles on a type for each: a key whose values disagree on type is
+// exported as a string.
+type attrSet map[string]string
+
+func (a attrSet) observe(name string, v any) {
+	t := valueType(v)
+	if cur, ok := a[name]; ok && cur != t {
+		t = typeString
+	}
+	a[name] = t
+}
+
+func (a attrSet) observeStore(s *spine.Store) {
+	s.Range(func(k string, v any) bool {
+		a.observe(k, v)
+		return true
+	})
+}
+
+// names returns the attribute names in sorted order.
+func (a attrSet) names() []string {
+	names := make([]string, 0, len(a))
+	for n := range a {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// valueType returns the attribute type for a metadata value. Values that
+// are not strings, booleans or numbers are exported as JSON strings.
+func valueType(v any) string {
+	switch v.(type) {
+	case bool:
+		return typeBoolean
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return typeLong
+	case float32, float64:
+		return typeDouble
+	}
+	return typeString
+}
+
+// encodeValue formats a metadata value as an attribute value.
+func encodeValue(v any) string {
+	if valueType(v) != typeString {
+		return fmt.Sprint(v)
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// decodeValue parses an attribute value of type t. Values that do not parse
+// are kept as strings.
+func decodeValue(s, t string) any {
+	switch t {
+	case typeBoolean:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case typeLong, "int", "integer":
+		if n, err := strconv.Atoi(s); err == nil {
+			return n
+		}
+	case typeDouble, "float":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+// encodeData returns the JSON encoding of node or edge data.
+func encodeData(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// nodeAttrs collects the attributes used by g's node metadata.
+func nodeAttrs[N, E any](g *spine.Graph[N, E]) attrSet {
+	attrs := attrSet{}
+	for _, n := range g.Nodes() {
+		if g.NodeMetaCount(n.ID) > 0 {
+			attrs.observeStore(g.NodeMeta(n.ID))
+		}
+	}
+	return attrs
+}
+
+// edgeAttrs collects the attributes used by g's edge metadata, except
+// WeightAttr.
+func edgeAttrs[N, E any](g *spine.Graph[N, E], edges []spine.Edge[E]) attrSet {
+	attrs := attrSet{}
+	for _, e := range edges {
+		if g.EdgeMetaCount(e.From, e.To) > 0 {
+			attrs.observeStore(g.EdgeMeta(e.From, e.To))
+		}
+	}
+	delete(attrs, WeightAttr)
+	return attrs
+}
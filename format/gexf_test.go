@@ -0,0 +1,54 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGEXFRoundTrip(t *testing.T) {
+	g := sampleGraph()
+	var buf bytes.Buffer
+	if err := EncodeGEXF(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `defaultedgetype="directed"`) {
+		t.Errorf("missing defaultedgetype:\n%s", buf.String())
+	}
+	got, err := DecodeGEXF[task, string](&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameGraph(t, got, g, false)
+}
+
+func TestDecodeGEXFForeign(t *testing.T) {
+	const doc = `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" version="1.3">
+  <graph defaultedgetype="undirected">
+    <attributes class="node">
+      <attribute id="0" title="score" type="float"/>
+    </attributes>
+    <nodes>
+      <node id="x" label="X"><attvalues><attvalue for="0" value="0.25"/></attvalues></node>
+      <node id="y" label="Y"/>
+    </nodes>
+    <edges>
+      <edge id="0" source="x" target="y" weight="3"/>
+    </edges>
+  </graph>
+</gexf>`
+	g, err := DecodeGEXF[any, any](strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Directed || g.Order() != 2 || g.Size() != 1 {
+		t.Fatalf("got directed=%v order=%d size=%d", g.Directed, g.Order(), g.Size())
+	}
+	if e, _ := g.GetEdge("x", "y"); e.Weight != 3 {
+		t.Errorf("weight = %v, want 3", e.Weight)
+	}
+	if v, _ := g.NodeMeta("x").Get("score"); v != 0.25 {
+		t.Errorf("score = %v, want 0.25", v)
+	}
+}
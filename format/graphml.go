@@ -0,0 +1,226 @@
+package format
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/imran31415/spine"
+)
+
+const graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+type graphML struct {
+	XMLName xml.Name   `xml:"graphml"`
+	Xmlns   string     `xml:"xmlns,attr,omitempty"`
+	Keys    []gmlKey   `xml:"key"`
+	Graphs  []gmlGraph `xml:"graph"`
+}
+
+type gmlKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type gmlGraph struct {
+	ID          string    `xml:"id,attr,omitempty"`
+	EdgeDefault string    `xml:"edgedefault,attr"`
+	Data        []gmlData `xml:"data"`
+	Nodes       []gmlNode `xml:"node"`
+	Edges       []gmlEdge `xml:"edge"`
+}
+
+type gmlNode struct {
+	ID   string    `xml:"id,attr"`
+	Data []gmlData `xml:"data"`
+}
+
+type gmlEdge struct {
+	ID     string    `xml:"id,attr,omitempty"`
+	Source string    `xml:"source,attr"`
+	Target string    `xml:"target,attr"`
+	Data   []gmlData `xml:"data"`
+}
+
+type gmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// gmlKeys assigns GraphML key IDs to the attributes of one domain.
+type gmlKeys struct {
+	domain string
+	ids    map[string]string // attribute name -> key ID
+}
+
+func (k *gmlKeys) add(doc *graphML, name, typ string) {
+	id := fmt.Sprintf("%s%d", k.domain[:1], len(k.ids))
+	k.ids[name] = id
+	doc.Keys = append(doc.Keys, gmlKey{ID: id, For: k.domain, Name: name, Type: typ})
+}
+
+func (k *gmlKeys) data(s *spine.Store) []gmlData {
+	var out []gmlData
+	s.Range(func(name string, v any) bool {
+		if id, ok := k.ids[name]; ok {
+			out = append(out, gmlData{Key: id, Value: encodeValue(v)})
+		}
+		return true
+	})
+	return out
+}
+
+// EncodeGraphML writes g to w as GraphML. The graph config and node and
+// edge metadata become <data> attributes typed from their values; node and
+// edge data are stored as JSON under the DataAttr attribute and edge weights
+// under WeightAttr. Schemas are not exported.
+func EncodeGraphML[N, E any](w io.Writer, g *spine.Graph[N, E]) error {
+	doc := &graphML{Xmlns: graphMLNamespace}
+	graph := gmlGraph{ID: "G", EdgeDefault: "undirected"}
+	if g.Directed {
+		graph.EdgeDefault = "directed"
+	}
+	edges, _ := g.EdgesPage(0, 0)
+
+	graphKeys := &gmlKeys{domain: "graph", ids: map[string]string{}}
+	config := attrSet{}
+	config.observeStore(g.GraphMeta())
+	for _, name := range config.names() {
+		graphKeys.add(doc, name, config[name])
+	}
+	nodeKeys := &gmlKeys{domain: "node", ids: map[string]string{}}
+	nodeKeys.add(doc, DataAttr, typeString)
+	nattrs := nodeAttrs(g)
+	delete(nattrs, DataAttr)
+	for _, name := range nattrs.names() {
+		nodeKeys.add(doc, name, nattrs[name])
+	}
+	edgeKeys := &gmlKeys{domain: "edge", ids: map[string]string{}}
+	edgeKeys.add(doc, DataAttr, typeString)
+	edgeKeys.add(doc, WeightAttr, typeDouble)
+	eattrs := edgeAttrs(g, edges)
+	delete(eattrs, DataAttr)
+	for _, name := range eattrs.names() {
+		edgeKeys.add(doc, name, eattrs[name])
+	}
+
+	graph.Data = graphKeys.data(g.GraphMeta())
+	for _, n := range g.Nodes() {
+		data, err := encodeData(n.Data)
+		if err != nil {
+			return fmt.Errorf("node %q: %w", n.ID, err)
+		}
+		node := gmlNode{ID: n.ID, Data: []gmlData{{Key: nodeKeys.ids[DataAttr], Value: data}}}
+		if g.NodeMetaCount(n.ID) > 0 {
+			node.Data = append(node.Data, nodeKeys.data(g.NodeMeta(n.ID))...)
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	for i, e := range edges {
+		data, err := encodeData(e.Data)
+		if err != nil {
+			return fmt.Errorf("edge %s->%s: %w", e.From, e.To, err)
+		}
+		edge := gmlEdge{ID: fmt.Sprintf("e%d", i), Source: e.From, Target: e.To, Data: []gmlData{
+			{Key: edgeKeys.ids[DataAttr], Value: data},
+			{Key: edgeKeys.ids[WeightAttr], Value: encodeValue(e.Weight)},
+		}}
+		if g.EdgeMetaCount(e.From, e.To) > 0 {
+			edge.Data = append(edge.Data, edgeKeys.data(g.EdgeMeta(e.From, e.To))...)
+		}
+		graph.Edges = append(graph.Edges, edge)
+	}
+	doc.Graphs = []gmlGraph{graph}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// DecodeGraphML reads the first graph of a GraphML document. Attributes are
+// converted to metadata using their declared types, except DataAttr, which
+// is decoded as the node or edge data, and the edge WeightAttr. Edges
+// without a weight get weight 1. Defaults declared on keys are ignored.
+func DecodeGraphML[N, E any](r io.Reader) (*spine.Graph[N, E], error) {
+	var doc graphML
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("graphml: %w", err)
+	}
+	if len(doc.Graphs) == 0 {
+		return nil, fmt.Errorf("graphml: no graph element")
+	}
+	keys := make(map[string]gmlKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		keys[k.ID] = k
+	}
+	graph := doc.Graphs[0]
+	g := spine.NewGraph[N, E](graph.EdgeDefault != "undirected")
+
+	config := g.GraphMeta()
+	for _, d := range graph.Data {
+		if k, ok := keys[d.Key]; ok {
+			config.Set(k.Name, decodeValue(d.Value, k.Type))
+		}
+	}
+	for _, n := range graph.Nodes {
+		var data N
+		meta := make(map[string]any)
+		for _, d := range n.Data {
+			k, ok := keys[d.Key]
+			if !ok {
+				continue
+			}
+			if k.Name == DataAttr {
+				if err := json.Unmarshal([]byte(d.Value), &data); err != nil {
+					return nil, fmt.Errorf("graphml: node %q: %w", n.ID, err)
+				}
+				continue
+			}
+			meta[k.Name] = decodeValue(d.Value, k.Type)
+		}
+		g.AddNodeWithMeta(n.ID, data, meta)
+	}
+	for _, e := range graph.Edges {
+		var data E
+		weight := 1.0
+		meta := make(map[string]any)
+		for _, d := range e.Data {
+			k, ok := keys[d.Key]
+			if !ok {
+				continue
+			}
+			switch k.Name {
+			case DataAttr:
+				if err := json.Unmarshal([]byte(d.Value), &data); err != nil {
+					return nil, fmt.Errorf("graphml: edge %s->%s: %w", e.Source, e.Target, err)
+				}
+			case WeightAttr:
+				if f, ok := decodeValue(d.Value, typeDouble).(float64); ok {
+					weight = f
+				}
+			default:
+				meta[k.Name] = decodeValue(d.Value, k.Type)
+			}
+		}
+		if err := g.AddEdge(e.Source, e.Target, data, weight); err != nil {
+			return nil, fmt.Errorf("graphml: %w", err)
+		}
+		if len(meta) > 0 {
+			store := g.EdgeMeta(e.Source, e.Target)
+			for k, v := range meta {
+				store.Set(k, v)
+			}
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,120 @@
+package format
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/imran31415/spine"
+)
+
+type task struct {
+	Title string `json:"title"`
+}
+
+// sampleGraph returns a graph exercising node data, typed metadata, edge
+// weights, edge metadata and graph config.
+func sampleGraph() *spine.Graph[task, string] {
+	g := spine.NewGraph[task, string](true)
+	g.AddNodeWithMeta("a", task{Title: "design"}, map[string]any{"status": "done", "cost": 3, "ratio": 0.5, "ok": true})
+	g.AddNodeWithMeta("b", task{Title: "build"}, map[string]any{"status": "pending"})
+	g.AddNode("c", task{Title: "ship"})
+	g.AddEdge("a", "b", "blocks", 2.5)
+	g.AddEdge("b", "c", "", 1)
+	g.EdgeMeta("a", "b").Set("kind", "hard")
+	g.GraphMeta().Set("owner", "ops")
+	return g
+}
+
+func assertSameGraph(t *testing.T, got, want *spine.Graph[task, string], config bool) {
+	t.Helper()
+	if got.Directed != want.Directed {
+		t.Errorf("directed = %v, want %v", got.Directed, want.Directed)
+	}
+	if !reflect.DeepEqual(got.Nodes(), want.Nodes()) {
+		t.Errorf("nodes = %v, want %v", got.Nodes(), want.Nodes())
+	}
+	gotEdges, _ := got.EdgesPage(0, 0)
+	wantEdges, _ := want.EdgesPage(0, 0)
+	if !reflect.DeepEqual(gotEdges, wantEdges) {
+		t.Errorf("edges = %v, want %v", gotEdges, wantEdges)
+	}
+	for _, n := range want.Nodes() {
+		if g, w := storeMap(got.NodeMeta(n.ID)), storeMap(want.NodeMeta(n.ID)); !reflect.DeepEqual(g, w) {
+			t.Errorf("node %s meta = %v, want %v", n.ID, g, w)
+		}
+	}
+	if g, w := storeMap(got.EdgeMeta("a", "b")), storeMap(want.EdgeMeta("a", "b")); !reflect.DeepEqual(g, w) {
+		t.Errorf("edge meta = %v, want %v", g, w)
+	}
+	if config {
+		if v, _ := got.GraphMeta().Get("owner"); v != "ops" {
+			t.Errorf("config owner = %v, want ops", v)
+		}
+	}
+}
+
+func storeMap(s *spine.Store) map[string]any {
+	m := make(map[string]any)
+	s.Range(func(k string, v any) bool {
+		m[k] = v
+		return true
+	})
+	return m
+}
+
+func TestGraphMLRoundTrip(t *testing.T) {
+	g := sampleGraph()
+	var buf bytes.Buffer
+	if err := EncodeGraphML(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `edgedefault="directed"`) {
+		t.Errorf("missing edgedefault:\n%s", buf.String())
+	}
+	got, err := DecodeGraphML[task, string](&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameGraph(t, got, g, true)
+}
+
+func TestDecodeGraphMLForeign(t *testing.T) {
+	const doc = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="color" attr.type="string"/>
+  <key id="d1" for="edge" attr.name="capacity" attr.type="int"/>
+  <graph edgedefault="undirected">
+    <node id="x"><data key="d0">red</data></node>
+    <node id="y"/>
+    <edge source="x" target="y"><data key="d1">4</data></edge>
+  </graph>
+</graphml>`
+	g, err := DecodeGraphML[any, any](strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Directed || g.Order() != 2 || g.Size() != 1 {
+		t.Fatalf("got directed=%v order=%d size=%d", g.Directed, g.Order(), g.Size())
+	}
+	if e, _ := g.GetEdge("y", "x"); e.Weight != 1 {
+		t.Errorf("weight = %v, want default 1", e.Weight)
+	}
+	if v, _ := g.NodeMeta("x").Get("color"); v != "red" {
+		t.Errorf("color = %v, want red", v)
+	}
+	if v, _ := g.EdgeMeta("x", "y").Get("capacity"); v != 4 {
+		t.Errorf("capacity = %v (%T), want 4", v, v)
+	}
+}
+
+func TestDecodeGraphMLErrors(t *testing.T) {
+	if _, err := DecodeGraphML[any, any](strings.NewReader(`<graphml/>`)); err == nil {
+		t.Error("expected error for missing graph")
+	}
+	const dangling = `<graphml><graph><node id="a"/><edge source="a" target="b"/></graph></graphml>`
+	if _, err := DecodeGraphML[any, any](strings.NewReader(dangling)); err == nil {
+		t.Error("expected error for edge to unknown node")
+	}
+}
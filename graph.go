@@ -28,7 +28,16 @@ type Graph[N, E any] struct {
 	in           map[string]map[string]Edge[E] // to -> from -> edge
 	nodeMeta     map[string]*Store             // node ID -> metadata store
 	edgeMeta     map[string]map[string]*Store  // from -> to -> metadata store
+	dirEdgeMeta  map[string]map[string]*Store  // from -> to -> per-direction store; undirected graphs only
+	graphMeta    *Store                        // graph-level metadata, serialized as config
 	rawEdgeCount int                           // total entries in out maps (for O(1) Size)
+	components   *DisjointSet                  // incremental weak components; nil unless tracking
+	compStale    bool                          // components need a rebuild after a removal, done by the next add
+	history      *history[N, E]                // op log; nil unless created with NewGraphWithHistory
+	idLess       func(a, b string) bool        // node ID order for sorted results; nil means lexical
+	listeners    []changeListener[N, E]        // OnChange callbacks, in registration order
+	nextListener int                           // ID for the next OnChange registration
+	metaIndexes  map[string]*metaIndex         // node metadata key -> value index; see IndexMetaKey
 }
 
 // NewGraph creates a new graph. If directed is true, edges are one-way.
@@ -52,6 +61,28 @@ func (g *Graph[N, E]) AddNode(id string, data N) {
 	if g.in[id] == nil {
 		g.in[id] = make(map[string]Edge[E])
 	}
+	if g.components != nil {
+		if g.compStale {
+			g.rebuildComponents()
+		} else {
+			g.components.MakeSet(id)
+		}
+	}
+	g.record(HistoryOp[N, E]{Kind: OpAddNode, ID: id, Node: data})
+}
+
+// AddNodeWithMeta adds or overwrites a node like AddNode and sets each key in
+// meta on the node's metadata store. Existing metadata keys not present in meta
+// are preserved.
+func (g *Graph[N, E]) AddNodeWithMeta(id string, data N, meta map[string]any) {
+	g.AddNode(id, data)
+	if len(meta) == 0 {
+		return
+	}
+	store := g.NodeMeta(id)
+	for k, v := range meta {
+		store.Set(k, v)
+	}
 }
 
 // AddEdge adds an edge between two nodes. Both nodes must already exist.
@@ -77,14 +108,35 @@ func (g *Graph[N, E]) AddEdge(from, to string, data E, weight float64) error {
 		g.out[to][from] = rev
 		g.in[from][to] = rev
 	}
+	if g.components != nil {
+		if g.compStale {
+			g.rebuildComponents()
+		} else {
+			g.components.Union(from, to)
+		}
+	}
+	g.record(HistoryOp[N, E]{Kind: OpAddEdge, From: from, To: to, Edge: data, Weight: weight})
 	return nil
 }
 
+// AddEdgeAuto adds an edge like AddEdge, first creating either endpoint that
+// does not exist with nodeData. Existing nodes are left untouched.
+func (g *Graph[N, E]) AddEdgeAuto(from, to string, data E, weight float64, nodeData N) {
+	if !g.HasNode(from) {
+		g.AddNode(from, nodeData)
+	}
+	if !g.HasNode(to) {
+		g.AddNode(to, nodeData)
+	}
+	_ = g.AddEdge(from, to, data, weight)
+}
+
 // RemoveNode removes a node and all its incident edges.
 func (g *Graph[N, E]) RemoveNode(id string) {
 	if !g.HasNode(id) {
 		return
 	}
+	g.record(HistoryOp[N, E]{Kind: OpRemoveNode, ID: id})
 	// Count and remove outgoing edges
 	g.rawEdgeCount -= len(g.out[id])
 	for to := range g.out[id] {
@@ -102,13 +154,23 @@ func (g *Graph[N, E]) RemoveNode(id string) {
 	delete(g.out, id)
 	delete(g.in, id)
 	delete(g.nodes, id)
+	g.compStale = true
+	if store := g.nodeMeta[id]; store != nil {
+		// The store may outlive the node; stop it reporting to g.
+		store.onChange = nil
+	}
 	delete(g.nodeMeta, id)
+	for _, idx := range g.metaIndexes {
+		idx.remove(id)
+	}
 	// Clean up edge metadata involving this node.
-	delete(g.edgeMeta, id)
-	for from, m := range g.edgeMeta {
-		delete(m, id)
-		if len(m) == 0 {
-			delete(g.edgeMeta, from)
+	for _, stores := range []map[string]map[string]*Store{g.edgeMeta, g.dirEdgeMeta} {
+		delete(stores, id)
+		for from, m := range stores {
+			delete(m, id)
+			if len(m) == 0 {
+				delete(stores, from)
+			}
 		}
 	}
 }
@@ -117,6 +179,8 @@ func (g *Graph[N, E]) RemoveNode(id string) {
 func (g *Graph[N, E]) RemoveEdge(from, to string) {
 	if _, existed := g.out[from][to]; existed {
 		g.rawEdgeCount--
+		g.compStale = true
+		g.record(HistoryOp[N, E]{Kind: OpRemoveEdge, From: from, To: to})
 	}
 	delete(g.out[from], to)
 	delete(g.in[to], from)
@@ -128,14 +192,19 @@ func (g *Graph[N, E]) RemoveEdge(from, to string) {
 		delete(g.in[from], to)
 	}
 	// Clean up edge metadata.
-	f, t := from, to
-	if !g.Directed && t < f {
-		f, t = t, f
-	}
-	if m, ok := g.edgeMeta[f]; ok {
-		delete(m, t)
+	f, t := g.edgeMetaKey(from, to)
+	deleteEdgeStore(g.edgeMeta, f, t)
+	deleteEdgeStore(g.dirEdgeMeta, from, to)
+	deleteEdgeStore(g.dirEdgeMeta, to, from)
+}
+
+// deleteEdgeStore removes the from -> to store, dropping the inner map once
+// it is empty.
+func deleteEdgeStore(stores map[string]map[string]*Store, from, to string) {
+	if m, ok := stores[from]; ok {
+		delete(m, to)
 		if len(m) == 0 {
-			delete(g.edgeMeta, f)
+			delete(stores, from)
 		}
 	}
 }
@@ -171,6 +240,38 @@ func (g *Graph[N, E]) HasEdge(from, to string) bool {
 	return false
 }
 
+// SetIDLess sets the order in which node IDs appear in sorted results:
+// Nodes, Neighbors, OutEdges, InEdges, EdgesPage and the traversal and query
+// functions that sort IDs. A nil less restores the default lexical order.
+// Copies inherit the comparator. Serialized snapshots are always written in
+// lexical order, so the comparator does not affect Marshal or Fingerprint.
+func (g *Graph[N, E]) SetIDLess(less func(a, b string) bool) {
+	g.idLess = less
+}
+
+// NaturalIDLess orders IDs with embedded numbers compared by value, so "v2"
+// sorts before "v10". It is intended for use with SetIDLess.
+func NaturalIDLess(a, b string) bool {
+	return naturalLess(a, b)
+}
+
+// lessID reports whether node ID a sorts before b under the graph's order.
+func (g *Graph[N, E]) lessID(a, b string) bool {
+	if g.idLess != nil {
+		return g.idLess(a, b)
+	}
+	return a < b
+}
+
+// sortIDs sorts ids in place under the graph's order.
+func (g *Graph[N, E]) sortIDs(ids []string) {
+	if g.idLess == nil {
+		sort.Strings(ids)
+		return
+	}
+	sort.Slice(ids, func(i, j int) bool { return g.idLess(ids[i], ids[j]) })
+}
+
 // Neighbors returns the IDs of nodes adjacent to the given node (outgoing direction).
 func (g *Graph[N, E]) Neighbors(id string) []string {
 	m := g.out[id]
@@ -178,7 +279,7 @@ func (g *Graph[N, E]) Neighbors(id string) []string {
 	for to := range m {
 		result = append(result, to)
 	}
-	sort.Strings(result)
+	g.sortIDs(result)
 	return result
 }
 
@@ -189,7 +290,7 @@ func (g *Graph[N, E]) OutEdges(id string) []Edge[E] {
 	for _, e := range m {
 		result = append(result, e)
 	}
-	sort.Slice(result, func(i, j int) bool { return result[i].To < result[j].To })
+	sort.Slice(result, func(i, j int) bool { return g.lessID(result[i].To, result[j].To) })
 	return result
 }
 
@@ -200,7 +301,7 @@ func (g *Graph[N, E]) InEdges(id string) []Edge[E] {
 	for _, e := range m {
 		result = append(result, e)
 	}
-	sort.Slice(result, func(i, j int) bool { return result[i].From < result[j].From })
+	sort.Slice(result, func(i, j int) bool { return g.lessID(result[i].From, result[j].From) })
 	return result
 }
 
@@ -210,7 +311,7 @@ func (g *Graph[N, E]) Nodes() []Node[N] {
 	for _, n := range g.nodes {
 		result = append(result, n)
 	}
-	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	sort.Slice(result, func(i, j int) bool { return g.lessID(result[i].ID, result[j].ID) })
 	return result
 }
 
@@ -237,6 +338,50 @@ func (g *Graph[N, E]) Edges() []Edge[E] {
 	return result
 }
 
+// EdgesPage returns a page of edges sorted by From then To, along with the
+// total edge count. Undirected edges are listed once with From <= To.
+// If limit <= 0, all edges from offset onward are returned.
+func (g *Graph[N, E]) EdgesPage(offset, limit int) ([]Edge[E], int) {
+	froms := make([]string, 0, len(g.out))
+	for from := range g.out {
+		froms = append(froms, from)
+	}
+	g.sortIDs(froms)
+
+	var keys [][2]string
+	for _, from := range froms {
+		tos := make([]string, 0, len(g.out[from]))
+		for to := range g.out[from] {
+			if !g.Directed && to < from {
+				continue
+			}
+			tos = append(tos, to)
+		}
+		g.sortIDs(tos)
+		for _, to := range tos {
+			keys = append(keys, [2]string{from, to})
+		}
+	}
+	total := len(keys)
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+	selected := keys[offset:]
+	if limit > 0 && limit < len(selected) {
+		selected = selected[:limit]
+	}
+
+	result := make([]Edge[E], len(selected))
+	for i, k := range selected {
+		result[i] = g.out[k[0]][k[1]]
+	}
+	return result, total
+}
+
 // Order returns the number of nodes.
 func (g *Graph[N, E]) Order() int {
 	return len(g.nodes)
@@ -269,6 +414,7 @@ func (g *Graph[N, E]) Copy() *Graph[N, E] {
 		}
 	}
 	c.rawEdgeCount = g.rawEdgeCount
+	c.idLess = g.idLess
 	for id, store := range g.nodeMeta {
 		c.nodeMeta[id] = store.Copy()
 	}
@@ -278,9 +424,99 @@ func (g *Graph[N, E]) Copy() *Graph[N, E] {
 			c.edgeMeta[from][to] = store.Copy()
 		}
 	}
+	c.dirEdgeMeta = copyEdgeStores(g.dirEdgeMeta)
+	if g.graphMeta != nil {
+		c.graphMeta = g.graphMeta.Copy()
+	}
+	if g.components != nil {
+		c.TrackComponents(true)
+	}
+	for key := range g.metaIndexes {
+		c.IndexMetaKey(key)
+	}
 	return c
 }
 
+// Restore replaces the contents of g (directedness, nodes, edges and
+// metadata) with those of src, e.g. to roll g back to a snapshot taken with
+// Copy. What belongs to g itself is kept: its history, OnChange listeners,
+// ID order, component tracking and metadata indexes, which are rebuilt for
+// the new contents. The replacement is recorded in history and reported to
+// listeners as the node, edge and metadata operations that turn the old
+// contents into the new, so StateAt and observers stay in step. src must not
+// be used afterwards.
+func (g *Graph[N, E]) Restore(src *Graph[N, E]) {
+	var ops []HistoryOp[N, E]
+	if g.history != nil || len(g.listeners) > 0 {
+		ops = g.restoreOps(src)
+	}
+	g.Directed = src.Directed
+	g.nodes, g.out, g.in = src.nodes, src.out, src.in
+	g.nodeMeta, g.edgeMeta, g.dirEdgeMeta, g.graphMeta = src.nodeMeta, src.edgeMeta, src.dirEdgeMeta, src.graphMeta
+	g.rawEdgeCount = src.rawEdgeCount
+	if g.components != nil {
+		g.rebuildComponents()
+	}
+	keys := g.IndexedMetaKeys()
+	g.metaIndexes = nil
+	for _, key := range keys {
+		g.IndexMetaKey(key)
+	}
+	// Rebind the stores to g (or unhook them if g does not watch).
+	g.watchAllMeta()
+	for _, op := range ops {
+		g.record(op)
+	}
+}
+
+// TrackComponents enables or disables incremental tracking of weakly
+// connected components. While enabled, AddNode and AddEdge keep a
+// DisjointSet up to date so ConnectedComponents avoids a full traversal on
+// add-only workloads. A removal invalidates the set: ConnectedComponents
+// traverses until the next AddNode or AddEdge rebuilds it. Queries never
+// modify the set, so ConnectedComponents stays safe under a read lock.
+func (g *Graph[N, E]) TrackComponents(enable bool) {
+	if !enable {
+		g.components = nil
+		g.compStale = false
+		return
+	}
+	g.rebuildComponents()
+}
+
+// trackedComponents returns the tracked component set, or nil if tracking is
+// disabled or the set is out of date. The caller must not modify it.
+func (g *Graph[N, E]) trackedComponents() *DisjointSet {
+	if g.compStale {
+		return nil
+	}
+	return g.components
+}
+
+func (g *Graph[N, E]) rebuildComponents() {
+	ds := NewDisjointSet()
+	for id := range g.nodes {
+		ds.MakeSet(id)
+	}
+	for from, m := range g.out {
+		for to := range m {
+			ds.Union(from, to)
+		}
+	}
+	g.components = ds
+	g.compStale = false
+}
+
+// GraphMeta returns the graph-level metadata store, creating it lazily.
+// It holds per-graph settings and is serialized as the snapshot's config.
+func (g *Graph[N, E]) GraphMeta() *Store {
+	if g.graphMeta == nil {
+		g.graphMeta = NewStore()
+		g.watchMeta(g.graphMeta, MetaScopeGraph, "", "", "")
+	}
+	return g.graphMeta
+}
+
 // NodeMeta returns the metadata store for the given node, creating it lazily.
 // Returns nil if the node does not exist.
 func (g *Graph[N, E]) NodeMeta(id string) *Store {
@@ -289,6 +525,7 @@ func (g *Graph[N, E]) NodeMeta(id string) *Store {
 	}
 	if g.nodeMeta[id] == nil {
 		g.nodeMeta[id] = NewStore()
+		g.watchMeta(g.nodeMeta[id], MetaScopeNode, id, "", "")
 	}
 	return g.nodeMeta[id]
 }
@@ -300,19 +537,68 @@ func (g *Graph[N, E]) EdgeMeta(from, to string) *Store {
 	if !g.HasEdge(from, to) {
 		return nil
 	}
-	f, t := from, to
-	if !g.Directed && t < f {
-		f, t = t, f
-	}
+	f, t := g.edgeMetaKey(from, to)
 	if g.edgeMeta[f] == nil {
 		g.edgeMeta[f] = make(map[string]*Store)
 	}
 	if g.edgeMeta[f][t] == nil {
 		g.edgeMeta[f][t] = NewStore()
+		g.watchMeta(g.edgeMeta[f][t], MetaScopeEdge, "", f, t)
 	}
 	return g.edgeMeta[f][t]
 }
 
+// DirectedEdgeMeta returns a metadata store for one direction of the given
+// edge, creating it lazily. On undirected graphs, DirectedEdgeMeta("a","b")
+// and DirectedEdgeMeta("b","a") are separate stores, independent of the
+// shared EdgeMeta store, for attributes that differ by direction while the
+// topology stays symmetric. On directed graphs it is the same as EdgeMeta.
+// Returns nil if the edge does not exist.
+func (g *Graph[N, E]) DirectedEdgeMeta(from, to string) *Store {
+	if g.Directed {
+		return g.EdgeMeta(from, to)
+	}
+	if !g.HasEdge(from, to) {
+		return nil
+	}
+	if g.dirEdgeMeta == nil {
+		g.dirEdgeMeta = make(map[string]map[string]*Store)
+	}
+	if g.dirEdgeMeta[from] == nil {
+		g.dirEdgeMeta[from] = make(map[string]*Store)
+	}
+	if g.dirEdgeMeta[from][to] == nil {
+		g.dirEdgeMeta[from][to] = NewStore()
+		g.watchMeta(g.dirEdgeMeta[from][to], MetaScopeDirectedEdge, "", from, to)
+	}
+	return g.dirEdgeMeta[from][to]
+}
+
+// copyEdgeStores deep-copies a from -> to -> store map. A nil map stays nil.
+func copyEdgeStores(stores map[string]map[string]*Store) map[string]map[string]*Store {
+	if stores == nil {
+		return nil
+	}
+	c := make(map[string]map[string]*Store, len(stores))
+	for from, m := range stores {
+		c[from] = make(map[string]*Store, len(m))
+		for to, store := range m {
+			c[from][to] = store.Copy()
+		}
+	}
+	return c
+}
+
+// edgeMetaKey returns the key under which edge metadata is stored. Undirected
+// edges use the canonical min(from,to) -> max(from,to) order so both access
+// directions resolve to one store and serialize identically.
+func (g *Graph[N, E]) edgeMetaKey(from, to string) (string, string) {
+	if !g.Directed && to < from {
+		return to, from
+	}
+	return from, to
+}
+
 // NodeMetaCount returns the number of metadata entries for the given node.
 // Returns 0 if the node doesn't exist or has no metadata store.
 func (g *Graph[N, E]) NodeMetaCount(id string) int {
@@ -325,10 +611,7 @@ func (g *Graph[N, E]) NodeMetaCount(id string) int {
 // EdgeMetaCount returns the number of metadata entries for the given edge.
 // Returns 0 if the edge doesn't exist or has no metadata store.
 func (g *Graph[N, E]) EdgeMetaCount(from, to string) int {
-	f, t := from, to
-	if !g.Directed && t < f {
-		f, t = t, f
-	}
+	f, t := g.edgeMetaKey(from, to)
 	if m, ok := g.edgeMeta[f]; ok {
 		if s, ok := m[t]; ok {
 			return s.Len()
@@ -336,3 +619,32 @@ func (g *Graph[N, E]) EdgeMetaCount(from, to string) int {
 	}
 	return 0
 }
+
+// GraphMetaCount returns the number of graph-level metadata entries, without
+// creating the store.
+func (g *Graph[N, E]) GraphMetaCount() int {
+	if g.graphMeta == nil {
+		return 0
+	}
+	return g.graphMeta.Len()
+}
+
+// DeleteExpiredMeta removes expired entries from every metadata store in the
+// graph (see Store.SetWithTTL) and returns how many were removed.
+func (g *Graph[N, E]) DeleteExpiredMeta() int {
+	n := 0
+	if g.graphMeta != nil {
+		n += g.graphMeta.DeleteExpired()
+	}
+	for _, store := range g.nodeMeta {
+		n += store.DeleteExpired()
+	}
+	for _, stores := range []map[string]map[string]*Store{g.edgeMeta, g.dirEdgeMeta} {
+		for _, m := range stores {
+			for _, store := range m {
+				n += store.DeleteExpired()
+			}
+		}
+	}
+	return n
+}
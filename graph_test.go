@@ -1,6 +1,8 @@
 package spine
 
 import (
+	"bytes"
+	"reflect"
 	"testing"
 )
 
@@ -42,6 +44,31 @@ func TestAddEdge(t *testing.T) {
 	}
 }
 
+func TestAddEdgeAuto(t *testing.T) {
+	g := NewGraph[string, int](true)
+	g.AddNode("a", "existing")
+
+	g.AddEdgeAuto("x", "y", 7, 2, "default")
+	if !g.HasNode("x") || !g.HasNode("y") {
+		t.Fatal("expected both endpoints to be created")
+	}
+	if n, _ := g.GetNode("x"); n.Data != "default" {
+		t.Errorf("expected default node data, got %q", n.Data)
+	}
+	e, ok := g.GetEdge("x", "y")
+	if !ok || e.Data != 7 || e.Weight != 2 {
+		t.Fatalf("expected edge x->y, got %+v", e)
+	}
+
+	g.AddEdgeAuto("a", "x", 0, 0, "default")
+	if n, _ := g.GetNode("a"); n.Data != "existing" {
+		t.Errorf("expected existing node untouched, got %q", n.Data)
+	}
+	if g.Order() != 3 || g.Size() != 2 {
+		t.Errorf("expected 3 nodes and 2 edges, got %d and %d", g.Order(), g.Size())
+	}
+}
+
 func TestRemoveNode(t *testing.T) {
 	g := NewGraph[int, int](true)
 	g.AddNode("a", 1)
@@ -168,6 +195,32 @@ func TestCopy(t *testing.T) {
 	}
 }
 
+func TestRestore(t *testing.T) {
+	g := NewGraph[string, int](true)
+	g.AddNode("a", "A")
+	g.NodeMeta("a").Set("tag", "x")
+	g.IndexMetaKey("tag")
+	snap := g.Copy()
+
+	events := 0
+	g.OnChange(func(HistoryOp[string, int]) { events++ })
+	g.AddNode("b", "B")
+	g.AddEdge("a", "b", 1, 1)
+	g.Restore(snap)
+	if g.HasNode("b") || g.Size() != 0 {
+		t.Fatalf("expected the snapshot contents, got order=%d size=%d", g.Order(), g.Size())
+	}
+
+	events = 0
+	g.NodeMeta("a").Set("tag", "y")
+	if events != 1 {
+		t.Errorf("expected listeners to see writes to restored stores, got %d events", events)
+	}
+	if got := g.FindNodesByMeta("tag", "y"); len(got) != 1 || got[0] != "a" {
+		t.Errorf("expected the index to follow restored stores, got %v", got)
+	}
+}
+
 func TestSelfLoop(t *testing.T) {
 	g := NewGraph[int, int](true)
 	g.AddNode("a", 1)
@@ -192,6 +245,86 @@ func TestDuplicateAddNode(t *testing.T) {
 	}
 }
 
+func TestAddNodeWithMeta(t *testing.T) {
+	g := NewGraph[string, int](true)
+	g.AddNodeWithMeta("a", "first", map[string]any{"owner": "x", "priority": 1})
+	g.AddNodeWithMeta("a", "second", map[string]any{"priority": 2, "tag": "core"})
+
+	n, _ := g.GetNode("a")
+	if n.Data != "second" {
+		t.Fatalf("expected data overwritten, got %q", n.Data)
+	}
+	store := g.NodeMeta("a")
+	if v, _ := store.Get("owner"); v != "x" {
+		t.Errorf("expected owner preserved, got %v", v)
+	}
+	if v, _ := store.Get("priority"); v != 2 {
+		t.Errorf("expected priority updated to 2, got %v", v)
+	}
+	if v, _ := store.Get("tag"); v != "core" {
+		t.Errorf("expected tag set, got %v", v)
+	}
+}
+
+func TestEdgesPage(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("c", "a", 0, 0)
+	g.AddEdge("a", "d", 0, 0)
+	g.AddEdge("b", "c", 0, 0)
+	g.AddEdge("a", "b", 0, 0)
+	g.AddEdge("c", "d", 0, 0)
+
+	want := []string{"a->b", "a->d", "b->c", "c->a", "c->d"}
+	var got []string
+	for offset := 0; offset < 6; offset += 2 {
+		page, total := g.EdgesPage(offset, 2)
+		if total != 5 {
+			t.Fatalf("expected total 5, got %d", total)
+		}
+		for _, e := range page {
+			got = append(got, e.From+"->"+e.To)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	if page, _ := g.EdgesPage(4, 10); len(page) != 1 || page[0].From != "c" || page[0].To != "d" {
+		t.Errorf("expected last page [c->d], got %v", page)
+	}
+	if page, _ := g.EdgesPage(10, 2); len(page) != 0 {
+		t.Errorf("expected empty page past the end, got %v", page)
+	}
+	if page, _ := g.EdgesPage(0, 0); len(page) != 5 {
+		t.Errorf("expected all edges with limit 0, got %d", len(page))
+	}
+}
+
+func TestEdgesPageUndirected(t *testing.T) {
+	g := NewGraph[string, int](false)
+	g.AddNode("a", "A")
+	g.AddNode("b", "B")
+	g.AddNode("c", "C")
+	g.AddEdge("b", "a", 0, 0)
+	g.AddEdge("c", "b", 0, 0)
+
+	page, total := g.EdgesPage(0, 0)
+	if total != 2 || len(page) != 2 {
+		t.Fatalf("expected 2 edges listed once, got %d (%v)", total, page)
+	}
+	if page[0].From != "a" || page[0].To != "b" || page[1].From != "b" || page[1].To != "c" {
+		t.Errorf("expected [a-b b-c], got %v", page)
+	}
+}
+
 func TestSizeCounter(t *testing.T) {
 	// Directed: add, overwrite, remove
 	g := NewGraph[string, int](true)
@@ -286,3 +419,43 @@ func TestEmptyGraphOperations(t *testing.T) {
 		t.Fatal("neighbors of nonexistent node should be empty")
 	}
 }
+
+func TestSetIDLess(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"v10", "v2", "v1"} {
+		g.AddNode(id, "")
+	}
+	g.AddEdge("v1", "v10", 0, 1)
+	g.AddEdge("v1", "v2", 0, 1)
+	before, _ := Marshal(g, nil)
+
+	g.SetIDLess(NaturalIDLess)
+	var ids []string
+	for _, n := range g.Nodes() {
+		ids = append(ids, n.ID)
+	}
+	want := []string{"v1", "v2", "v10"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("Nodes() = %v, want %v", ids, want)
+	}
+	if got := g.Neighbors("v1"); !reflect.DeepEqual(got, []string{"v2", "v10"}) {
+		t.Errorf("Neighbors = %v", got)
+	}
+	if got, _ := TopologicalSort(g); !reflect.DeepEqual(got, want) {
+		t.Errorf("TopologicalSort = %v", got)
+	}
+	if got := g.Copy().Nodes(); got[2].ID != "v10" {
+		t.Errorf("expected Copy to keep the ID order, got %v", got)
+	}
+
+	// Snapshots stay lexical.
+	after, _ := Marshal(g, nil)
+	if !bytes.Equal(before, after) {
+		t.Error("expected SetIDLess not to change the serialized form")
+	}
+
+	g.SetIDLess(nil)
+	if got := g.Nodes(); got[1].ID != "v10" {
+		t.Errorf("expected lexical order after reset, got %v", got)
+	}
+}
@@ -0,0 +1,360 @@
+package spine
+
+import (
+	"reflect"
+	"sort"
+)
+
+// HistoryOpKind identifies the kind of mutation recorded in a graph's history.
+type HistoryOpKind string
+
+const (
+	OpAddNode    HistoryOpKind = "add_node"
+	OpAddEdge    HistoryOpKind = "add_edge"
+	OpRemoveNode HistoryOpKind = "remove_node"
+	OpRemoveEdge HistoryOpKind = "remove_edge"
+	OpSetMeta    HistoryOpKind = "set_meta"
+	OpDeleteMeta HistoryOpKind = "delete_meta"
+)
+
+// Metadata scopes of OpSetMeta and OpDeleteMeta.
+const (
+	MetaScopeNode         = "node"
+	MetaScopeEdge         = "edge"
+	MetaScopeGraph        = "graph"
+	MetaScopeDirectedEdge = "directed_edge"
+)
+
+// HistoryOp is one recorded mutation. ID is set for node operations and
+// node metadata; From and To for edge operations and edge metadata. Scope
+// says which store a metadata operation applies to. Metadata values are
+// recorded as is, so mutating a stored map or slice in place also changes
+// its history.
+type HistoryOp[N, E any] struct {
+	Kind   HistoryOpKind
+	ID     string
+	From   string
+	To     string
+	Node   N
+	Edge   E
+	Weight float64
+	Scope  string
+	Key    string
+	Value  any
+}
+
+// history is the append-only op log of a graph created with
+// NewGraphWithHistory. When the log is bounded, ops that fall off the front
+// are folded into base, the state before the first retained op.
+type history[N, E any] struct {
+	ops     []HistoryOp[N, E]
+	maxOps  int
+	dropped int
+	base    *Graph[N, E]
+}
+
+// NewGraphWithHistory creates a graph like NewGraph that records every
+// mutation (nodes, edges and metadata) in an append-only log, so past states
+// can be rebuilt with StateAt. If maxOps > 0, only the most recent maxOps
+// operations are retained.
+func NewGraphWithHistory[N, E any](directed bool, maxOps int) *Graph[N, E] {
+	g := NewGraph[N, E](directed)
+	g.history = &history[N, E]{maxOps: maxOps}
+	return g
+}
+
+// History returns the retained operations, oldest first. It is nil for
+// graphs without history.
+func (g *Graph[N, E]) History() []HistoryOp[N, E] {
+	if g.history == nil {
+		return nil
+	}
+	return append([]HistoryOp[N, E](nil), g.history.ops...)
+}
+
+// HistoryLen returns the total number of operations recorded since the graph
+// was created, including any no longer retained.
+func (g *Graph[N, E]) HistoryLen() int {
+	if g.history == nil {
+		return 0
+	}
+	return g.history.dropped + len(g.history.ops)
+}
+
+// StateAt returns a new graph holding the state after the first n recorded
+// operations. It returns nil if the graph has no history or n is outside
+// the retained window [HistoryLen()-len(History()), HistoryLen()]. The
+// returned graph does not record history.
+func (g *Graph[N, E]) StateAt(n int) *Graph[N, E] {
+	h := g.history
+	if h == nil || n < h.dropped || n > h.dropped+len(h.ops) {
+		return nil
+	}
+	var state *Graph[N, E]
+	if h.base != nil {
+		state = h.base.Copy()
+	} else {
+		state = NewGraph[N, E](g.Directed)
+	}
+	for _, op := range h.ops[:n-h.dropped] {
+		state.applyOp(op)
+	}
+	return state
+}
+
+// changeListener is a callback registered with OnChange.
+type changeListener[N, E any] struct {
+	id int
+	fn func(op HistoryOp[N, E])
+}
+
+// OnChange registers fn to be called on every mutation of g (nodes, edges
+// and metadata), described as the HistoryOp that history would record. A
+// node removal is reported before the node and its edges are dropped.
+// Listeners run synchronously, in registration order, and must not mutate g.
+// Listeners belong to this graph value and are not carried over by Copy. The
+// returned function unregisters fn.
+func (g *Graph[N, E]) OnChange(fn func(op HistoryOp[N, E])) (cancel func()) {
+	id := g.nextListener
+	g.nextListener++
+	g.listeners = append(g.listeners, changeListener[N, E]{id: id, fn: fn})
+	if len(g.listeners) == 1 && g.history == nil {
+		// Stores created before the first listener are not watched yet.
+		g.watchAllMeta()
+	}
+	return func() {
+		for i, l := range g.listeners {
+			if l.id == id {
+				g.listeners = append(g.listeners[:i:i], g.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// record appends op to the history, folding the oldest op into the base
+// state if the log is over its bound, and notifies OnChange listeners.
+func (g *Graph[N, E]) record(op HistoryOp[N, E]) {
+	for _, l := range g.listeners {
+		l.fn(op)
+	}
+	h := g.history
+	if h == nil {
+		return
+	}
+	h.ops = append(h.ops, op)
+	if h.maxOps <= 0 || len(h.ops) <= h.maxOps {
+		return
+	}
+	if h.base == nil {
+		h.base = NewGraph[N, E](g.Directed)
+	}
+	h.base.applyOp(h.ops[0])
+	h.ops[0] = HistoryOp[N, E]{}
+	h.ops = h.ops[1:]
+	h.dropped++
+}
+
+// watchMeta makes store record its changes in g's history under scope,
+// report them to OnChange listeners and keep metadata indexes up to date.
+func (g *Graph[N, E]) watchMeta(store *Store, scope, id, from, to string) {
+	if g.history == nil && len(g.listeners) == 0 && len(g.metaIndexes) == 0 {
+		store.onChange = nil
+		return
+	}
+	store.onChange = func(key string, value any, deleted bool) {
+		if scope == MetaScopeNode {
+			g.updateMetaIndex(id, key, value, deleted)
+		}
+		op := HistoryOp[N, E]{Kind: OpSetMeta, Scope: scope, ID: id, From: from, To: to, Key: key, Value: value}
+		if deleted {
+			op.Kind = OpDeleteMeta
+		}
+		g.record(op)
+	}
+}
+
+// watchAllMeta watches every existing metadata store of g.
+func (g *Graph[N, E]) watchAllMeta() {
+	if g.graphMeta != nil {
+		g.watchMeta(g.graphMeta, MetaScopeGraph, "", "", "")
+	}
+	for id, store := range g.nodeMeta {
+		g.watchMeta(store, MetaScopeNode, id, "", "")
+	}
+	for from, m := range g.edgeMeta {
+		for to, store := range m {
+			g.watchMeta(store, MetaScopeEdge, "", from, to)
+		}
+	}
+	for from, m := range g.dirEdgeMeta {
+		for to, store := range m {
+			g.watchMeta(store, MetaScopeDirectedEdge, "", from, to)
+		}
+	}
+}
+
+// applyOp replays a recorded operation onto g.
+func (g *Graph[N, E]) applyOp(op HistoryOp[N, E]) {
+	switch op.Kind {
+	case OpAddNode:
+		g.AddNode(op.ID, op.Node)
+	case OpAddEdge:
+		_ = g.AddEdge(op.From, op.To, op.Edge, op.Weight)
+	case OpRemoveNode:
+		g.RemoveNode(op.ID)
+	case OpRemoveEdge:
+		g.RemoveEdge(op.From, op.To)
+	case OpSetMeta, OpDeleteMeta:
+		var store *Store
+		switch op.Scope {
+		case MetaScopeNode:
+			store = g.NodeMeta(op.ID)
+		case MetaScopeEdge:
+			store = g.EdgeMeta(op.From, op.To)
+		case MetaScopeGraph:
+			store = g.GraphMeta()
+		case MetaScopeDirectedEdge:
+			store = g.DirectedEdgeMeta(op.From, op.To)
+		}
+		if store == nil {
+			return
+		}
+		if op.Kind == OpSetMeta {
+			store.Set(op.Key, op.Value)
+		} else {
+			store.Delete(op.Key)
+		}
+	}
+}
+
+// restoreOps returns the operations that turn the contents of g into those
+// of src: node removals, edge removals, node and edge additions or updates,
+// then metadata changes (graph, node, edge and directed edge stores). Nodes,
+// edges and entries that are unchanged yield no operation.
+func (g *Graph[N, E]) restoreOps(src *Graph[N, E]) []HistoryOp[N, E] {
+	var ops []HistoryOp[N, E]
+	// A change of directedness replaces every node.
+	same := g.Directed == src.Directed
+	kept := func(id string) bool { return same && g.HasNode(id) && src.HasNode(id) }
+	keptEdge := func(from, to string) bool {
+		return kept(from) && kept(to) && g.HasEdge(from, to) && src.HasEdge(from, to)
+	}
+
+	var removed, ids []string
+	for id := range g.nodes {
+		if !kept(id) {
+			removed = append(removed, id)
+		}
+	}
+	g.sortIDs(removed)
+	for _, id := range removed {
+		ops = append(ops, HistoryOp[N, E]{Kind: OpRemoveNode, ID: id})
+	}
+	for _, e := range g.sortedEdges() {
+		if kept(e.From) && kept(e.To) && !src.HasEdge(e.From, e.To) {
+			ops = append(ops, HistoryOp[N, E]{Kind: OpRemoveEdge, From: e.From, To: e.To})
+		}
+	}
+
+	for id := range src.nodes {
+		ids = append(ids, id)
+	}
+	g.sortIDs(ids)
+	for _, id := range ids {
+		n := src.nodes[id]
+		if old, ok := g.nodes[id]; ok && reflect.DeepEqual(old.Data, n.Data) {
+			continue
+		}
+		ops = append(ops, HistoryOp[N, E]{Kind: OpAddNode, ID: id, Node: n.Data})
+	}
+	for _, e := range src.sortedEdges() {
+		if keptEdge(e.From, e.To) {
+			old, _ := g.GetEdge(e.From, e.To)
+			if old.Weight == e.Weight && reflect.DeepEqual(old.Data, e.Data) {
+				continue
+			}
+		}
+		ops = append(ops, HistoryOp[N, E]{Kind: OpAddEdge, From: e.From, To: e.To, Edge: e.Data, Weight: e.Weight})
+	}
+
+	ops = appendStoreOps(ops, g.graphMeta, src.graphMeta, HistoryOp[N, E]{Scope: MetaScopeGraph})
+	for _, id := range ids {
+		var old *Store
+		if kept(id) {
+			old = g.nodeMeta[id]
+		}
+		ops = appendStoreOps(ops, old, src.nodeMeta[id], HistoryOp[N, E]{Scope: MetaScopeNode, ID: id})
+	}
+	for _, scope := range []string{MetaScopeEdge, MetaScopeDirectedEdge} {
+		oldStores, newStores := g.edgeMeta, src.edgeMeta
+		if scope == MetaScopeDirectedEdge {
+			oldStores, newStores = g.dirEdgeMeta, src.dirEdgeMeta
+		}
+		for _, e := range src.sortedEdges() {
+			from, to := e.From, e.To
+			if scope == MetaScopeEdge {
+				from, to = src.edgeMetaKey(from, to)
+			}
+			pairs := [][2]string{{from, to}}
+			if scope == MetaScopeDirectedEdge && !src.Directed && from != to {
+				pairs = append(pairs, [2]string{to, from})
+			}
+			for _, p := range pairs {
+				var old *Store
+				if keptEdge(p[0], p[1]) {
+					old = oldStores[p[0]][p[1]]
+				}
+				ops = appendStoreOps(ops, old, newStores[p[0]][p[1]], HistoryOp[N, E]{Scope: scope, From: p[0], To: p[1]})
+			}
+		}
+	}
+	return ops
+}
+
+// sortedEdges returns the edges of g ordered by From then To, listing each
+// undirected edge once with From <= To.
+func (g *Graph[N, E]) sortedEdges() []Edge[E] {
+	edges := g.Edges()
+	if !g.Directed {
+		for i, e := range edges {
+			if g.lessID(e.To, e.From) {
+				edges[i].From, edges[i].To = e.To, e.From
+			}
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return g.lessID(edges[i].From, edges[j].From)
+		}
+		return g.lessID(edges[i].To, edges[j].To)
+	})
+	return edges
+}
+
+// appendStoreOps appends the OpSetMeta and OpDeleteMeta operations, scoped
+// like op, that turn the entries of old into those of cur. Either store may
+// be nil.
+func appendStoreOps[N, E any](ops []HistoryOp[N, E], old, cur *Store, op HistoryOp[N, E]) []HistoryOp[N, E] {
+	if old != nil {
+		for _, key := range old.Keys() {
+			if cur == nil || !cur.Has(key) {
+				op.Kind, op.Key, op.Value = OpDeleteMeta, key, nil
+				ops = append(ops, op)
+			}
+		}
+	}
+	if cur != nil {
+		cur.Range(func(key string, value any) bool {
+			if old != nil {
+				if v, ok := old.Get(key); ok && reflect.DeepEqual(v, value) {
+					return true
+				}
+			}
+			op.Kind, op.Key, op.Value = OpSetMeta, key, value
+			ops = append(ops, op)
+			return true
+		})
+	}
+	return ops
+}
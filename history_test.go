@@ -0,0 +1,154 @@
+package spine
+
+import "testing"
+
+func TestStateAt(t *testing.T) {
+	g := NewGraphWithHistory[string, int](true, 0)
+	g.AddNode("a", "A")
+	g.AddNode("b", "B")
+	g.AddEdge("a", "b", 7, 2)
+	g.NodeMeta("a").Set("owner", "x")
+	mid := g.HistoryLen()
+	snapshot, _ := Marshal(g, nil)
+
+	g.AddNode("c", "C")
+	g.AddEdge("b", "c", 0, 1)
+	g.NodeMeta("a").Delete("owner")
+	g.RemoveEdge("a", "b")
+	g.RemoveNode("b")
+
+	if mid != 4 || g.HistoryLen() != 9 {
+		t.Fatalf("expected 4 ops at mid and 9 total, got %d and %d", mid, g.HistoryLen())
+	}
+
+	past := g.StateAt(mid)
+	got, _ := Marshal(past, nil)
+	if string(got) != string(snapshot) {
+		t.Errorf("state at %d does not match the graph at that point:\n%s\nwant:\n%s", mid, got, snapshot)
+	}
+
+	now := g.StateAt(g.HistoryLen())
+	if Fingerprint(now) != Fingerprint(g) {
+		t.Error("replaying every op should reproduce the current graph")
+	}
+	if empty := g.StateAt(0); empty.Order() != 0 {
+		t.Errorf("expected empty graph at 0, got %d nodes", empty.Order())
+	}
+	if g.StateAt(10) != nil || g.StateAt(-1) != nil {
+		t.Error("expected nil outside the recorded range")
+	}
+	if NewGraph[string, int](true).StateAt(0) != nil {
+		t.Error("expected nil for a graph without history")
+	}
+}
+
+func TestStateAtAfterRestore(t *testing.T) {
+	g := NewGraphWithHistory[string, int](false, 0)
+	g.AddNode("a", "A")
+	g.AddNode("b", "B")
+	g.AddNode("c", "C")
+	g.AddEdge("a", "b", 1, 1)
+	g.AddEdge("b", "c", 2, 1)
+	g.GraphMeta().Set("name", "g")
+	g.NodeMeta("a").Set("owner", "x")
+	g.EdgeMeta("a", "b").Set("kind", "dep")
+	g.DirectedEdgeMeta("b", "a").Set("cost", 3)
+	snap := g.Copy()
+	want, _ := Marshal(g, nil)
+
+	g.RemoveNode("c")
+	g.AddNode("a", "A2")
+	g.AddNode("d", "D")
+	g.AddEdge("a", "d", 5, 2)
+	g.RemoveEdge("a", "b")
+	g.AddEdge("a", "b", 9, 1)
+	g.GraphMeta().Delete("name")
+	g.NodeMeta("a").Set("owner", "y")
+	g.NodeMeta("d").Set("tag", "new")
+	before := g.HistoryLen()
+
+	var seen []HistoryOp[string, int]
+	g.OnChange(func(op HistoryOp[string, int]) { seen = append(seen, op) })
+	g.Restore(snap)
+
+	if got := g.HistoryLen() - before; got == 0 || got != len(seen) {
+		t.Fatalf("expected the restore recorded and reported alike, got %d ops and %d events", got, len(seen))
+	}
+	if got, _ := Marshal(g.StateAt(g.HistoryLen()), nil); string(got) != string(want) {
+		t.Errorf("replaying the restore does not reproduce the snapshot:\n%s\nwant:\n%s", got, want)
+	}
+	if Fingerprint(g.StateAt(before)) == Fingerprint(g) {
+		t.Error("expected the state before the restore to differ")
+	}
+
+	// Restoring identical contents changes nothing.
+	seen = nil
+	g.Restore(g.Copy())
+	if len(seen) != 0 {
+		t.Errorf("expected no ops for an unchanged restore, got %v", seen)
+	}
+}
+
+func TestStateAtBounded(t *testing.T) {
+	g := NewGraphWithHistory[string, int](false, 3)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", 0, 1)
+	g.EdgeMeta("b", "a").Set("kind", "link")
+
+	if len(g.History()) != 3 || g.HistoryLen() != 6 {
+		t.Fatalf("expected 3 retained of 6 ops, got %d of %d", len(g.History()), g.HistoryLen())
+	}
+	if g.StateAt(2) != nil {
+		t.Error("expected nil for a state before the retained window")
+	}
+	if s := g.StateAt(4); s.Order() != 4 || s.Size() != 0 {
+		t.Errorf("expected 4 nodes and no edges at op 4, got %d and %d", s.Order(), s.Size())
+	}
+	if s := g.StateAt(5); s.Size() != 1 || s.EdgeMetaCount("a", "b") != 0 {
+		t.Errorf("expected the edge without metadata at op 5, got size %d", s.Size())
+	}
+	if Fingerprint(g.StateAt(6)) != Fingerprint(g) {
+		t.Error("expected full replay to match the current graph")
+	}
+}
+
+func TestOnChange(t *testing.T) {
+	g := NewGraph[string, string](true)
+	g.AddNode("a", "")
+	g.NodeMeta("a").Set("before", 1) // store created before any listener
+
+	var ops []HistoryOp[string, string]
+	cancel := g.OnChange(func(op HistoryOp[string, string]) { ops = append(ops, op) })
+
+	g.AddNode("b", "B")
+	g.AddEdge("a", "b", "dep", 2)
+	g.NodeMeta("a").Set("owner", "ana")
+	g.EdgeMeta("a", "b").Set("kind", "hard")
+	g.NodeMeta("a").Delete("before")
+	g.RemoveEdge("a", "b")
+	g.RemoveNode("b")
+
+	kinds := []HistoryOpKind{OpAddNode, OpAddEdge, OpSetMeta, OpSetMeta, OpDeleteMeta, OpRemoveEdge, OpRemoveNode}
+	if len(ops) != len(kinds) {
+		t.Fatalf("expected %d events, got %d: %+v", len(kinds), len(ops), ops)
+	}
+	for i, k := range kinds {
+		if ops[i].Kind != k {
+			t.Errorf("event %d: expected %s, got %s", i, k, ops[i].Kind)
+		}
+	}
+	if op := ops[3]; op.Scope != MetaScopeEdge || op.From != "a" || op.To != "b" || op.Key != "kind" {
+		t.Errorf("unexpected edge metadata event: %+v", op)
+	}
+	if op := ops[4]; op.Scope != MetaScopeNode || op.ID != "a" || op.Key != "before" {
+		t.Errorf("unexpected delete event: %+v", op)
+	}
+
+	cancel()
+	g.AddNode("c", "")
+	if len(ops) != len(kinds) {
+		t.Errorf("expected no events after cancel, got %+v", ops[len(kinds):])
+	}
+}
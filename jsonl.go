@@ -0,0 +1,291 @@
+package spine
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// JSONL record types. Each line of the JSONL form is one record.
+const (
+	jsonlGraph   = "graph"  // header: directedness
+	jsonlConfig  = "config" // graph-level metadata
+	jsonlNode    = "node"
+	jsonlEdge    = "edge"
+	jsonlDirEdge = "directed_edge" // per-direction metadata of an undirected edge
+)
+
+// jsonlRecord is a single line of the JSONL form. Fields not relevant to the
+// record type are omitted.
+type jsonlRecord struct {
+	Type     string          `json:"type"`
+	Directed *bool           `json:"directed,omitempty"`
+	ID       string          `json:"id,omitempty"`
+	From     string          `json:"from,omitempty"`
+	To       string          `json:"to,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+	Weight   float64         `json:"weight,omitempty"`
+	Meta     map[string]any  `json:"meta,omitempty"`
+	Schema   Schema          `json:"schema,omitempty"`
+}
+
+// MarshalJSONL writes g to w as newline-delimited JSON: a graph header, an
+// optional config record, then one record per node (sorted by ID) and per
+// edge (sorted by From then To), each carrying its metadata and schema.
+// Undirected edges are written once with From <= To, followed by any
+// per-direction metadata. It is MarshalTo with every section included.
+func MarshalJSONL[N, E any](g *Graph[N, E], w io.Writer) error {
+	return MarshalTo(w, g, nil)
+}
+
+// MarshalTo streams g to w in the JSONL form of MarshalJSONL, without
+// building the whole Snapshot in memory: records are encoded one at a time,
+// and edges are visited node by node, so memory beyond the graph itself
+// stays proportional to the node count rather than the edge count. Read the
+// output back with UnmarshalFrom.
+//
+// opts selects what is written as for Marshal; nil includes everything.
+// NodeIDs limits the stream to those nodes and the edges between them. With
+// Graph unset, records carry no data or weight and nodes and edges without
+// metadata are skipped. With Meta unset, records carry no metadata, and
+// Schemas controls whether schemas are written. Indent does not apply.
+// Config is always written, as in Marshal.
+func MarshalTo[N, E any](w io.Writer, g *Graph[N, E], opts *MarshalOptions) error {
+	if opts == nil {
+		opts = &MarshalOptions{Graph: true, Meta: true, Schemas: true}
+	}
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	directed := g.Directed
+	if err := enc.Encode(jsonlRecord{Type: jsonlGraph, Directed: &directed}); err != nil {
+		return err
+	}
+	if g.graphMeta != nil && g.graphMeta.Len() > 0 {
+		if err := enc.Encode(jsonlRecord{Type: jsonlConfig, Meta: g.graphMeta.live()}); err != nil {
+			return err
+		}
+	}
+
+	var ids []string
+	var include map[string]bool
+	if opts.NodeIDs != nil {
+		include = make(map[string]bool, len(opts.NodeIDs))
+		for _, id := range opts.NodeIDs {
+			if g.HasNode(id) && !include[id] {
+				include[id] = true
+				ids = append(ids, id)
+			}
+		}
+	} else {
+		ids = make([]string, 0, len(g.nodes))
+		for id := range g.nodes {
+			ids = append(ids, id)
+		}
+	}
+	g.sortIDs(ids)
+
+	// withMeta attaches store's metadata and schema to rec as opts allow and
+	// reports whether rec still carries anything when Graph is unset.
+	withMeta := func(rec *jsonlRecord, store *Store) bool {
+		if opts.Meta && store != nil {
+			rec.Meta, rec.Schema = jsonlMeta(store)
+			if !opts.Schemas {
+				rec.Schema = nil
+			}
+		}
+		return opts.Graph || rec.Meta != nil || rec.Schema != nil
+	}
+
+	for _, id := range ids {
+		rec := jsonlRecord{Type: jsonlNode, ID: id}
+		if !withMeta(&rec, g.nodeMeta[id]) {
+			continue
+		}
+		if opts.Graph {
+			data, err := json.Marshal(g.nodes[id].Data)
+			if err != nil {
+				return fmt.Errorf("node %q: %w", id, err)
+			}
+			rec.Data = data
+		}
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	for _, from := range ids {
+		tos := make([]string, 0, len(g.out[from]))
+		for to := range g.out[from] {
+			if (!g.Directed && to < from) || (include != nil && !include[to]) {
+				continue
+			}
+			tos = append(tos, to)
+		}
+		g.sortIDs(tos)
+		for _, to := range tos {
+			rec := jsonlRecord{Type: jsonlEdge, From: from, To: to}
+			if withMeta(&rec, g.edgeMeta[from][to]) {
+				if opts.Graph {
+					e := g.out[from][to]
+					data, err := json.Marshal(e.Data)
+					if err != nil {
+						return fmt.Errorf("edge %s->%s: %w", from, to, err)
+					}
+					rec.Data, rec.Weight = data, e.Weight
+				}
+				if err := enc.Encode(rec); err != nil {
+					return err
+				}
+			}
+			if g.Directed || !opts.Meta {
+				continue
+			}
+			for _, dir := range [][2]string{{from, to}, {to, from}} {
+				store := g.dirEdgeMeta[dir[0]][dir[1]]
+				if store == nil {
+					continue
+				}
+				rec := jsonlRecord{Type: jsonlDirEdge, From: dir[0], To: dir[1]}
+				if withMeta(&rec, store); rec.Meta == nil && rec.Schema == nil {
+					continue
+				}
+				if err := enc.Encode(rec); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+func jsonlMeta(s *Store) (map[string]any, Schema) {
+	if s.Len() == 0 {
+		return nil, s.GetSchema()
+	}
+	return s.live(), s.GetSchema()
+}
+
+// UnmarshalFrom reads a graph streamed by MarshalTo. It is the same as
+// UnmarshalJSONL.
+func UnmarshalFrom[N, E any](r io.Reader) (*Graph[N, E], error) {
+	return UnmarshalJSONL[N, E](r)
+}
+
+// UnmarshalJSONL builds a graph from newline-delimited JSON records as
+// written by MarshalJSONL, reading one line at a time. The graph is directed
+// unless a graph header says otherwise. Edges may appear before their
+// endpoint nodes; they are buffered until both endpoints exist, and an error
+// is returned at the end if any edge is still dangling. Per-direction edge
+// metadata is applied once all edges are read.
+func UnmarshalJSONL[N, E any](r io.Reader) (*Graph[N, E], error) {
+	g := NewGraph[N, E](true)
+	waiting := make(map[string][]jsonlRecord) // missing node ID -> edges blocked on it
+	var dirMeta []jsonlRecord
+	seenEdge := false
+
+	addEdge := func(rec jsonlRecord) error {
+		for _, id := range []string{rec.From, rec.To} {
+			if !g.HasNode(id) {
+				waiting[id] = append(waiting[id], rec)
+				return nil
+			}
+		}
+		var data E
+		if len(rec.Data) > 0 {
+			if err := json.Unmarshal(rec.Data, &data); err != nil {
+				return fmt.Errorf("edge %s->%s: %w", rec.From, rec.To, err)
+			}
+		}
+		g.AddEdge(rec.From, rec.To, data, rec.Weight)
+		if len(rec.Meta) > 0 || rec.Schema != nil {
+			applyJSONLMeta(g.EdgeMeta(rec.From, rec.To), rec)
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec jsonlRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		switch rec.Type {
+		case jsonlGraph:
+			if rec.Directed != nil {
+				if g.Order() > 0 || seenEdge {
+					return nil, fmt.Errorf("line %d: graph header after nodes or edges", line)
+				}
+				g.Directed = *rec.Directed
+			}
+		case jsonlConfig:
+			for k, v := range rec.Meta {
+				g.GraphMeta().Set(k, v)
+			}
+		case jsonlNode:
+			if rec.ID == "" {
+				return nil, fmt.Errorf("line %d: node without id", line)
+			}
+			var data N
+			if len(rec.Data) > 0 {
+				if err := json.Unmarshal(rec.Data, &data); err != nil {
+					return nil, fmt.Errorf("line %d: node %q: %w", line, rec.ID, err)
+				}
+			}
+			g.AddNode(rec.ID, data)
+			if len(rec.Meta) > 0 || rec.Schema != nil {
+				applyJSONLMeta(g.NodeMeta(rec.ID), rec)
+			}
+			blocked := waiting[rec.ID]
+			delete(waiting, rec.ID)
+			for _, e := range blocked {
+				if err := addEdge(e); err != nil {
+					return nil, err
+				}
+			}
+		case jsonlEdge:
+			seenEdge = true
+			if err := addEdge(rec); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		case jsonlDirEdge:
+			dirMeta = append(dirMeta, rec)
+		default:
+			return nil, fmt.Errorf("line %d: unknown record type %q", line, rec.Type)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(waiting) > 0 {
+		missing := make([]string, 0, len(waiting))
+		for id := range waiting {
+			missing = append(missing, id)
+		}
+		sort.Strings(missing)
+		e := waiting[missing[0]][0]
+		return nil, fmt.Errorf("edge %s->%s references missing node %q", e.From, e.To, missing[0])
+	}
+	for _, rec := range dirMeta {
+		if store := g.DirectedEdgeMeta(rec.From, rec.To); store != nil {
+			applyJSONLMeta(store, rec)
+		}
+	}
+	return g, nil
+}
+
+func applyJSONLMeta(s *Store, rec jsonlRecord) {
+	for k, v := range rec.Meta {
+		s.Set(k, v)
+	}
+	if rec.Schema != nil {
+		s.SetSchema(rec.Schema)
+		s.CoerceToSchema()
+	}
+}
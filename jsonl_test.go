@@ -0,0 +1,146 @@
+package spine
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalJSONLEdgeBeforeNodes(t *testing.T) {
+	input := strings.Join([]string{
+		`{"type":"graph","directed":true}`,
+		`{"type":"edge","from":"a","to":"b","data":"ab","weight":2,"meta":{"kind":"dep"}}`,
+		`{"type":"node","id":"b","data":"beta"}`,
+		`{"type":"edge","from":"b","to":"c","data":"bc","weight":1}`,
+		``,
+		`{"type":"node","id":"a","data":"alpha","meta":{"owner":"alice"}}`,
+		`{"type":"node","id":"c","data":"gamma"}`,
+	}, "\n")
+
+	g, err := UnmarshalJSONL[string, string](strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Order() != 3 || g.Size() != 2 {
+		t.Fatalf("expected 3 nodes and 2 edges, got %d and %d", g.Order(), g.Size())
+	}
+	e, ok := g.GetEdge("a", "b")
+	if !ok || e.Data != "ab" || e.Weight != 2 {
+		t.Errorf("unexpected buffered edge: %+v", e)
+	}
+	if v, _ := g.EdgeMeta("a", "b").Get("kind"); v != "dep" {
+		t.Errorf("expected buffered edge metadata, got %v", v)
+	}
+	if v, _ := g.NodeMeta("a").Get("owner"); v != "alice" {
+		t.Errorf("expected node metadata, got %v", v)
+	}
+}
+
+func TestUnmarshalJSONLDangling(t *testing.T) {
+	input := `{"type":"node","id":"a"}
+{"type":"edge","from":"a","to":"ghost"}`
+	if _, err := UnmarshalJSONL[string, string](strings.NewReader(input)); err == nil {
+		t.Fatal("expected error for dangling edge")
+	} else if !strings.Contains(err.Error(), "ghost") {
+		t.Errorf("expected error to name the missing node, got %v", err)
+	}
+
+	if _, err := UnmarshalJSONL[string, string](strings.NewReader(`{"type":"bogus"}`)); err == nil {
+		t.Error("expected error for unknown record type")
+	}
+}
+
+func TestJSONLRoundTrip(t *testing.T) {
+	g := NewGraph[string, string](false)
+	g.AddNode("a", "alpha")
+	g.AddNode("b", "beta")
+	g.AddNode("c", "gamma")
+	g.AddEdge("b", "a", "ab", 1.5)
+	g.AddEdge("b", "c", "bc", 2)
+	g.NodeMeta("a").Set("lang", "go")
+	g.NodeMeta("a").SetSchema(Schema{"lang": {Type: FieldString, Required: true}})
+	g.EdgeMeta("a", "b").Set("count", float64(5))
+	g.GraphMeta().Set("default_status", "pending")
+
+	var buf bytes.Buffer
+	if err := MarshalJSONL(g, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 7 {
+		t.Errorf("expected 7 records, got %d:\n%s", lines, buf.String())
+	}
+
+	got, err := UnmarshalJSONL[string, string](&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Directed {
+		t.Error("expected undirected graph")
+	}
+	if Fingerprint(got) != Fingerprint(g) {
+		t.Error("expected round trip to preserve the graph")
+	}
+}
+
+func TestMarshalToStreamsDirectedEdgeMeta(t *testing.T) {
+	g := NewGraph[string, string](false)
+	g.AddNode("a", "alpha")
+	g.AddNode("b", "beta")
+	g.AddEdge("b", "a", "ab", 3)
+	g.DirectedEdgeMeta("b", "a").Set("lanes", "2")
+
+	var buf bytes.Buffer
+	if err := MarshalTo(&buf, g, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `{"type":"directed_edge","from":"b","to":"a","meta":{"lanes":"2"}}`) {
+		t.Errorf("expected a directed_edge record, got:\n%s", buf.String())
+	}
+	got, err := UnmarshalFrom[string, string](&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Fingerprint(got) != Fingerprint(g) {
+		t.Error("expected round trip to preserve the graph")
+	}
+	if got.DirectedEdgeMeta("a", "b").Len() != 0 {
+		t.Error("expected the reverse direction to stay empty")
+	}
+}
+
+func TestMarshalToOptions(t *testing.T) {
+	g := NewGraph[string, string](true)
+	for _, id := range []string{"a", "b", "c"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", "ab", 1)
+	g.AddEdge("b", "c", "bc", 2)
+	g.NodeMeta("b").Set("status", "done")
+	g.NodeMeta("b").SetSchema(Schema{"status": {Type: FieldString}})
+
+	var buf bytes.Buffer
+	if err := MarshalTo(&buf, g, &MarshalOptions{NodeIDs: []string{"b", "a", "zz"}, Graph: true, Meta: true}); err != nil {
+		t.Fatal(err)
+	}
+	sub, err := UnmarshalFrom[string, string](strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Order() != 2 || sub.Size() != 1 || !sub.HasEdge("a", "b") {
+		t.Errorf("expected subset a->b, got %d nodes and %d edges", sub.Order(), sub.Size())
+	}
+	if strings.Contains(buf.String(), `"schema"`) {
+		t.Errorf("expected schemas to be omitted:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := MarshalTo(&buf, g, &MarshalOptions{Meta: true}); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"graph","directed":true}
+{"type":"node","id":"b","meta":{"status":"done"}}
+`
+	if buf.String() != want {
+		t.Errorf("unexpected metadata-only stream:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -26,6 +26,7 @@ func (s *Server) handleOpenGraph(args json.RawMessage) (any, error) {
 	var a struct {
 		Name     string `json:"name"`
 		Directed *bool  `json:"directed,omitempty"`
+		Template string `json:"template,omitempty"`
 	}
 	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, err
@@ -33,6 +34,9 @@ func (s *Server) handleOpenGraph(args json.RawMessage) (any, error) {
 	if err := requireName(a.Name); err != nil {
 		return nil, err
 	}
+	if a.Template != "" {
+		return s.mgr.OpenFromTemplate(a.Name, a.Template)
+	}
 	directed := true
 	if a.Directed != nil {
 		directed = *a.Directed
@@ -41,14 +45,23 @@ func (s *Server) handleOpenGraph(args json.RawMessage) (any, error) {
 }
 
 func (s *Server) handleSaveGraph(args json.RawMessage) (any, error) {
-	var a nameArg
+	var a struct {
+		Name            string `json:"name"`
+		ExpectedVersion *int   `json:"expected_version,omitempty"`
+	}
 	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, err
 	}
 	if err := requireName(a.Name); err != nil {
 		return nil, err
 	}
-	if err := s.mgr.Save(a.Name); err != nil {
+	var err error
+	if a.ExpectedVersion != nil {
+		err = s.mgr.SaveIfVersion(a.Name, *a.ExpectedVersion)
+	} else {
+		err = s.mgr.Save(a.Name)
+	}
+	if err != nil {
 		return nil, err
 	}
 	return map[string]any{"ok": true}, nil
@@ -105,6 +118,102 @@ func (s *Server) handleReadNodes(args json.RawMessage) (any, error) {
 	return s.mgr.ReadNodes(req)
 }
 
+func (s *Server) handleReadEdges(args json.RawMessage) (any, error) {
+	var req api.ReadEdgesRequest
+	if err := json.Unmarshal(args, &req); err != nil {
+		return nil, err
+	}
+	if err := requireName(req.Graph); err != nil {
+		return nil, err
+	}
+	return s.mgr.ReadEdges(req)
+}
+
+func (s *Server) handleCountByMetaKey(args json.RawMessage) (any, error) {
+	var a struct {
+		Graph string `json:"graph"`
+		Key   string `json:"key"`
+	}
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, err
+	}
+	if err := requireName(a.Graph); err != nil {
+		return nil, err
+	}
+	if _, err := s.mgr.OpenGraph(a.Graph); err != nil {
+		return nil, err
+	}
+	with, without, err := s.mgr.CountByMetaKey(a.Graph, a.Key)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"key": a.Key, "with_key": with, "without_key": without}, nil
+}
+
+func (s *Server) handleGraphOutline(args json.RawMessage) (any, error) {
+	var a struct {
+		Graph string `json:"graph"`
+	}
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, err
+	}
+	if err := requireName(a.Graph); err != nil {
+		return nil, err
+	}
+	if _, err := s.mgr.OpenGraph(a.Graph); err != nil {
+		return nil, err
+	}
+	outline, err := s.mgr.Outline(a.Graph)
+	if err != nil {
+		return nil, err
+	}
+	return []ContentBlock{{Type: "text", Text: outline}}, nil
+}
+
+func (s *Server) handleTagNodes(args json.RawMessage) (any, error) {
+	var a struct {
+		Graph string         `json:"graph"`
+		IDs   []string       `json:"ids"`
+		Meta  map[string]any `json:"meta"`
+	}
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, err
+	}
+	if err := requireName(a.Graph); err != nil {
+		return nil, err
+	}
+	if _, err := s.mgr.OpenGraph(a.Graph); err != nil {
+		return nil, err
+	}
+	n, err := s.mgr.SetMetaBulk(a.Graph, a.IDs, a.Meta)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"keys_set": n}, nil
+}
+
+func (s *Server) handleUntagNodes(args json.RawMessage) (any, error) {
+	var a struct {
+		Graph string   `json:"graph"`
+		IDs   []string `json:"ids"`
+		Keys  []string `json:"keys"`
+	}
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, err
+	}
+	if err := requireName(a.Graph); err != nil {
+		return nil, err
+	}
+	if _, err := s.mgr.OpenGraph(a.Graph); err != nil {
+		return nil, err
+	}
+	n, err := s.mgr.DeleteMetaBulk(a.Graph, a.IDs, a.Keys)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"keys_deleted": n}, nil
+}
+
 func (s *Server) handleTransition(args json.RawMessage) (any, error) {
 	var req api.TransitionRequest
 	if err := json.Unmarshal(args, &req); err != nil {
@@ -116,6 +225,40 @@ func (s *Server) handleTransition(args json.RawMessage) (any, error) {
 	return s.mgr.Transition(req)
 }
 
+func (s *Server) handleAdvancePlan(args json.RawMessage) (any, error) {
+	var a struct {
+		Graph     string   `json:"graph"`
+		Completed []string `json:"completed"`
+	}
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, err
+	}
+	if err := requireName(a.Graph); err != nil {
+		return nil, err
+	}
+	return s.mgr.AdvancePlan(a.Graph, a.Completed)
+}
+
+func (s *Server) handlePlanLayers(args json.RawMessage) (any, error) {
+	var a struct {
+		Graph string `json:"graph"`
+	}
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, err
+	}
+	if err := requireName(a.Graph); err != nil {
+		return nil, err
+	}
+	if _, err := s.mgr.OpenGraph(a.Graph); err != nil {
+		return nil, err
+	}
+	layers, err := s.mgr.Layers(a.Graph)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"layers": layers, "depth": len(layers)}, nil
+}
+
 func (s *Server) handleRemove(args json.RawMessage) (any, error) {
 	var req api.RemoveRequest
 	if err := json.Unmarshal(args, &req); err != nil {
@@ -127,6 +270,17 @@ func (s *Server) handleRemove(args json.RawMessage) (any, error) {
 	return s.mgr.Remove(req)
 }
 
+func (s *Server) handleSimulateRemove(args json.RawMessage) (any, error) {
+	var req api.RemoveRequest
+	if err := json.Unmarshal(args, &req); err != nil {
+		return nil, err
+	}
+	if err := requireName(req.Graph); err != nil {
+		return nil, err
+	}
+	return s.mgr.SimulateRemove(req)
+}
+
 func (s *Server) handleSCC(args json.RawMessage) (any, error) {
 	var a struct {
 		Graph string `json:"graph"`
@@ -159,7 +313,7 @@ func (s *Server) handleMST(args json.RawMessage) (any, error) {
 	if err != nil {
 		return nil, err
 	}
-	edges, totalWeight, err := spine.MinimumSpanningTree(g)
+	edges, totalWeight, components, err := spine.MinimumSpanningTree(g)
 	if err != nil {
 		return nil, err
 	}
@@ -172,13 +326,30 @@ func (s *Server) handleMST(args json.RawMessage) (any, error) {
 	for i, e := range edges {
 		result[i] = edgeResult{From: e.From, To: e.To, Weight: e.Weight}
 	}
-	return map[string]any{"edges": result, "total_weight": totalWeight}, nil
+	return map[string]any{
+		"edges":        result,
+		"total_weight": totalWeight,
+		"components":   components,
+		"forest":       components > 1,
+	}, nil
+}
+
+// defaultMaxTraversal caps BFS and DFS results when the caller does not set
+// max_nodes, so a huge graph cannot produce an unbounded response.
+const defaultMaxTraversal = 10000
+
+func traverseOptions(maxNodes int) spine.TraverseOptions {
+	if maxNodes <= 0 {
+		maxNodes = defaultMaxTraversal
+	}
+	return spine.TraverseOptions{MaxNodes: maxNodes}
 }
 
 func (s *Server) handleBFS(args json.RawMessage) (any, error) {
 	var a struct {
-		Graph string `json:"graph"`
-		Start string `json:"start"`
+		Graph    string `json:"graph"`
+		Start    string `json:"start"`
+		MaxNodes int    `json:"max_nodes"`
 	}
 	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, err
@@ -190,14 +361,15 @@ func (s *Server) handleBFS(args json.RawMessage) (any, error) {
 	if err != nil {
 		return nil, err
 	}
-	order := spine.BFS(g, a.Start, nil)
-	return map[string]any{"order": order}, nil
+	order, truncated := spine.BFSWithOptions(g, a.Start, nil, traverseOptions(a.MaxNodes))
+	return map[string]any{"order": order, "truncated": truncated}, nil
 }
 
 func (s *Server) handleDFS(args json.RawMessage) (any, error) {
 	var a struct {
-		Graph string `json:"graph"`
-		Start string `json:"start"`
+		Graph    string `json:"graph"`
+		Start    string `json:"start"`
+		MaxNodes int    `json:"max_nodes"`
 	}
 	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, err
@@ -209,8 +381,8 @@ func (s *Server) handleDFS(args json.RawMessage) (any, error) {
 	if err != nil {
 		return nil, err
 	}
-	order := spine.DFS(g, a.Start, nil)
-	return map[string]any{"order": order}, nil
+	order, truncated := spine.DFSWithOptions(g, a.Start, nil, traverseOptions(a.MaxNodes))
+	return map[string]any{"order": order, "truncated": truncated}, nil
 }
 
 func (s *Server) handleShortestPath(args json.RawMessage) (any, error) {
@@ -236,6 +408,28 @@ func (s *Server) handleShortestPath(args json.RawMessage) (any, error) {
 	return map[string]any{"path": path, "cost": cost}, nil
 }
 
+func (s *Server) handleSlowestPaths(args json.RawMessage) (any, error) {
+	var a struct {
+		Graph string `json:"graph"`
+		Src   string `json:"src"`
+		N     int    `json:"n"`
+	}
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, err
+	}
+	if err := requireName(a.Graph); err != nil {
+		return nil, err
+	}
+	if _, err := s.mgr.OpenGraph(a.Graph); err != nil {
+		return nil, err
+	}
+	paths, err := s.mgr.SlowestPaths(a.Graph, a.Src, a.N)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"paths": paths}, nil
+}
+
 func (s *Server) handleTopologicalSort(args json.RawMessage) (any, error) {
 	var a struct {
 		Graph string `json:"graph"`
@@ -419,12 +613,52 @@ func (s *Server) handleValidateGraph(args json.RawMessage) (any, error) {
 	if err := requireName(a.Graph); err != nil {
 		return nil, err
 	}
-	g, err := s.mgr.OpenGraph(a.Graph)
-	if err != nil {
+	return s.mgr.ValidateGraph(a.Graph)
+}
+
+func (s *Server) handleValidatePlan(args json.RawMessage) (any, error) {
+	var a struct {
+		Graph string `json:"graph"`
+	}
+	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, err
 	}
-	result := spine.Validate(g)
-	return result, nil
+	if err := requireName(a.Graph); err != nil {
+		return nil, err
+	}
+	return s.mgr.ValidatePlan(a.Graph)
+}
+
+func (s *Server) handleUndo(args json.RawMessage) (any, error) {
+	var a struct {
+		Graph string `json:"graph"`
+	}
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, err
+	}
+	if err := requireName(a.Graph); err != nil {
+		return nil, err
+	}
+	if err := s.mgr.Undo(a.Graph); err != nil {
+		return nil, err
+	}
+	return map[string]any{"ok": true}, nil
+}
+
+func (s *Server) handleRedo(args json.RawMessage) (any, error) {
+	var a struct {
+		Graph string `json:"graph"`
+	}
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, err
+	}
+	if err := requireName(a.Graph); err != nil {
+		return nil, err
+	}
+	if err := s.mgr.Redo(a.Graph); err != nil {
+		return nil, err
+	}
+	return map[string]any{"ok": true}, nil
 }
 
 func (s *Server) handleDiffGraphs(args json.RawMessage) (any, error) {
@@ -4,7 +4,9 @@ package mcp
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -47,13 +49,23 @@ type ServerInfo struct {
 }
 
 type ServerCapabilities struct {
-	Tools *struct{} `json:"tools"`
+	Tools     *struct{} `json:"tools"`
+	Resources *struct{} `json:"resources,omitempty"`
 }
 
 type ToolDefinition struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	InputSchema any    `json:"inputSchema"`
+	Name         string        `json:"name"`
+	Description  string        `json:"description"`
+	InputSchema  any           `json:"inputSchema"`
+	OutputSchema any           `json:"outputSchema,omitempty"`
+	Examples     []ToolExample `json:"examples,omitempty"`
+}
+
+// ToolExample is a sample invocation advertised alongside a tool.
+type ToolExample struct {
+	Description string         `json:"description,omitempty"`
+	Arguments   map[string]any `json:"arguments"`
+	Result      any            `json:"result,omitempty"`
 }
 
 type ToolCallParams struct {
@@ -64,13 +76,32 @@ type ToolCallParams struct {
 type ToolCallResult struct {
 	Content []ContentBlock `json:"content"`
 	IsError bool           `json:"isError,omitempty"`
+	// Meta carries an "errorCode" for failed calls whose cause is a known
+	// api error, so clients can branch without parsing the message.
+	Meta map[string]any `json:"_meta,omitempty"`
 }
 
+// ContentBlock is a single item in a tool result. Text blocks set Text;
+// "resource_link" blocks set URI, Name and MimeType; "resource" blocks embed
+// the payload in Resource.
 type ContentBlock struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type     string            `json:"type"`
+	Text     string            `json:"text,omitempty"`
+	URI      string            `json:"uri,omitempty"`
+	Name     string            `json:"name,omitempty"`
+	MimeType string            `json:"mimeType,omitempty"`
+	Resource *ResourceContents `json:"resource,omitempty"`
+}
+
+// ResourceContents is the payload of an embedded resource.
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
 }
 
+// toolHandler runs a tool. Handlers normally return a value that is encoded
+// as a single JSON text block; returning []ContentBlock sends the blocks as is.
 type toolHandler func(json.RawMessage) (any, error)
 
 // Server is the MCP server wrapping a spine API Manager.
@@ -102,12 +133,11 @@ func (s *Server) Run(r io.Reader, w io.Writer) error {
 			continue
 		}
 
-		var req Request
-		if err := json.Unmarshal(line, &req); err != nil {
-			resp := &Response{
-				JSONRPC: "2.0",
-				ID:      json.RawMessage("null"),
-				Error:   &RPCError{Code: -32700, Message: "parse error"},
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 && trimmed[0] == '[' {
+			resp := s.handleBatch(trimmed)
+			if resp == nil {
+				// Batch of notifications — no response.
+				continue
 			}
 			if err := writeResponse(w, resp); err != nil {
 				return err
@@ -115,6 +145,14 @@ func (s *Server) Run(r io.Reader, w io.Writer) error {
 			continue
 		}
 
+		var req Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			if err := writeResponse(w, parseError()); err != nil {
+				return err
+			}
+			continue
+		}
+
 		resp := s.handle(&req)
 		if resp == nil {
 			// Notification — no response.
@@ -127,6 +165,51 @@ func (s *Server) Run(r io.Reader, w io.Writer) error {
 	return scanner.Err()
 }
 
+// handleBatch processes a JSON-RPC batch in order and returns the array of
+// responses, omitting those for notifications. It returns nil when there is
+// nothing to send, and a single error response for a malformed or empty batch.
+func (s *Server) handleBatch(data []byte) any {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return parseError()
+	}
+	if len(raw) == 0 {
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage("null"),
+			Error:   &RPCError{Code: -32600, Message: "invalid request: empty batch"},
+		}
+	}
+
+	var resps []*Response
+	for _, item := range raw {
+		var req Request
+		if err := json.Unmarshal(item, &req); err != nil {
+			resps = append(resps, &Response{
+				JSONRPC: "2.0",
+				ID:      json.RawMessage("null"),
+				Error:   &RPCError{Code: -32600, Message: "invalid request"},
+			})
+			continue
+		}
+		if resp := s.handle(&req); resp != nil {
+			resps = append(resps, resp)
+		}
+	}
+	if len(resps) == 0 {
+		return nil
+	}
+	return resps
+}
+
+func parseError() *Response {
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage("null"),
+		Error:   &RPCError{Code: -32700, Message: "parse error"},
+	}
+}
+
 func (s *Server) handle(req *Request) *Response {
 	switch req.Method {
 	case "initialize":
@@ -136,7 +219,7 @@ func (s *Server) handle(req *Request) *Response {
 			Result: InitializeResult{
 				ProtocolVersion: "2024-11-05",
 				ServerInfo:      ServerInfo{Name: "spine-mcp", Version: "0.1.0"},
-				Capabilities:    ServerCapabilities{Tools: &struct{}{}},
+				Capabilities:    ServerCapabilities{Tools: &struct{}{}, Resources: &struct{}{}},
 			},
 		}
 
@@ -151,6 +234,12 @@ func (s *Server) handle(req *Request) *Response {
 			Result:  map[string]any{"tools": s.defs},
 		}
 
+	case "resources/list":
+		return s.handleResourcesList(req)
+
+	case "resources/read":
+		return s.handleResourcesRead(req)
+
 	case "tools/call":
 		var params ToolCallParams
 		if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -176,9 +265,17 @@ func (s *Server) handle(req *Request) *Response {
 				Result: ToolCallResult{
 					Content: []ContentBlock{{Type: "text", Text: err.Error()}},
 					IsError: true,
+					Meta:    errorMeta(err),
 				},
 			}
 		}
+		if blocks, ok := result.([]ContentBlock); ok {
+			return &Response{
+				JSONRPC: "2.0",
+				ID:      req.ID,
+				Result:  ToolCallResult{Content: blocks},
+			}
+		}
 		text, _ := json.Marshal(result)
 		return &Response{
 			JSONRPC: "2.0",
@@ -197,6 +294,38 @@ func (s *Server) handle(req *Request) *Response {
 	}
 }
 
+// Error codes reported for known api errors. They follow JSON-RPC and MCP
+// conventions: -32002 for a missing resource, -32602 for invalid params.
+// -32003, in the server-defined range, reports a stale expected version.
+const (
+	codeNotFound      = -32002
+	codeConflict      = -32003
+	codeInvalidParams = -32602
+)
+
+// errorCode maps api sentinel errors to a JSON-RPC error code, or 0 if the
+// error is not recognised.
+func errorCode(err error) int {
+	switch {
+	case errors.Is(err, api.ErrGraphNotOpen), errors.Is(err, api.ErrNodeNotFound):
+		return codeNotFound
+	case errors.Is(err, api.ErrInvalidTransition), errors.Is(err, api.ErrInvalidGraphName):
+		return codeInvalidParams
+	case errors.Is(err, api.ErrVersionConflict):
+		return codeConflict
+	default:
+		return 0
+	}
+}
+
+func errorMeta(err error) map[string]any {
+	code := errorCode(err)
+	if code == 0 {
+		return nil
+	}
+	return map[string]any{"errorCode": code}
+}
+
 func (s *Server) addTool(name, description string, schema any, handler toolHandler) {
 	s.defs = append(s.defs, ToolDefinition{
 		Name:        name,
@@ -206,7 +335,19 @@ func (s *Server) addTool(name, description string, schema any, handler toolHandl
 	s.tools[name] = handler
 }
 
-func writeResponse(w io.Writer, resp *Response) error {
+// describeTool attaches an output schema and examples to a registered tool.
+func (s *Server) describeTool(name string, output any, examples ...ToolExample) {
+	for i := range s.defs {
+		if s.defs[i].Name == name {
+			s.defs[i].OutputSchema = output
+			s.defs[i].Examples = examples
+			return
+		}
+	}
+}
+
+// writeResponse writes a single response or a batch as one line of JSON.
+func writeResponse(w io.Writer, resp any) error {
 	data, err := json.Marshal(resp)
 	if err != nil {
 		return err
@@ -3,8 +3,10 @@ package mcp
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/imran31415/spine/api"
@@ -105,6 +107,78 @@ func TestInitialize(t *testing.T) {
 	if init.ServerInfo.Name != "spine-mcp" {
 		t.Errorf("unexpected server name: %s", init.ServerInfo.Name)
 	}
+	if init.Capabilities.Resources == nil {
+		t.Error("expected resources capability")
+	}
+}
+
+func TestResources(t *testing.T) {
+	srv := newTestServer(t)
+	for _, name := range []string{"alpha", "beta"} {
+		callTool(t, srv, "open_graph", map[string]any{"name": name})
+		callTool(t, srv, "upsert", map[string]any{
+			"graph": name,
+			"nodes": []map[string]any{{"id": name + "-node", "label": name}},
+		})
+		callTool(t, srv, "save_graph", map[string]any{"name": name})
+	}
+
+	resp := call(t, srv, "resources/list", nil)
+	if resp.Error != nil {
+		t.Fatal(resp.Error.Message)
+	}
+	b, _ := json.Marshal(resp.Result)
+	var list struct {
+		Resources []Resource `json:"resources"`
+	}
+	json.Unmarshal(b, &list)
+	if len(list.Resources) != 2 {
+		t.Fatalf("expected 2 resources, got %d", len(list.Resources))
+	}
+	if list.Resources[0].URI != "spine://graph/alpha" || list.Resources[1].URI != "spine://graph/beta" {
+		t.Errorf("unexpected uris: %+v", list.Resources)
+	}
+
+	resp = call(t, srv, "resources/read", map[string]string{"uri": "spine://graph/beta"})
+	if resp.Error != nil {
+		t.Fatal(resp.Error.Message)
+	}
+	b, _ = json.Marshal(resp.Result)
+	var read struct {
+		Contents []ResourceContents `json:"contents"`
+	}
+	json.Unmarshal(b, &read)
+	if len(read.Contents) != 1 || read.Contents[0].MimeType != "application/json" {
+		t.Fatalf("unexpected contents: %+v", read.Contents)
+	}
+	if !strings.Contains(read.Contents[0].Text, "beta-node") {
+		t.Errorf("expected snapshot to contain beta-node, got %s", read.Contents[0].Text)
+	}
+
+	resp = call(t, srv, "resources/read", map[string]string{"uri": "spine://graph/missing"})
+	if resp.Error == nil {
+		t.Error("expected error for missing graph")
+	}
+}
+
+func TestResourceReadTraversal(t *testing.T) {
+	root := tempDir(t)
+	os.WriteFile(filepath.Join(root, "secret.json"), []byte(`{"secret":true}`), 0o644)
+	mgr, _ := api.NewManager(filepath.Join(root, "graphs"))
+	srv := NewServer(mgr)
+
+	for _, uri := range []string{"spine://graph/../secret", `spine://graph/..\secret`, "spine://graph/a/b"} {
+		resp := call(t, srv, "resources/read", map[string]string{"uri": uri})
+		if resp.Error == nil || resp.Error.Code != -32602 {
+			t.Errorf("%s: expected an invalid params error, got %+v", uri, resp)
+		}
+	}
+	if _, err := mgr.Snapshot("../secret"); !errors.Is(err, api.ErrInvalidGraphName) {
+		t.Errorf("expected ErrInvalidGraphName from Snapshot, got %v", err)
+	}
+	if _, err := mgr.Open(".."); !errors.Is(err, api.ErrInvalidGraphName) {
+		t.Errorf("expected ErrInvalidGraphName from Open, got %v", err)
+	}
 }
 
 func TestToolsList(t *testing.T) {
@@ -120,8 +194,8 @@ func TestToolsList(t *testing.T) {
 	}
 	json.Unmarshal(b, &result)
 
-	if len(result.Tools) != 35 {
-		t.Errorf("expected 35 tools, got %d", len(result.Tools))
+	if len(result.Tools) != 47 {
+		t.Errorf("expected 47 tools, got %d", len(result.Tools))
 	}
 
 	names := make(map[string]bool)
@@ -130,11 +204,12 @@ func TestToolsList(t *testing.T) {
 	}
 	for _, expected := range []string{
 		"open_graph", "save_graph", "list_graphs", "delete_graph",
-		"graph_summary", "upsert", "read_nodes", "transition", "remove",
+		"graph_summary", "upsert", "read_nodes", "read_edges", "transition", "advance_plan", "remove",
+		"simulate_remove", "plan_layers", "count_by_meta_key", "graph_outline", "tag_nodes", "untag_nodes",
 		"scc", "mst",
-		"bfs", "dfs", "shortest_path", "topological_sort", "cycle_detect",
+		"bfs", "dfs", "shortest_path", "slowest_paths", "topological_sort", "cycle_detect",
 		"connected_components", "ancestors", "descendants", "roots", "leaves",
-		"transitive_closure", "validate_graph", "diff_graphs",
+		"transitive_closure", "validate_graph", "validate_plan", "diff_graphs", "undo", "redo",
 		"degree_centrality", "betweenness_centrality", "closeness_centrality", "pagerank",
 		"all_pairs_shortest_paths", "critical_path", "max_flow",
 		"explain_path", "explain_component", "explain_centrality", "explain_dependency",
@@ -145,6 +220,39 @@ func TestToolsList(t *testing.T) {
 	}
 }
 
+func TestToolMetadata(t *testing.T) {
+	srv := newTestServer(t)
+	resp := call(t, srv, "tools/list", nil)
+	if resp.Error != nil {
+		t.Fatal(resp.Error.Message)
+	}
+
+	b, _ := json.Marshal(resp.Result)
+	var result struct {
+		Tools []ToolDefinition `json:"tools"`
+	}
+	json.Unmarshal(b, &result)
+
+	var upsert *ToolDefinition
+	for i := range result.Tools {
+		if result.Tools[i].Name == "upsert" {
+			upsert = &result.Tools[i]
+		}
+	}
+	if upsert == nil {
+		t.Fatal("upsert tool not listed")
+	}
+	if upsert.OutputSchema == nil {
+		t.Error("expected upsert to advertise an output schema")
+	}
+	if len(upsert.Examples) == 0 {
+		t.Fatal("expected upsert to advertise an example")
+	}
+	if upsert.Examples[0].Arguments["graph"] == nil {
+		t.Errorf("expected example arguments to name a graph, got %v", upsert.Examples[0].Arguments)
+	}
+}
+
 func TestNotification(t *testing.T) {
 	// Notifications have no ID and should produce no response.
 	srv := newTestServer(t)
@@ -165,6 +273,60 @@ func TestNotification(t *testing.T) {
 	}
 }
 
+func TestBatch(t *testing.T) {
+	srv := newTestServer(t)
+	batch := `[` +
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"open_graph","arguments":{"name":"b"}}},` +
+		`{"jsonrpc":"2.0","method":"notifications/initialized"},` +
+		`{"jsonrpc":"2.0","id":"two","method":"tools/call","params":{"name":"upsert","arguments":{"graph":"b","nodes":[{"id":"x"},{"id":"y"}]}}},` +
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"read_nodes","arguments":{"graph":"b"}}}` +
+		`]` + "\n"
+
+	var out bytes.Buffer
+	if err := srv.Run(strings.NewReader(batch), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var resps []Response
+	if err := json.Unmarshal(out.Bytes(), &resps); err != nil {
+		t.Fatalf("unmarshal batch response: %v\nbody: %s", err, out.String())
+	}
+	if len(resps) != 3 {
+		t.Fatalf("expected 3 responses (notification omitted), got %d", len(resps))
+	}
+	for i, want := range []string{`1`, `"two"`, `3`} {
+		if string(resps[i].ID) != want {
+			t.Errorf("response %d: expected id %s, got %s", i, want, resps[i].ID)
+		}
+		if resps[i].Error != nil {
+			t.Errorf("response %d: unexpected error %s", i, resps[i].Error.Message)
+		}
+	}
+
+	b, _ := json.Marshal(resps[2].Result)
+	var tcr ToolCallResult
+	json.Unmarshal(b, &tcr)
+	var read api.ReadNodesResponse
+	json.Unmarshal([]byte(tcr.Content[0].Text), &read)
+	if read.Total != 2 {
+		t.Errorf("expected 2 nodes after batched upsert, got %d", read.Total)
+	}
+}
+
+func TestEmptyBatch(t *testing.T) {
+	srv := newTestServer(t)
+	var out bytes.Buffer
+	srv.Run(strings.NewReader("[]\n"), &out)
+
+	var resp Response
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v\nbody: %s", err, out.String())
+	}
+	if resp.Error == nil || resp.Error.Code != -32600 {
+		t.Errorf("expected invalid request error, got %+v", resp.Error)
+	}
+}
+
 func TestUnknownMethod(t *testing.T) {
 	srv := newTestServer(t)
 	resp := call(t, srv, "bogus/method", nil)
@@ -301,6 +463,242 @@ func TestToolError(t *testing.T) {
 	if len(tcr.Content) == 0 || tcr.Content[0].Text == "" {
 		t.Fatal("expected error message in content")
 	}
+	if code, _ := tcr.Meta["errorCode"].(float64); int(code) != codeNotFound {
+		t.Errorf("expected errorCode %d, got %v", codeNotFound, tcr.Meta["errorCode"])
+	}
+}
+
+func TestOpenGraphTemplate(t *testing.T) {
+	srv := newTestServer(t)
+	tcr := callTool(t, srv, "open_graph", map[string]any{"name": "ci", "template": "workflow"})
+	if tcr.IsError {
+		t.Fatalf("open_graph from template failed: %s", tcr.Content[0].Text)
+	}
+	var info api.GraphInfo
+	json.Unmarshal([]byte(tcr.Content[0].Text), &info)
+	want, _ := api.BuildFromTemplate("workflow")
+	if info.NodeCount != want.Order() || info.EdgeCount != want.Size() {
+		t.Errorf("expected the workflow template, got %+v", info)
+	}
+
+	tcr = callTool(t, srv, "open_graph", map[string]any{"name": "ci", "template": "workflow"})
+	if !tcr.IsError {
+		t.Error("expected an error opening a template over an open graph")
+	}
+	tcr = callTool(t, srv, "open_graph", map[string]any{"name": "x", "template": "nope"})
+	if !tcr.IsError {
+		t.Error("expected an error for an unknown template")
+	}
+}
+
+func TestToolErrorCodes(t *testing.T) {
+	srv := newTestServer(t)
+	callTool(t, srv, "open_graph", map[string]any{"name": "g"})
+	callTool(t, srv, "upsert", map[string]any{
+		"graph": "g",
+		"nodes": []map[string]any{{"id": "a", "status": "pending"}},
+	})
+
+	tcr := callTool(t, srv, "transition", map[string]any{"graph": "g", "id": "a", "status": "done"})
+	if code, _ := tcr.Meta["errorCode"].(float64); !tcr.IsError || int(code) != codeInvalidParams {
+		t.Errorf("expected invalid transition errorCode %d, got %+v", codeInvalidParams, tcr)
+	}
+	tcr = callTool(t, srv, "transition", map[string]any{"graph": "g", "id": "zzz", "status": "ready"})
+	if code, _ := tcr.Meta["errorCode"].(float64); !tcr.IsError || int(code) != codeNotFound {
+		t.Errorf("expected node not found errorCode %d, got %+v", codeNotFound, tcr)
+	}
+	tcr = callTool(t, srv, "save_graph", map[string]any{"name": "g", "expected_version": 0})
+	if code, _ := tcr.Meta["errorCode"].(float64); !tcr.IsError || int(code) != codeConflict {
+		t.Errorf("expected stale save errorCode %d, got %+v", codeConflict, tcr)
+	}
+	var info api.GraphInfo
+	tcr = callTool(t, srv, "open_graph", map[string]any{"name": "g"})
+	json.Unmarshal([]byte(tcr.Content[0].Text), &info)
+	tcr = callTool(t, srv, "save_graph", map[string]any{"name": "g", "expected_version": info.Version})
+	if tcr.IsError {
+		t.Errorf("expected save at the current version to succeed, got %s", tcr.Content[0].Text)
+	}
+}
+
+func TestToolContentBlocks(t *testing.T) {
+	srv := newTestServer(t)
+	srv.addTool("render", "test render", map[string]any{"type": "object"},
+		func(json.RawMessage) (any, error) {
+			return []ContentBlock{
+				{Type: "text", Text: "graph TD\n  a --> b", MimeType: "text/vnd.mermaid"},
+				{Type: "resource_link", URI: "spine://graph/g", Name: "g", MimeType: "application/json"},
+			}, nil
+		})
+
+	tcr := callTool(t, srv, "render", nil)
+	if tcr.IsError {
+		t.Fatal(tcr.Content[0].Text)
+	}
+	if len(tcr.Content) != 2 {
+		t.Fatalf("expected 2 content blocks, got %d", len(tcr.Content))
+	}
+	if tcr.Content[0].Type != "text" || tcr.Content[0].Text != "graph TD\n  a --> b" {
+		t.Errorf("unexpected first block: %+v", tcr.Content[0])
+	}
+	if tcr.Content[1].Type != "resource_link" || tcr.Content[1].URI != "spine://graph/g" {
+		t.Errorf("unexpected second block: %+v", tcr.Content[1])
+	}
+}
+
+func TestGraphOutline(t *testing.T) {
+	srv := newTestServer(t)
+	callTool(t, srv, "open_graph", map[string]any{"name": "outline"})
+	callTool(t, srv, "upsert", map[string]any{
+		"graph": "outline",
+		"nodes": []map[string]any{
+			{"id": "a", "status": "done"}, {"id": "b", "status": "ready"}, {"id": "c"},
+		},
+		"edges": []map[string]any{
+			{"from": "a", "to": "b"},
+			{"from": "a", "to": "c"},
+		},
+	})
+
+	tcr := callTool(t, srv, "graph_outline", map[string]any{"graph": "outline"})
+	if tcr.IsError {
+		t.Fatalf("graph_outline failed: %s", tcr.Content[0].Text)
+	}
+	text := tcr.Content[0].Text
+	for _, line := range []string{"a(done) -> b, c", "b(ready)", "\nc"} {
+		if !strings.Contains(text, line) {
+			t.Errorf("expected outline to contain %q, got:\n%s", line, text)
+		}
+	}
+}
+
+func TestTagNodes(t *testing.T) {
+	srv := newTestServer(t)
+
+	callTool(t, srv, "open_graph", map[string]any{"name": "tags"})
+	callTool(t, srv, "upsert", map[string]any{
+		"graph": "tags",
+		"nodes": []map[string]any{{"id": "a"}, {"id": "b"}, {"id": "c"}, {"id": "d"}},
+	})
+
+	tcr := callTool(t, srv, "tag_nodes", map[string]any{
+		"graph": "tags", "ids": []string{"a", "b", "c"}, "meta": map[string]any{"reviewed": true},
+	})
+	if tcr.IsError {
+		t.Fatalf("tag_nodes failed: %s", tcr.Content[0].Text)
+	}
+	var tagged map[string]int
+	json.Unmarshal([]byte(tcr.Content[0].Text), &tagged)
+	if tagged["keys_set"] != 3 {
+		t.Errorf("expected keys_set 3, got %d", tagged["keys_set"])
+	}
+
+	tcr = callTool(t, srv, "read_nodes", map[string]any{
+		"graph":   "tags",
+		"filters": []map[string]any{{"key": "reviewed", "op": "eq", "value": true}},
+	})
+	var read api.ReadNodesResponse
+	json.Unmarshal([]byte(tcr.Content[0].Text), &read)
+	if read.Total != 3 {
+		t.Errorf("expected 3 reviewed nodes, got %d", read.Total)
+	}
+
+	tcr = callTool(t, srv, "untag_nodes", map[string]any{
+		"graph": "tags", "ids": []string{"a", "d"}, "keys": []string{"reviewed"},
+	})
+	var untagged map[string]int
+	json.Unmarshal([]byte(tcr.Content[0].Text), &untagged)
+	if untagged["keys_deleted"] != 1 {
+		t.Errorf("expected keys_deleted 1, got %d", untagged["keys_deleted"])
+	}
+
+	tcr = callTool(t, srv, "tag_nodes", map[string]any{
+		"graph": "tags", "ids": []string{"missing"}, "meta": map[string]any{"x": 1},
+	})
+	if !tcr.IsError {
+		t.Error("expected error tagging a missing node")
+	}
+}
+
+func TestAdvancePlan(t *testing.T) {
+	srv := newTestServer(t)
+
+	callTool(t, srv, "open_graph", map[string]any{"name": "plan"})
+	callTool(t, srv, "upsert", map[string]any{
+		"graph": "plan",
+		"nodes": []map[string]any{
+			{"id": "root", "status": "ready"}, {"id": "next", "status": "pending"},
+		},
+		"edges": []map[string]any{{"from": "root", "to": "next"}},
+	})
+
+	tcr := callTool(t, srv, "advance_plan", map[string]any{"graph": "plan", "completed": []string{"root"}})
+	if tcr.IsError {
+		t.Fatalf("advance_plan failed: %s", tcr.Content[0].Text)
+	}
+	var res api.AdvanceResult
+	json.Unmarshal([]byte(tcr.Content[0].Text), &res)
+	if len(res.Ready) != 1 || res.Ready[0] != "next" {
+		t.Errorf("expected next to be ready, got %v", res.Ready)
+	}
+	if res.Done != 1 || res.Total != 2 {
+		t.Errorf("expected 1/2 done, got %d/%d", res.Done, res.Total)
+	}
+}
+
+func TestSimulateRemove(t *testing.T) {
+	srv := newTestServer(t)
+
+	callTool(t, srv, "open_graph", map[string]any{"name": "sim"})
+	callTool(t, srv, "upsert", map[string]any{
+		"graph": "sim",
+		"nodes": []map[string]any{{"id": "a"}, {"id": "b"}, {"id": "c"}},
+		"edges": []map[string]any{{"from": "a", "to": "b"}, {"from": "b", "to": "c"}},
+	})
+
+	tcr := callTool(t, srv, "simulate_remove", map[string]any{
+		"graph": "sim",
+		"edges": []map[string]any{{"from": "a", "to": "b"}},
+	})
+	if tcr.IsError {
+		t.Fatalf("simulate_remove failed: %s", tcr.Content[0].Text)
+	}
+	var res api.SimulationResult
+	json.Unmarshal([]byte(tcr.Content[0].Text), &res)
+	if len(res.Unreachable) != 2 {
+		t.Errorf("expected b and c unreachable, got %v", res.Unreachable)
+	}
+
+	g, _ := srv.mgr.OpenGraph("sim")
+	if !g.HasEdge("a", "b") {
+		t.Error("simulate_remove must not modify the real graph")
+	}
+}
+
+func TestPlanLayers(t *testing.T) {
+	srv := newTestServer(t)
+
+	callTool(t, srv, "open_graph", map[string]any{"name": "layers"})
+	callTool(t, srv, "upsert", map[string]any{
+		"graph": "layers",
+		"nodes": []map[string]any{{"id": "start"}, {"id": "build"}, {"id": "deploy"}},
+		"edges": []map[string]any{
+			{"from": "start", "to": "build"},
+			{"from": "build", "to": "deploy"},
+		},
+	})
+
+	tcr := callTool(t, srv, "plan_layers", map[string]any{"graph": "layers"})
+	if tcr.IsError {
+		t.Fatalf("plan_layers failed: %s", tcr.Content[0].Text)
+	}
+	var res struct {
+		Layers [][]string `json:"layers"`
+		Depth  int        `json:"depth"`
+	}
+	json.Unmarshal([]byte(tcr.Content[0].Text), &res)
+	if res.Depth != 3 || res.Layers[0][0] != "start" || res.Layers[2][0] != "deploy" {
+		t.Errorf("unexpected layers: %+v", res)
+	}
 }
 
 func TestSCC(t *testing.T) {
@@ -389,6 +787,24 @@ func TestBFS(t *testing.T) {
 	}
 }
 
+func TestBFSMaxNodes(t *testing.T) {
+	srv := newTestServer(t)
+	setupDAG(t, srv)
+
+	tcr := callTool(t, srv, "bfs", map[string]any{"graph": "dag", "start": "a", "max_nodes": 2})
+	if tcr.IsError {
+		t.Fatalf("bfs failed: %s", tcr.Content[0].Text)
+	}
+	var result struct {
+		Order     []string `json:"order"`
+		Truncated bool     `json:"truncated"`
+	}
+	json.Unmarshal([]byte(tcr.Content[0].Text), &result)
+	if len(result.Order) != 2 || !result.Truncated {
+		t.Fatalf("expected 2 nodes and truncation, got %+v", result)
+	}
+}
+
 func TestDFS(t *testing.T) {
 	srv := newTestServer(t)
 	setupDAG(t, srv)
@@ -406,6 +822,27 @@ func TestDFS(t *testing.T) {
 	}
 }
 
+func TestSlowestPaths(t *testing.T) {
+	srv := newTestServer(t)
+	setupDAG(t, srv)
+
+	tcr := callTool(t, srv, "slowest_paths", map[string]any{"graph": "dag", "src": "a", "n": 1})
+	if tcr.IsError {
+		t.Fatalf("slowest_paths failed: %s", tcr.Content[0].Text)
+	}
+	var result struct {
+		Paths []struct {
+			To   string   `json:"to"`
+			Path []string `json:"path"`
+			Cost float64  `json:"cost"`
+		} `json:"paths"`
+	}
+	json.Unmarshal([]byte(tcr.Content[0].Text), &result)
+	if len(result.Paths) != 1 || result.Paths[0].To != "c" || result.Paths[0].Cost != 3 {
+		t.Fatalf("expected c at cost 3, got %+v", result.Paths)
+	}
+}
+
 func TestShortestPath(t *testing.T) {
 	srv := newTestServer(t)
 	setupDAG(t, srv)
@@ -558,11 +995,11 @@ func TestEmptyGraphName(t *testing.T) {
 
 	// Tools that accept "graph" param.
 	for _, tool := range []string{
-		"upsert", "read_nodes", "transition", "remove",
-		"scc", "mst", "bfs", "dfs", "shortest_path", "topological_sort",
+		"upsert", "read_nodes", "read_edges", "transition", "advance_plan", "remove", "simulate_remove", "plan_layers", "count_by_meta_key", "graph_outline", "tag_nodes", "untag_nodes",
+		"scc", "mst", "bfs", "dfs", "shortest_path", "slowest_paths", "topological_sort",
 		"cycle_detect", "connected_components", "ancestors", "descendants",
 		"roots", "leaves",
-		"transitive_closure", "validate_graph",
+		"transitive_closure", "validate_graph", "validate_plan", "undo", "redo",
 		"degree_centrality", "betweenness_centrality", "closeness_centrality", "pagerank",
 		"all_pairs_shortest_paths", "critical_path", "max_flow",
 		"explain_path", "explain_component", "explain_centrality", "explain_dependency",
@@ -631,6 +1068,78 @@ func TestValidateGraph(t *testing.T) {
 	}
 }
 
+func TestReadEdges(t *testing.T) {
+	srv := newTestServer(t)
+	setupDAG(t, srv)
+
+	tcr := callTool(t, srv, "read_edges", map[string]any{
+		"graph":   "dag",
+		"filters": []map[string]any{{"key": "weight", "op": "gt", "value": 1}},
+	})
+	if tcr.IsError {
+		t.Fatalf("read_edges failed: %s", tcr.Content[0].Text)
+	}
+	var result struct {
+		Edges []struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"edges"`
+		Total int `json:"total"`
+	}
+	json.Unmarshal([]byte(tcr.Content[0].Text), &result)
+	if result.Total != 1 || result.Edges[0].From != "b" || result.Edges[0].To != "c" {
+		t.Fatalf("expected only b->c, got %s", tcr.Content[0].Text)
+	}
+}
+
+func TestValidatePlan(t *testing.T) {
+	srv := newTestServer(t)
+	setupDAG(t, srv)
+
+	tcr := callTool(t, srv, "validate_plan", map[string]any{"graph": "dag"})
+	if tcr.IsError {
+		t.Fatalf("validate_plan failed: %s", tcr.Content[0].Text)
+	}
+	var result struct {
+		Valid   bool `json:"valid"`
+		Acyclic struct {
+			Passed bool `json:"passed"`
+		} `json:"acyclic"`
+	}
+	json.Unmarshal([]byte(tcr.Content[0].Text), &result)
+	if !result.Valid || !result.Acyclic.Passed {
+		t.Fatalf("expected a valid plan, got %s", tcr.Content[0].Text)
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	srv := newTestServer(t)
+	setupDAG(t, srv)
+
+	tcr := callTool(t, srv, "remove", map[string]any{"graph": "dag", "nodes": []string{"c"}})
+	if tcr.IsError {
+		t.Fatalf("remove failed: %s", tcr.Content[0].Text)
+	}
+	if tcr := callTool(t, srv, "undo", map[string]any{"graph": "dag"}); tcr.IsError {
+		t.Fatalf("undo failed: %s", tcr.Content[0].Text)
+	}
+	tcr = callTool(t, srv, "read_nodes", map[string]any{"graph": "dag", "ids": []string{"c"}})
+	if !strings.Contains(tcr.Content[0].Text, `"id":"c"`) {
+		t.Fatalf("expected c to be restored, got %s", tcr.Content[0].Text)
+	}
+
+	if tcr := callTool(t, srv, "redo", map[string]any{"graph": "dag"}); tcr.IsError {
+		t.Fatalf("redo failed: %s", tcr.Content[0].Text)
+	}
+	if tcr := callTool(t, srv, "redo", map[string]any{"graph": "dag"}); !tcr.IsError {
+		t.Error("expected error with nothing to redo")
+	}
+	tcr = callTool(t, srv, "read_nodes", map[string]any{"graph": "dag", "ids": []string{"c"}})
+	if strings.Contains(tcr.Content[0].Text, `"id":"c"`) {
+		t.Fatalf("expected c to be removed again, got %s", tcr.Content[0].Text)
+	}
+}
+
 func TestDiffGraphs(t *testing.T) {
 	srv := newTestServer(t)
 
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/imran31415/spine/api"
+)
+
+// graphURIPrefix is the URI scheme under which persisted graphs are exposed
+// as MCP resources, e.g. spine://graph/deps.
+const graphURIPrefix = "spine://graph/"
+
+// Resource describes a single entry in a resources/list response.
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type resourceReadParams struct {
+	URI string `json:"uri"`
+}
+
+func (s *Server) handleResourcesList(req *Request) *Response {
+	infos, err := s.mgr.List()
+	if err != nil {
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &RPCError{Code: -32603, Message: err.Error()},
+		}
+	}
+	resources := make([]Resource, 0, len(infos))
+	for _, info := range infos {
+		resources = append(resources, Resource{
+			URI:         graphURIPrefix + info.Name,
+			Name:        info.Name,
+			Description: "spine graph snapshot",
+			MimeType:    "application/json",
+		})
+	}
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  map[string]any{"resources": resources},
+	}
+}
+
+func (s *Server) handleResourcesRead(req *Request) *Response {
+	var params resourceReadParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &RPCError{Code: -32602, Message: "invalid params: " + err.Error()},
+		}
+	}
+	name, ok := strings.CutPrefix(params.URI, graphURIPrefix)
+	if !ok || name == "" {
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &RPCError{Code: -32602, Message: "invalid resource uri: " + params.URI},
+		}
+	}
+	data, err := s.mgr.Snapshot(name)
+	if errors.Is(err, api.ErrInvalidGraphName) {
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &RPCError{Code: -32602, Message: "invalid resource uri: " + params.URI},
+		}
+	}
+	if err != nil {
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &RPCError{Code: codeNotFound, Message: "resource not found: " + params.URI},
+		}
+	}
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: map[string]any{
+			"contents": []ResourceContents{{
+				URI:      params.URI,
+				MimeType: "application/json",
+				Text:     string(data),
+			}},
+		},
+	}
+}
@@ -1,5 +1,11 @@
 package mcp
 
+import (
+	"strings"
+
+	"github.com/imran31415/spine/api"
+)
+
 func (s *Server) registerTools() {
 	s.addTool("open_graph", "Open or create a named graph",
 		map[string]any{
@@ -7,6 +13,7 @@ func (s *Server) registerTools() {
 			"properties": map[string]any{
 				"name":     map[string]any{"type": "string", "description": "Graph name"},
 				"directed": map[string]any{"type": "boolean", "description": "Whether the graph is directed (default true)"},
+				"template": map[string]any{"type": "string", "description": "Create a new graph from this starter template instead (" + strings.Join(api.TemplateNames(), ", ") + "); fails if the graph already exists"},
 			},
 			"required": []string{"name"},
 		}, s.handleOpenGraph)
@@ -15,7 +22,8 @@ func (s *Server) registerTools() {
 		map[string]any{
 			"type": "object",
 			"properties": map[string]any{
-				"name": map[string]any{"type": "string", "description": "Graph name"},
+				"name":             map[string]any{"type": "string", "description": "Graph name"},
+				"expected_version": map[string]any{"type": "integer", "description": "Only save if the graph is still at this version (see open_graph); a stale version is rejected"},
 			},
 			"required": []string{"name"},
 		}, s.handleSaveGraph)
@@ -59,6 +67,19 @@ func (s *Server) registerTools() {
 							"status": map[string]any{"type": "string"},
 							"meta":   map[string]any{"type": "object"},
 							"delete": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+							"meta_ops": map[string]any{
+								"type":        "array",
+								"description": "Incremental ops applied in order: incr, append, set_if_absent",
+								"items": map[string]any{
+									"type": "object",
+									"properties": map[string]any{
+										"key":   map[string]any{"type": "string"},
+										"op":    map[string]any{"type": "string", "enum": []string{"incr", "append", "set_if_absent"}},
+										"value": map[string]any{},
+									},
+									"required": []string{"key", "op"},
+								},
+							},
 						},
 						"required": []string{"id"},
 					},
@@ -74,6 +95,19 @@ func (s *Server) registerTools() {
 							"weight": map[string]any{"type": "number"},
 							"meta":   map[string]any{"type": "object"},
 							"delete": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+							"meta_ops": map[string]any{
+								"type":        "array",
+								"description": "Incremental ops applied in order: incr, append, set_if_absent",
+								"items": map[string]any{
+									"type": "object",
+									"properties": map[string]any{
+										"key":   map[string]any{"type": "string"},
+										"op":    map[string]any{"type": "string", "enum": []string{"incr", "append", "set_if_absent"}},
+										"value": map[string]any{},
+									},
+									"required": []string{"key", "op"},
+								},
+							},
 						},
 						"required": []string{"from", "to"},
 					},
@@ -81,6 +115,32 @@ func (s *Server) registerTools() {
 			},
 			"required": []string{"graph"},
 		}, s.handleUpsert)
+	s.describeTool("upsert",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"nodes_created":     map[string]any{"type": "integer"},
+				"nodes_updated":     map[string]any{"type": "integer"},
+				"edges_created":     map[string]any{"type": "integer"},
+				"edges_updated":     map[string]any{"type": "integer"},
+				"meta_keys_set":     map[string]any{"type": "integer"},
+				"meta_keys_deleted": map[string]any{"type": "integer"},
+			},
+		},
+		ToolExample{
+			Description: "Add two tasks with a dependency",
+			Arguments: map[string]any{
+				"graph": "plan",
+				"nodes": []any{
+					map[string]any{"id": "design", "label": "Design", "status": "ready"},
+					map[string]any{"id": "build", "label": "Build", "status": "pending", "meta": map[string]any{"owner": "alice"}},
+				},
+				"edges": []any{
+					map[string]any{"from": "design", "to": "build"},
+				},
+			},
+			Result: map[string]any{"nodes_created": 2, "edges_created": 1, "meta_keys_set": 1},
+		})
 
 	s.addTool("read_nodes", "Selective read with filters, key projection, and pagination",
 		map[string]any{
@@ -94,51 +154,161 @@ func (s *Server) registerTools() {
 					"items": map[string]any{
 						"type": "object",
 						"properties": map[string]any{
-							"key":   map[string]any{"type": "string"},
+							"key":   map[string]any{"type": "string", "description": "Meta key, status, label, upstream_status or downstream_status"},
 							"op":    map[string]any{"type": "string"},
-							"value": map[string]any{},
+							"value": map[string]any{"description": "Literal to compare against, or {\"$field\": key} to compare against another field of the same node"},
 						},
 						"required": []string{"key", "op"},
 					},
 				},
-				"include_edges": map[string]any{"type": "boolean"},
-				"offset":        map[string]any{"type": "integer"},
-				"limit":         map[string]any{"type": "integer"},
+				"include_edges":        map[string]any{"type": "boolean"},
+				"include_reach_counts": map[string]any{"type": "boolean", "description": "Add ancestor_count and descendant_count to each node"},
+				"offset":               map[string]any{"type": "integer"},
+				"limit":                map[string]any{"type": "integer"},
 			},
 			"required": []string{"graph"},
 		}, s.handleReadNodes)
-
-	s.addTool("transition", "Change node status with auto-ready propagation",
+	s.describeTool("read_nodes",
 		map[string]any{
 			"type": "object",
 			"properties": map[string]any{
-				"graph":  map[string]any{"type": "string", "description": "Graph name"},
-				"id":     map[string]any{"type": "string", "description": "Node ID"},
-				"status": map[string]any{"type": "string", "description": "Target status"},
+				"nodes":    map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+				"edges":    map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+				"total":    map[string]any{"type": "integer"},
+				"has_more": map[string]any{"type": "boolean"},
 			},
-			"required": []string{"graph", "id", "status"},
-		}, s.handleTransition)
+		},
+		ToolExample{
+			Description: "List pending nodes, returning only the owner key",
+			Arguments: map[string]any{
+				"graph":   "plan",
+				"keys":    []any{"owner"},
+				"filters": []any{map[string]any{"key": "status", "op": "eq", "value": "pending"}},
+				"limit":   10,
+			},
+		})
 
-	s.addTool("remove", "Delete nodes and/or edges from a graph",
+	s.addTool("read_edges", "Read edges with filters on from, to, label, weight or metadata, key projection, and pagination",
 		map[string]any{
 			"type": "object",
 			"properties": map[string]any{
 				"graph": map[string]any{"type": "string", "description": "Graph name"},
-				"nodes": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
-				"edges": map[string]any{
+				"keys":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				"filters": map[string]any{
 					"type": "array",
 					"items": map[string]any{
 						"type": "object",
 						"properties": map[string]any{
-							"from": map[string]any{"type": "string"},
-							"to":   map[string]any{"type": "string"},
+							"key":   map[string]any{"type": "string", "description": "Meta key, from, to, label or weight"},
+							"op":    map[string]any{"type": "string"},
+							"value": map[string]any{"description": "Literal to compare against, or {\"$field\": key} to compare against another field of the same edge"},
 						},
-						"required": []string{"from", "to"},
+						"required": []string{"key", "op"},
 					},
 				},
+				"offset": map[string]any{"type": "integer"},
+				"limit":  map[string]any{"type": "integer"},
+			},
+			"required": []string{"graph"},
+		}, s.handleReadEdges)
+
+	s.addTool("count_by_meta_key", "Count nodes with and without a metadata key set",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"graph": map[string]any{"type": "string", "description": "Graph name"},
+				"key":   map[string]any{"type": "string", "description": "Metadata key"},
+			},
+			"required": []string{"graph", "key"},
+		}, s.handleCountByMetaKey)
+
+	s.addTool("graph_outline", "Compact text adjacency list, one line per node like \"a(done) -> b, c\"",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"graph": map[string]any{"type": "string", "description": "Graph name"},
+			},
+			"required": []string{"graph"},
+		}, s.handleGraphOutline)
+
+	s.addTool("tag_nodes", "Set the same metadata keys on many nodes at once",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"graph": map[string]any{"type": "string", "description": "Graph name"},
+				"ids":   map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Node IDs to tag"},
+				"meta":  map[string]any{"type": "object", "description": "Metadata key-value pairs to set on every node"},
+			},
+			"required": []string{"graph", "ids", "meta"},
+		}, s.handleTagNodes)
+
+	s.addTool("untag_nodes", "Remove metadata keys from many nodes at once",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"graph": map[string]any{"type": "string", "description": "Graph name"},
+				"ids":   map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Node IDs to untag"},
+				"keys":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Metadata keys to remove"},
+			},
+			"required": []string{"graph", "ids", "keys"},
+		}, s.handleUntagNodes)
+
+	s.addTool("transition", "Change node status with auto-ready propagation",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"graph":        map[string]any{"type": "string", "description": "Graph name"},
+				"id":           map[string]any{"type": "string", "description": "Node ID"},
+				"status":       map[string]any{"type": "string", "description": "Target status"},
+				"cascade_skip": map[string]any{"type": "boolean", "description": "When skipping, also skip pending descendants"},
+				"verbose":      map[string]any{"type": "boolean", "description": "Explain readiness of each downstream node"},
+			},
+			"required": []string{"graph", "id", "status"},
+		}, s.handleTransition)
+
+	s.addTool("advance_plan", "Mark tasks done (stepping through ready/running), auto-ready dependents, and return the ready set and progress",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"graph":     map[string]any{"type": "string", "description": "Graph name"},
+				"completed": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "IDs of tasks that are finished"},
 			},
 			"required": []string{"graph"},
-		}, s.handleRemove)
+		}, s.handleAdvancePlan)
+
+	s.addTool("plan_layers", "Group plan nodes by dependency depth (level 0 = roots) to describe a plan wave by wave",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"graph": map[string]any{"type": "string", "description": "Graph name"},
+			},
+			"required": []string{"graph"},
+		}, s.handlePlanLayers)
+
+	removeSchema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"graph": map[string]any{"type": "string", "description": "Graph name"},
+			"nodes": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"edges": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"from": map[string]any{"type": "string"},
+						"to":   map[string]any{"type": "string"},
+					},
+					"required": []string{"from", "to"},
+				},
+			},
+		},
+		"required": []string{"graph"},
+	}
+
+	s.addTool("remove", "Delete nodes and/or edges from a graph", removeSchema, s.handleRemove)
+
+	s.addTool("simulate_remove", "Preview a remove without applying it: unreachable nodes, orphaned nodes, and broken root paths",
+		removeSchema, s.handleSimulateRemove)
 
 	s.addTool("scc", "Compute strongly connected components of a graph",
 		map[string]any{
@@ -162,8 +332,9 @@ func (s *Server) registerTools() {
 		map[string]any{
 			"type": "object",
 			"properties": map[string]any{
-				"graph": map[string]any{"type": "string", "description": "Graph name"},
-				"start": map[string]any{"type": "string", "description": "Start node ID"},
+				"graph":     map[string]any{"type": "string", "description": "Graph name"},
+				"start":     map[string]any{"type": "string", "description": "Start node ID"},
+				"max_nodes": map[string]any{"type": "integer", "description": "Stop after visiting this many nodes (default 10000); truncated is set if nodes were left unvisited"},
 			},
 			"required": []string{"graph", "start"},
 		}, s.handleBFS)
@@ -172,8 +343,9 @@ func (s *Server) registerTools() {
 		map[string]any{
 			"type": "object",
 			"properties": map[string]any{
-				"graph": map[string]any{"type": "string", "description": "Graph name"},
-				"start": map[string]any{"type": "string", "description": "Start node ID"},
+				"graph":     map[string]any{"type": "string", "description": "Graph name"},
+				"start":     map[string]any{"type": "string", "description": "Start node ID"},
+				"max_nodes": map[string]any{"type": "integer", "description": "Stop after visiting this many nodes (default 10000); truncated is set if nodes were left unvisited"},
 			},
 			"required": []string{"graph", "start"},
 		}, s.handleDFS)
@@ -189,6 +361,17 @@ func (s *Server) registerTools() {
 			"required": []string{"graph", "src", "dst"},
 		}, s.handleShortestPath)
 
+	s.addTool("slowest_paths", "Find the n destinations whose shortest path from src costs the most (e.g. the slowest routes by latency)",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"graph": map[string]any{"type": "string", "description": "Graph name"},
+				"src":   map[string]any{"type": "string", "description": "Source node ID"},
+				"n":     map[string]any{"type": "integer", "description": "Number of destinations to return (default all)"},
+			},
+			"required": []string{"graph", "src"},
+		}, s.handleSlowestPaths)
+
 	s.addTool("topological_sort", "Compute topological ordering of a directed acyclic graph",
 		map[string]any{
 			"type": "object",
@@ -263,7 +446,7 @@ func (s *Server) registerTools() {
 			"required": []string{"graph"},
 		}, s.handleTransitiveClosure)
 
-	s.addTool("validate_graph", "Validate internal consistency of a graph",
+	s.addTool("validate_graph", "Validate internal consistency of a graph and node metadata against per-label schemas",
 		map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -272,6 +455,33 @@ func (s *Server) registerTools() {
 			"required": []string{"graph"},
 		}, s.handleValidateGraph)
 
+	s.addTool("validate_plan", "Check a plan before running it: acyclic, dependencies exist, statuses consistent, metadata matches label schemas. Reports pass/fail per check",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"graph": map[string]any{"type": "string", "description": "Graph name"},
+			},
+			"required": []string{"graph"},
+		}, s.handleValidatePlan)
+
+	s.addTool("undo", "Revert the most recent change to a graph (upsert, transition, remove, advance_plan, tag/untag). Can be repeated up to the undo depth",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"graph": map[string]any{"type": "string", "description": "Graph name"},
+			},
+			"required": []string{"graph"},
+		}, s.handleUndo)
+
+	s.addTool("redo", "Reapply the most recently undone change to a graph. Any new change clears the redo history",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"graph": map[string]any{"type": "string", "description": "Graph name"},
+			},
+			"required": []string{"graph"},
+		}, s.handleRedo)
+
 	s.addTool("diff_graphs", "Compute differences between two graphs",
 		map[string]any{
 			"type": "object",
@@ -0,0 +1,146 @@
+package spine
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// MergeStrategy is the default conflict resolution of a MergePolicy.
+type MergeStrategy int
+
+const (
+	// MergeLastWriteWins takes the value from the source graph.
+	MergeLastWriteWins MergeStrategy = iota
+	// MergeKeepExisting keeps the value already in the destination graph.
+	MergeKeepExisting
+)
+
+// MetaConflict describes a metadata key set in both graphs of a Merge. Scope
+// is one of the MetaScope constants; ID names the node for MetaScopeNode and
+// From/To the edge for the edge scopes.
+type MetaConflict struct {
+	Scope    string
+	ID       string
+	From     string
+	To       string
+	Key      string
+	Existing any
+	Incoming any
+}
+
+// MergePolicy controls how Merge resolves nodes, edges and metadata keys
+// present in both graphs. Strategy applies unless the matching callback is
+// set; the zero value is last-write-wins throughout.
+type MergePolicy[N, E any] struct {
+	Strategy MergeStrategy
+	// ResolveNode returns the data to keep for a node in both graphs.
+	ResolveNode func(id string, existing, incoming N) N
+	// ResolveEdge returns the edge to keep for an edge in both graphs. Only
+	// its Data and Weight are used.
+	ResolveEdge func(existing, incoming Edge[E]) Edge[E]
+	// ResolveMeta returns the value to keep for a metadata key set in both
+	// graphs.
+	ResolveMeta func(c MetaConflict) any
+}
+
+// Merge copies the nodes, edges and metadata of src into dst, which must
+// have the same directedness. Anything only in src is added as is;
+// conflicts are resolved by policy, key by key for metadata, so keys only
+// in dst are always kept. Graph config and the per-direction edge metadata
+// of undirected graphs are merged the same way, and src schemas are copied
+// onto stores that have none. A nil policy is last-write-wins. src is not
+// modified; dst changes go through the normal mutators, so they are
+// recorded in its history and reported to its OnChange listeners. Merge
+// stops with an error at the first metadata value a strict dst store
+// rejects, leaving the changes made so far in place.
+func Merge[N, E any](dst, src *Graph[N, E], policy *MergePolicy[N, E]) error {
+	if dst.Directed != src.Directed {
+		return errors.New("merge: directedness differs")
+	}
+	if policy == nil {
+		policy = &MergePolicy[N, E]{}
+	}
+	keep := policy.Strategy == MergeKeepExisting
+
+	for _, n := range src.Nodes() {
+		data := n.Data
+		if existing, ok := dst.GetNode(n.ID); ok {
+			switch {
+			case policy.ResolveNode != nil:
+				data = policy.ResolveNode(n.ID, existing.Data, n.Data)
+			case keep:
+				data = existing.Data
+			}
+		}
+		dst.AddNode(n.ID, data)
+		if s := src.nodeMeta[n.ID]; s != nil {
+			if err := mergeStore(dst.NodeMeta(n.ID), s, policy, MetaConflict{Scope: MetaScopeNode, ID: n.ID}); err != nil {
+				return fmt.Errorf("merge node %q: %w", n.ID, err)
+			}
+		}
+	}
+
+	edges, _ := src.EdgesPage(0, 0)
+	for _, e := range edges {
+		data, weight := e.Data, e.Weight
+		if existing, ok := dst.GetEdge(e.From, e.To); ok {
+			switch {
+			case policy.ResolveEdge != nil:
+				r := policy.ResolveEdge(existing, e)
+				data, weight = r.Data, r.Weight
+			case keep:
+				data, weight = existing.Data, existing.Weight
+			}
+		}
+		if err := dst.AddEdge(e.From, e.To, data, weight); err != nil {
+			return err
+		}
+		if s := src.edgeMeta[e.From][e.To]; s != nil {
+			if err := mergeStore(dst.EdgeMeta(e.From, e.To), s, policy, MetaConflict{Scope: MetaScopeEdge, From: e.From, To: e.To}); err != nil {
+				return fmt.Errorf("merge edge %q -> %q: %w", e.From, e.To, err)
+			}
+		}
+		if src.Directed {
+			continue
+		}
+		for _, dir := range [][2]string{{e.From, e.To}, {e.To, e.From}} {
+			if s := src.dirEdgeMeta[dir[0]][dir[1]]; s != nil {
+				if err := mergeStore(dst.DirectedEdgeMeta(dir[0], dir[1]), s, policy, MetaConflict{Scope: MetaScopeDirectedEdge, From: dir[0], To: dir[1]}); err != nil {
+					return fmt.Errorf("merge edge %q -> %q: %w", dir[0], dir[1], err)
+				}
+			}
+		}
+	}
+
+	if src.graphMeta != nil {
+		if err := mergeStore(dst.GraphMeta(), src.graphMeta, policy, MetaConflict{Scope: MetaScopeGraph}); err != nil {
+			return fmt.Errorf("merge config: %w", err)
+		}
+	}
+	return nil
+}
+
+// mergeStore copies the entries of src into dst, resolving keys set in both
+// by policy. c identifies the store for ResolveMeta. It stops at the first
+// value a strict dst rejects.
+func mergeStore[N, E any](dst, src *Store, policy *MergePolicy[N, E], c MetaConflict) error {
+	if dst.schema == nil && src.schema != nil {
+		dst.SetSchema(maps.Clone(src.schema))
+	}
+	var err error
+	src.Range(func(k string, v any) bool {
+		if existing, ok := dst.Get(k); ok {
+			switch {
+			case policy.ResolveMeta != nil:
+				c.Key, c.Existing, c.Incoming = k, existing, v
+				v = policy.ResolveMeta(c)
+			case policy.Strategy == MergeKeepExisting:
+				return true
+			}
+		}
+		err = dst.Set(k, v)
+		return err == nil
+	})
+	return err
+}
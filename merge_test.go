@@ -0,0 +1,159 @@
+package spine
+
+import (
+	"fmt"
+	"testing"
+)
+
+// mergeFixtures returns two overlapping fragments: both have node b, edge
+// a->b and the metadata key "owner" on b.
+func mergeFixtures() (dst, src *Graph[string, string]) {
+	dst = NewGraph[string, string](true)
+	dst.AddNode("a", "a-dst")
+	dst.AddNode("b", "b-dst")
+	dst.AddEdge("a", "b", "ab-dst", 1)
+	dst.NodeMeta("b").Set("owner", "alice")
+	dst.NodeMeta("b").Set("team", "core")
+
+	src = NewGraph[string, string](true)
+	src.AddNode("a", "a-src")
+	src.AddNode("b", "b-src")
+	src.AddNode("c", "c-src")
+	src.AddEdge("a", "b", "ab-src", 5)
+	src.AddEdge("b", "c", "bc-src", 2)
+	src.NodeMeta("b").Set("owner", "bob")
+	src.NodeMeta("c").Set("owner", "carol")
+	src.NodeMeta("c").SetSchema(Schema{"owner": {Type: FieldString}})
+	src.EdgeMeta("b", "c").Set("kind", "hard")
+	src.GraphMeta().Set("source", "agent-2")
+	return dst, src
+}
+
+func TestMergeLastWriteWins(t *testing.T) {
+	dst, src := mergeFixtures()
+	if err := Merge(dst, src, nil); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Order() != 3 || dst.Size() != 2 {
+		t.Fatalf("expected 3 nodes and 2 edges, got %d and %d", dst.Order(), dst.Size())
+	}
+	if n, _ := dst.GetNode("b"); n.Data != "b-src" {
+		t.Errorf("expected src data for b, got %q", n.Data)
+	}
+	if e, _ := dst.GetEdge("a", "b"); e.Data != "ab-src" || e.Weight != 5 {
+		t.Errorf("expected src edge a->b, got %+v", e)
+	}
+	if v, _ := dst.NodeMeta("b").Get("owner"); v != "bob" {
+		t.Errorf("expected owner bob, got %v", v)
+	}
+	if v, _ := dst.NodeMeta("b").Get("team"); v != "core" {
+		t.Errorf("expected dst-only key to be kept, got %v", v)
+	}
+	if v, _ := dst.EdgeMeta("b", "c").Get("kind"); v != "hard" {
+		t.Errorf("expected edge metadata to be copied, got %v", v)
+	}
+	if v, _ := dst.GraphMeta().Get("source"); v != "agent-2" {
+		t.Errorf("expected config to be merged, got %v", v)
+	}
+	if dst.NodeMeta("c").GetSchema() == nil {
+		t.Error("expected the schema of c to be copied")
+	}
+	if n, _ := src.GetNode("b"); n.Data != "b-src" || src.Order() != 3 {
+		t.Error("expected src to be unchanged")
+	}
+}
+
+func TestMergeKeepExisting(t *testing.T) {
+	dst, src := mergeFixtures()
+	if err := Merge(dst, src, &MergePolicy[string, string]{Strategy: MergeKeepExisting}); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := dst.GetNode("b"); n.Data != "b-dst" {
+		t.Errorf("expected dst data for b, got %q", n.Data)
+	}
+	if e, _ := dst.GetEdge("a", "b"); e.Data != "ab-dst" || e.Weight != 1 {
+		t.Errorf("expected dst edge a->b, got %+v", e)
+	}
+	if v, _ := dst.NodeMeta("b").Get("owner"); v != "alice" {
+		t.Errorf("expected owner alice, got %v", v)
+	}
+	if n, _ := dst.GetNode("c"); n.Data != "c-src" {
+		t.Errorf("expected new node c to be added, got %q", n.Data)
+	}
+}
+
+func TestMergeCallbacks(t *testing.T) {
+	dst, src := mergeFixtures()
+	var conflicts []string
+	err := Merge(dst, src, &MergePolicy[string, string]{
+		Strategy: MergeKeepExisting,
+		ResolveNode: func(id, existing, incoming string) string {
+			return existing + "+" + incoming
+		},
+		ResolveEdge: func(existing, incoming Edge[string]) Edge[string] {
+			existing.Weight = max(existing.Weight, incoming.Weight)
+			return existing
+		},
+		ResolveMeta: func(c MetaConflict) any {
+			conflicts = append(conflicts, fmt.Sprintf("%s:%s:%s", c.Scope, c.ID, c.Key))
+			return fmt.Sprint(c.Existing, ",", c.Incoming)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := dst.GetNode("b"); n.Data != "b-dst+b-src" {
+		t.Errorf("unexpected resolved node data %q", n.Data)
+	}
+	if e, _ := dst.GetEdge("a", "b"); e.Data != "ab-dst" || e.Weight != 5 {
+		t.Errorf("unexpected resolved edge %+v", e)
+	}
+	if v, _ := dst.NodeMeta("b").Get("owner"); v != "alice,bob" {
+		t.Errorf("unexpected resolved owner %v", v)
+	}
+	if len(conflicts) != 1 || conflicts[0] != "node:b:owner" {
+		t.Errorf("expected one conflict on node b, got %v", conflicts)
+	}
+	// Nodes only in src are not conflicts.
+	if n, _ := dst.GetNode("c"); n.Data != "c-src" {
+		t.Errorf("expected c to be copied as is, got %q", n.Data)
+	}
+}
+
+func TestMergeDirectedness(t *testing.T) {
+	if err := Merge(NewGraph[int, int](true), NewGraph[int, int](false), nil); err == nil {
+		t.Error("expected error for differing directedness")
+	}
+}
+
+func TestMergeUndirectedDirectedMeta(t *testing.T) {
+	dst := NewGraph[int, int](false)
+	src := NewGraph[int, int](false)
+	for _, g := range []*Graph[int, int]{dst, src} {
+		g.AddNode("x", 0)
+		g.AddNode("y", 0)
+		g.AddEdge("x", "y", 0, 1)
+	}
+	src.DirectedEdgeMeta("y", "x").Set("lanes", 2)
+	if err := Merge(dst, src, nil); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := dst.DirectedEdgeMeta("y", "x").Get("lanes"); v != 2 {
+		t.Errorf("expected per-direction metadata to be merged, got %v", v)
+	}
+	if dst.DirectedEdgeMeta("x", "y").Len() != 0 {
+		t.Error("expected the other direction to stay empty")
+	}
+}
+
+func TestMergeStrictStore(t *testing.T) {
+	dst, src := mergeFixtures()
+	dst.NodeMeta("b").SetSchema(Schema{"owner": {Type: FieldString, Enum: []any{"alice"}}})
+	dst.NodeMeta("b").SetStrict(true)
+	if err := Merge(dst, src, nil); err == nil {
+		t.Fatal("expected the strict store to reject the incoming owner")
+	}
+	if v, _ := dst.NodeMeta("b").Get("owner"); v != "alice" {
+		t.Errorf("expected owner to be kept, got %v", v)
+	}
+}
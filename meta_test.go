@@ -164,6 +164,112 @@ func TestUndirectedEdgeMetaSymmetry(t *testing.T) {
 	}
 }
 
+func TestUndirectedEdgeMetaCanonicalKey(t *testing.T) {
+	build := func(from, to string) *Graph[string, string] {
+		g := NewGraph[string, string](false)
+		g.AddNode("a", "A")
+		g.AddNode("b", "B")
+		g.AddEdge(from, to, "", 1.0)
+		g.EdgeMeta(to, from).Set("kind", "link")
+		return g
+	}
+
+	g := build("b", "a")
+	if _, ok := g.edgeMeta["a"]["b"]; !ok {
+		t.Fatal("expected metadata stored under canonical key a -> b")
+	}
+	if _, ok := g.edgeMeta["b"]; ok {
+		t.Fatal("expected no metadata under non-canonical key b -> a")
+	}
+	if g.EdgeMetaCount("b", "a") != 1 || g.EdgeMetaCount("a", "b") != 1 {
+		t.Fatal("expected both directions to report the same count")
+	}
+
+	d1, err := Marshal(build("a", "b"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d2, err := Marshal(build("b", "a"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(d1) != string(d2) {
+		t.Fatalf("expected identical serialization:\n%s\n%s", d1, d2)
+	}
+}
+
+func TestDirectedEdgeMeta(t *testing.T) {
+	g := NewGraph[string, string](false)
+	g.AddNode("a", "A")
+	g.AddNode("b", "B")
+	g.AddNode("c", "C")
+	g.AddEdge("a", "b", "", 1.0)
+	g.AddEdge("b", "c", "", 1.0)
+
+	ab := g.DirectedEdgeMeta("a", "b")
+	ba := g.DirectedEdgeMeta("b", "a")
+	if ab == nil || ab == ba {
+		t.Fatal("expected separate stores per direction")
+	}
+	ab.Set("trust", 0.9)
+	ba.Set("trust", 0.2)
+	g.EdgeMeta("a", "b").Set("kind", "peer")
+
+	if v, _ := g.DirectedEdgeMeta("a", "b").Get("trust"); v != 0.9 {
+		t.Errorf("expected a->b trust 0.9, got %v", v)
+	}
+	if v, _ := g.DirectedEdgeMeta("b", "a").Get("trust"); v != 0.2 {
+		t.Errorf("expected b->a trust 0.2, got %v", v)
+	}
+	if _, ok := g.EdgeMeta("b", "a").Get("trust"); ok {
+		t.Error("directed metadata should not leak into the symmetric store")
+	}
+	if g.DirectedEdgeMeta("a", "c") != nil {
+		t.Error("expected nil for a missing edge")
+	}
+
+	// Topology is still undirected.
+	if !g.HasEdge("b", "a") || g.Size() != 2 {
+		t.Errorf("expected undirected topology, got size %d", g.Size())
+	}
+	if path, _, err := ShortestPath(g, "c", "a"); err != nil || len(path) != 3 {
+		t.Errorf("expected path c-b-a, got %v", path)
+	}
+
+	// Per-direction stores survive copies and a serialization round trip.
+	if v, _ := g.Copy().DirectedEdgeMeta("b", "a").Get("trust"); v != 0.2 {
+		t.Errorf("expected copy to keep b->a trust, got %v", v)
+	}
+	data, err := Marshal(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2, err := Unmarshal[string, string](data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := g2.DirectedEdgeMeta("a", "b").Get("trust"); v != 0.9 {
+		t.Errorf("expected round-tripped a->b trust 0.9, got %v", v)
+	}
+	if v, _ := g2.DirectedEdgeMeta("b", "a").Get("trust"); v != 0.2 {
+		t.Errorf("expected round-tripped b->a trust 0.2, got %v", v)
+	}
+
+	g.RemoveEdge("b", "a")
+	if len(g.dirEdgeMeta) != 0 {
+		t.Errorf("expected directed metadata cleaned up, got %v", g.dirEdgeMeta)
+	}
+
+	// On directed graphs it is the ordinary edge store.
+	d := NewGraph[string, string](true)
+	d.AddNode("a", "A")
+	d.AddNode("b", "B")
+	d.AddEdge("a", "b", "", 1.0)
+	if d.DirectedEdgeMeta("a", "b") != d.EdgeMeta("a", "b") {
+		t.Error("expected DirectedEdgeMeta to match EdgeMeta on directed graphs")
+	}
+}
+
 func TestSubgraphPreservesMetadata(t *testing.T) {
 	g := NewGraph[string, string](true)
 	g.AddNode("a", "A")
@@ -0,0 +1,139 @@
+package spine
+
+import "sort"
+
+// metaIndex maps the values of one node metadata key to the nodes holding
+// them. Values are normalised by indexValue.
+type metaIndex struct {
+	byValue map[any]map[string]struct{}
+	values  map[string]any // node ID -> indexed value, to unindex it on change
+}
+
+// indexValue returns the map key under which v is indexed: numbers of any
+// type as float64, so 1 and 1.0 match, and strings and bools as is. Other
+// values are not indexed.
+func indexValue(v any) (any, bool) {
+	if f, ok := numericValue(v); ok {
+		return f, true
+	}
+	switch v.(type) {
+	case string, bool:
+		return v, true
+	}
+	return nil, false
+}
+
+func (idx *metaIndex) add(id string, v any) {
+	k, ok := indexValue(v)
+	if !ok {
+		return
+	}
+	if idx.byValue[k] == nil {
+		idx.byValue[k] = make(map[string]struct{})
+	}
+	idx.byValue[k][id] = struct{}{}
+	idx.values[id] = k
+}
+
+func (idx *metaIndex) remove(id string) {
+	k, ok := idx.values[id]
+	if !ok {
+		return
+	}
+	delete(idx.values, id)
+	delete(idx.byValue[k], id)
+	if len(idx.byValue[k]) == 0 {
+		delete(idx.byValue, k)
+	}
+}
+
+// IndexMetaKey maintains an index of the node metadata values under key, so
+// FindNodesByMeta answers equality lookups on it without scanning every
+// node. The index is built from the current metadata and then kept up to
+// date by every Set and Delete on node stores. String, bool and numeric
+// values are indexed; nodes holding other values under key are found only by
+// a scan. Indexes are carried over by Copy but not serialized. Indexing a key
+// twice is a no-op.
+func (g *Graph[N, E]) IndexMetaKey(key string) {
+	if g.metaIndexes[key] != nil {
+		return
+	}
+	if g.metaIndexes == nil {
+		g.metaIndexes = make(map[string]*metaIndex)
+	}
+	idx := &metaIndex{byValue: make(map[any]map[string]struct{}), values: make(map[string]any)}
+	for id, store := range g.nodeMeta {
+		if v, ok := store.Get(key); ok {
+			idx.add(id, v)
+		}
+	}
+	g.metaIndexes[key] = idx
+	if len(g.metaIndexes) == 1 {
+		// Stores are only watched while something needs their changes.
+		g.watchAllMeta()
+	}
+}
+
+// DropMetaIndex removes the index on key, if any.
+func (g *Graph[N, E]) DropMetaIndex(key string) {
+	delete(g.metaIndexes, key)
+}
+
+// IndexedMetaKeys returns the node metadata keys with an index, sorted.
+func (g *Graph[N, E]) IndexedMetaKeys() []string {
+	keys := make([]string, 0, len(g.metaIndexes))
+	for k := range g.metaIndexes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MetaIndexed reports whether key has an index.
+func (g *Graph[N, E]) MetaIndexed(key string) bool {
+	return g.metaIndexes[key] != nil
+}
+
+// FindNodesByMeta returns the IDs of the nodes whose metadata value under key
+// equals value, sorted. Numbers compare by value regardless of type. It uses
+// the index on key if there is one, and scans every node's metadata
+// otherwise.
+func (g *Graph[N, E]) FindNodesByMeta(key string, value any) []string {
+	var ids []string
+	if idx := g.metaIndexes[key]; idx != nil {
+		if k, ok := indexValue(value); ok {
+			for id := range idx.byValue[k] {
+				// The index keeps entries that have expired but are not
+				// deleted yet; Get hides them.
+				store := g.nodeMeta[id]
+				if store == nil {
+					continue
+				}
+				if _, ok := store.Get(key); ok {
+					ids = append(ids, id)
+				}
+			}
+			g.sortIDs(ids)
+			return ids
+		}
+	}
+	for id, store := range g.nodeMeta {
+		if v, ok := store.Get(key); ok && sameValue(v, value) {
+			ids = append(ids, id)
+		}
+	}
+	g.sortIDs(ids)
+	return ids
+}
+
+// updateMetaIndex applies a change of node id's metadata key to its index.
+func (g *Graph[N, E]) updateMetaIndex(id, key string, value any, deleted bool) {
+	idx := g.metaIndexes[key]
+	if idx == nil {
+		return
+	}
+	idx.remove(id)
+	if !deleted {
+		idx.add(id, value)
+	}
+}
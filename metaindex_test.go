@@ -0,0 +1,100 @@
+package spine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func metaIndexFixture() *Graph[string, string] {
+	g := NewGraph[string, string](true)
+	for i, status := range []string{"open", "done", "open", "blocked"} {
+		id := string(rune('a' + i))
+		g.AddNode(id, "")
+		g.NodeMeta(id).Set("status", status)
+		g.NodeMeta(id).Set("priority", i%2)
+	}
+	return g
+}
+
+func TestFindNodesByMetaIndex(t *testing.T) {
+	g := metaIndexFixture()
+	scan := g.FindNodesByMeta("status", "open")
+	g.IndexMetaKey("status")
+	g.IndexMetaKey("priority")
+	if got := g.IndexedMetaKeys(); !reflect.DeepEqual(got, []string{"priority", "status"}) {
+		t.Fatalf("unexpected indexed keys %v", got)
+	}
+	if got := g.FindNodesByMeta("status", "open"); !reflect.DeepEqual(got, scan) || len(got) != 2 {
+		t.Fatalf("expected index and scan to agree on %v, got %v", scan, got)
+	}
+	// Numbers match by value: priorities are ints, JSON filters float64s.
+	if got := g.FindNodesByMeta("priority", 1.0); !reflect.DeepEqual(got, []string{"b", "d"}) {
+		t.Fatalf("expected [b d], got %v", got)
+	}
+
+	// Set, Delete, node and store changes are reflected.
+	g.NodeMeta("b").Set("status", "open")
+	g.NodeMeta("c").Delete("status")
+	g.RemoveNode("a")
+	g.AddNode("e", "")
+	g.NodeMeta("e").Set("status", "open")
+	g.NodeMeta("d").Clear()
+	if got := g.FindNodesByMeta("status", "open"); !reflect.DeepEqual(got, []string{"b", "e"}) {
+		t.Fatalf("expected [b e], got %v", got)
+	}
+	if got := g.FindNodesByMeta("status", "blocked"); got != nil {
+		t.Fatalf("expected cleared node to be unindexed, got %v", got)
+	}
+
+	c := g.Copy()
+	g.NodeMeta("b").Set("status", "done")
+	if got := c.FindNodesByMeta("status", "open"); !c.MetaIndexed("status") || !reflect.DeepEqual(got, []string{"b", "e"}) {
+		t.Fatalf("expected the copy to keep its own index, got %v", got)
+	}
+
+	g.DropMetaIndex("status")
+	if g.MetaIndexed("status") || !reflect.DeepEqual(g.FindNodesByMeta("status", "open"), []string{"e"}) {
+		t.Error("expected a scan after dropping the index")
+	}
+}
+
+func TestMetaIndexBatchAndTTL(t *testing.T) {
+	advance := fakeClock(t)
+	g := metaIndexFixture()
+	g.IndexMetaKey("status")
+	err := g.Batch(func(tx *Tx[string, string]) error {
+		tx.NodeMeta("d").Set("status", "open")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.NodeMeta("a").SetWithTTL("status", "open", time.Minute)
+	if got := g.FindNodesByMeta("status", "open"); !reflect.DeepEqual(got, []string{"a", "c", "d"}) {
+		t.Fatalf("expected batched and TTL writes to be indexed, got %v", got)
+	}
+	advance(time.Minute)
+	if got := g.FindNodesByMeta("status", "open"); !reflect.DeepEqual(got, []string{"c", "d"}) {
+		t.Fatalf("expected the expired entry to be hidden, got %v", got)
+	}
+	g.NodeMeta("c").Set("status", []string{"open"})
+	if got := g.FindNodesByMeta("status", []string{"open"}); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Errorf("expected unindexed values to be found by a scan, got %v", got)
+	}
+}
+
+func TestMetaIndexDetachedStore(t *testing.T) {
+	g := metaIndexFixture()
+	g.IndexMetaKey("tag")
+	s := g.NodeMeta("a")
+	g.RemoveNode("a")
+	s.Set("tag", "x")
+	if got := g.FindNodesByMeta("tag", "x"); got != nil {
+		t.Fatalf("expected writes to a removed node's store to be ignored, got %v", got)
+	}
+	g.AddNode("a", "")
+	if g.NodeMetaCount("a") != 0 || g.FindNodesByMeta("tag", "x") != nil {
+		t.Error("expected a re-added node to start without metadata")
+	}
+}
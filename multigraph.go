@@ -0,0 +1,255 @@
+package spine
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MultiEdge is an edge of a Multigraph, identified by its own ID so several
+// edges may connect the same pair of nodes.
+type MultiEdge[T any] struct {
+	ID     string
+	From   string
+	To     string
+	Data   T
+	Weight float64
+}
+
+// Multigraph is a graph that allows parallel edges. Edges are keyed by ID
+// rather than by their endpoints, and each edge ID has its own metadata
+// store. Use Simple to run the Graph algorithms on it.
+type Multigraph[N, E any] struct {
+	Directed bool
+	nodes    map[string]Node[N]
+	edges    map[string]MultiEdge[E]    // edge ID -> edge
+	out      map[string]map[string]bool // node -> outgoing edge IDs (all incident IDs if undirected)
+	in       map[string]map[string]bool // node -> incoming edge IDs (all incident IDs if undirected)
+	nodeMeta map[string]*Store          // node ID -> metadata store
+	edgeMeta map[string]*Store          // edge ID -> metadata store
+}
+
+// NewMultigraph creates a new multigraph. If directed is true, edges are one-way.
+func NewMultigraph[N, E any](directed bool) *Multigraph[N, E] {
+	return &Multigraph[N, E]{
+		Directed: directed,
+		nodes:    make(map[string]Node[N]),
+		edges:    make(map[string]MultiEdge[E]),
+		out:      make(map[string]map[string]bool),
+		in:       make(map[string]map[string]bool),
+		nodeMeta: make(map[string]*Store),
+		edgeMeta: make(map[string]*Store),
+	}
+}
+
+// AddNode adds a node or overwrites its data if it already exists.
+func (m *Multigraph[N, E]) AddNode(id string, data N) {
+	m.nodes[id] = Node[N]{ID: id, Data: data}
+	if m.out[id] == nil {
+		m.out[id] = make(map[string]bool)
+	}
+	if m.in[id] == nil {
+		m.in[id] = make(map[string]bool)
+	}
+}
+
+// GetNode returns the node with the given ID.
+func (m *Multigraph[N, E]) GetNode(id string) (Node[N], bool) {
+	n, ok := m.nodes[id]
+	return n, ok
+}
+
+// HasNode returns true if the node exists.
+func (m *Multigraph[N, E]) HasNode(id string) bool {
+	_, ok := m.nodes[id]
+	return ok
+}
+
+// RemoveNode removes a node, its incident edges, and their metadata.
+func (m *Multigraph[N, E]) RemoveNode(id string) {
+	if !m.HasNode(id) {
+		return
+	}
+	for eid := range m.out[id] {
+		m.RemoveEdge(eid)
+	}
+	for eid := range m.in[id] {
+		m.RemoveEdge(eid)
+	}
+	delete(m.nodes, id)
+	delete(m.out, id)
+	delete(m.in, id)
+	delete(m.nodeMeta, id)
+}
+
+// AddEdgeID adds the edge id from -> to, or replaces it if an edge with that
+// ID already exists. Both nodes must exist. Replacing an edge keeps its
+// metadata.
+func (m *Multigraph[N, E]) AddEdgeID(id, from, to string, data E, weight float64) error {
+	if !m.HasNode(from) {
+		return fmt.Errorf("node %q not found", from)
+	}
+	if !m.HasNode(to) {
+		return fmt.Errorf("node %q not found", to)
+	}
+	if old, ok := m.edges[id]; ok {
+		m.unlink(old)
+	}
+	e := MultiEdge[E]{ID: id, From: from, To: to, Data: data, Weight: weight}
+	m.edges[id] = e
+	m.out[from][id] = true
+	m.in[to][id] = true
+	if !m.Directed {
+		m.out[to][id] = true
+		m.in[from][id] = true
+	}
+	return nil
+}
+
+// GetEdge returns the edge with the given ID.
+func (m *Multigraph[N, E]) GetEdge(id string) (MultiEdge[E], bool) {
+	e, ok := m.edges[id]
+	return e, ok
+}
+
+// HasEdge returns true if an edge with the given ID exists.
+func (m *Multigraph[N, E]) HasEdge(id string) bool {
+	_, ok := m.edges[id]
+	return ok
+}
+
+// RemoveEdge removes the edge with the given ID and its metadata.
+func (m *Multigraph[N, E]) RemoveEdge(id string) {
+	e, ok := m.edges[id]
+	if !ok {
+		return
+	}
+	m.unlink(e)
+	delete(m.edges, id)
+	delete(m.edgeMeta, id)
+}
+
+// unlink drops e from the adjacency indexes.
+func (m *Multigraph[N, E]) unlink(e MultiEdge[E]) {
+	delete(m.out[e.From], e.ID)
+	delete(m.in[e.To], e.ID)
+	if !m.Directed {
+		delete(m.out[e.To], e.ID)
+		delete(m.in[e.From], e.ID)
+	}
+}
+
+// Nodes returns all nodes sorted by ID.
+func (m *Multigraph[N, E]) Nodes() []Node[N] {
+	result := make([]Node[N], 0, len(m.nodes))
+	for _, n := range m.nodes {
+		result = append(result, n)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// Edges returns all edges sorted by ID.
+func (m *Multigraph[N, E]) Edges() []MultiEdge[E] {
+	result := make([]MultiEdge[E], 0, len(m.edges))
+	for _, e := range m.edges {
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// OutEdges returns the edges leaving the given node, sorted by ID. On
+// undirected multigraphs every incident edge is returned, oriented so that
+// From is the given node.
+func (m *Multigraph[N, E]) OutEdges(id string) []MultiEdge[E] {
+	return m.incident(m.out[id], id, true)
+}
+
+// InEdges returns the edges entering the given node, sorted by ID. On
+// undirected multigraphs every incident edge is returned, oriented so that
+// To is the given node.
+func (m *Multigraph[N, E]) InEdges(id string) []MultiEdge[E] {
+	return m.incident(m.in[id], id, false)
+}
+
+func (m *Multigraph[N, E]) incident(ids map[string]bool, node string, outgoing bool) []MultiEdge[E] {
+	result := make([]MultiEdge[E], 0, len(ids))
+	for eid := range ids {
+		e := m.edges[eid]
+		if !m.Directed && ((outgoing && e.From != node) || (!outgoing && e.To != node)) {
+			e.From, e.To = e.To, e.From
+		}
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// EdgesBetween returns the parallel edges from -> to, sorted by ID. On
+// undirected multigraphs edges in either direction are returned, oriented
+// from -> to.
+func (m *Multigraph[N, E]) EdgesBetween(from, to string) []MultiEdge[E] {
+	var result []MultiEdge[E]
+	for _, e := range m.OutEdges(from) {
+		if e.To == to {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Order returns the number of nodes.
+func (m *Multigraph[N, E]) Order() int {
+	return len(m.nodes)
+}
+
+// Size returns the number of edges, counting parallel edges separately.
+func (m *Multigraph[N, E]) Size() int {
+	return len(m.edges)
+}
+
+// NodeMeta returns the metadata store for the given node, creating it lazily.
+// Returns nil if the node does not exist.
+func (m *Multigraph[N, E]) NodeMeta(id string) *Store {
+	if !m.HasNode(id) {
+		return nil
+	}
+	if m.nodeMeta[id] == nil {
+		m.nodeMeta[id] = NewStore()
+	}
+	return m.nodeMeta[id]
+}
+
+// EdgeMeta returns the metadata store for the edge with the given ID,
+// creating it lazily. Each parallel edge has its own store. Returns nil if
+// the edge does not exist.
+func (m *Multigraph[N, E]) EdgeMeta(id string) *Store {
+	if !m.HasEdge(id) {
+		return nil
+	}
+	if m.edgeMeta[id] == nil {
+		m.edgeMeta[id] = NewStore()
+	}
+	return m.edgeMeta[id]
+}
+
+// Simple returns a Graph with the same nodes in which each set of parallel
+// edges is collapsed to its cheapest edge (ties broken by edge ID), so the
+// traversal and analysis functions can be used. Node metadata is copied;
+// edge metadata is not, since it belongs to individual edge IDs.
+func (m *Multigraph[N, E]) Simple() *Graph[N, E] {
+	g := NewGraph[N, E](m.Directed)
+	for _, n := range m.Nodes() {
+		g.AddNode(n.ID, n.Data)
+		if store := m.nodeMeta[n.ID]; store != nil && store.Len() > 0 {
+			g.nodeMeta[n.ID] = store.Copy()
+		}
+	}
+	for _, e := range m.Edges() {
+		if cur, ok := g.GetEdge(e.From, e.To); ok && cur.Weight <= e.Weight {
+			continue
+		}
+		_ = g.AddEdge(e.From, e.To, e.Data, e.Weight)
+	}
+	return g
+}
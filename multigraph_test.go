@@ -0,0 +1,78 @@
+package spine
+
+import "testing"
+
+func TestMultigraphParallelEdges(t *testing.T) {
+	m := NewMultigraph[string, string](true)
+	m.AddNode("api", "API")
+	m.AddNode("db", "DB")
+	if err := m.AddEdgeID("reads", "api", "db", "read", 5); err != nil {
+		t.Fatal(err)
+	}
+	m.AddEdgeID("writes", "api", "db", "write", 12)
+	m.AddEdgeID("replicates", "db", "api", "cdc", 30)
+	if err := m.AddEdgeID("bad", "api", "missing", "", 1); err == nil {
+		t.Error("expected error for missing node")
+	}
+
+	if m.Size() != 3 {
+		t.Fatalf("expected 3 edges, got %d", m.Size())
+	}
+	between := m.EdgesBetween("api", "db")
+	if len(between) != 2 || between[0].ID != "reads" || between[1].ID != "writes" {
+		t.Fatalf("expected parallel edges [reads writes], got %+v", between)
+	}
+	if in := m.InEdges("api"); len(in) != 1 || in[0].ID != "replicates" {
+		t.Errorf("unexpected in-edges: %+v", in)
+	}
+
+	// Each edge ID has its own metadata store.
+	m.EdgeMeta("reads").Set("qps", 900)
+	m.EdgeMeta("writes").Set("qps", 40)
+	if v, _ := m.EdgeMeta("reads").Get("qps"); v != 900 {
+		t.Errorf("expected reads qps 900, got %v", v)
+	}
+	if m.EdgeMeta("missing") != nil {
+		t.Error("expected nil store for a missing edge")
+	}
+
+	// Simple keeps the cheapest parallel edge.
+	g := m.Simple()
+	if e, _ := g.GetEdge("api", "db"); g.Size() != 2 || e.Data != "read" {
+		t.Errorf("expected the read edge to survive, got %+v (size %d)", e, g.Size())
+	}
+
+	m.RemoveEdge("reads")
+	if m.HasEdge("reads") || len(m.EdgesBetween("api", "db")) != 1 {
+		t.Error("expected reads to be removed")
+	}
+	m.RemoveNode("db")
+	if m.Size() != 0 || len(m.OutEdges("api")) != 0 {
+		t.Errorf("expected incident edges removed with the node, got %d", m.Size())
+	}
+}
+
+func TestMultigraphUndirected(t *testing.T) {
+	m := NewMultigraph[int, int](false)
+	m.AddNode("a", 0)
+	m.AddNode("b", 0)
+	m.AddEdgeID("e1", "a", "b", 1, 1)
+	m.AddEdgeID("e2", "b", "a", 2, 1)
+
+	between := m.EdgesBetween("a", "b")
+	if len(between) != 2 {
+		t.Fatalf("expected 2 edges between a and b, got %+v", between)
+	}
+	for _, e := range between {
+		if e.From != "a" || e.To != "b" {
+			t.Errorf("expected edges oriented a->b, got %+v", e)
+		}
+	}
+
+	// Re-pointing an edge ID moves it.
+	m.AddNode("c", 0)
+	m.AddEdgeID("e2", "a", "c", 2, 1)
+	if len(m.EdgesBetween("b", "a")) != 1 || len(m.OutEdges("c")) != 1 {
+		t.Errorf("expected e2 to move to a-c")
+	}
+}
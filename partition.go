@@ -0,0 +1,65 @@
+package spine
+
+// Partition splits the nodes of g into k parts of near-equal size (sizes
+// differ by at most one), trying to keep connected nodes together so few
+// edges cross parts. It uses greedy graph growing: each part is seeded with
+// the smallest unassigned node ID and repeatedly absorbs the unassigned node
+// with the most edges into the part (ties by smallest ID), falling back to
+// the smallest unassigned node when the part has no unassigned neighbors.
+// Edge direction is ignored. The result maps node ID to a part in [0, k) and
+// is deterministic. Returns nil if k < 1.
+func Partition[N, E any](g *Graph[N, E], k int) map[string]int {
+	if k < 1 {
+		return nil
+	}
+	nodes := g.Nodes()
+	n := len(nodes)
+	parts := make(map[string]int, n)
+
+	// Undirected adjacency with edge multiplicities; self-loops ignored.
+	adj := make(map[string]map[string]int, n)
+	for _, nd := range nodes {
+		adj[nd.ID] = make(map[string]int)
+	}
+	for _, e := range g.Edges() {
+		if e.From == e.To {
+			continue
+		}
+		adj[e.From][e.To]++
+		adj[e.To][e.From]++
+	}
+
+	next := 0 // index into nodes of the smallest possibly-unassigned node
+	for p := 0; p < k && len(parts) < n; p++ {
+		size := n / k
+		if p < n%k {
+			size++
+		}
+		gain := make(map[string]int) // unassigned frontier node -> edges into part p
+		for added := 0; added < size; added++ {
+			pick := ""
+			for id, gn := range gain {
+				if pick == "" || gn > gain[pick] || (gn == gain[pick] && id < pick) {
+					pick = id
+				}
+			}
+			if pick == "" {
+				for next < n {
+					if _, done := parts[nodes[next].ID]; !done {
+						break
+					}
+					next++
+				}
+				pick = nodes[next].ID
+			}
+			parts[pick] = p
+			delete(gain, pick)
+			for nb, w := range adj[pick] {
+				if _, done := parts[nb]; !done {
+					gain[nb] += w
+				}
+			}
+		}
+	}
+	return parts
+}
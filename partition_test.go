@@ -0,0 +1,70 @@
+package spine
+
+import (
+	"testing"
+)
+
+func TestPartitionTwoClusters(t *testing.T) {
+	g := NewGraph[string, int](false)
+	left := []string{"a1", "a2", "a3", "a4"}
+	right := []string{"b1", "b2", "b3", "b4"}
+	for _, cluster := range [][]string{left, right} {
+		for _, id := range cluster {
+			g.AddNode(id, id)
+		}
+		for i := range cluster {
+			for j := i + 1; j < len(cluster); j++ {
+				g.AddEdge(cluster[i], cluster[j], 0, 1)
+			}
+		}
+	}
+	g.AddEdge("a4", "b1", 0, 1) // single bridge
+
+	parts := Partition(g, 2)
+	if len(parts) != 8 {
+		t.Fatalf("expected all 8 nodes assigned, got %v", parts)
+	}
+	for _, cluster := range [][]string{left, right} {
+		for _, id := range cluster[1:] {
+			if parts[id] != parts[cluster[0]] {
+				t.Errorf("expected %s with %s, got %v", id, cluster[0], parts)
+			}
+		}
+	}
+	if parts["a1"] == parts["b1"] {
+		t.Errorf("expected clusters in different partitions, got %v", parts)
+	}
+}
+
+func TestPartitionBalanced(t *testing.T) {
+	g := NewGraph[string, int](true)
+	ids := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	for i, id := range ids {
+		g.AddNode(id, id)
+		if i > 0 {
+			g.AddEdge(ids[i-1], id, 0, 1)
+		}
+	}
+
+	parts := Partition(g, 4)
+	sizes := make(map[int]int)
+	for _, p := range parts {
+		if p < 0 || p >= 4 {
+			t.Fatalf("partition %d out of range", p)
+		}
+		sizes[p]++
+	}
+	for p := 0; p < 4; p++ {
+		if sizes[p] < 2 || sizes[p] > 3 {
+			t.Errorf("expected part sizes of 2 or 3, got %v", sizes)
+		}
+	}
+	// A path splits into contiguous runs.
+	if parts["a"] != parts["b"] || parts["a"] == parts["j"] {
+		t.Errorf("expected contiguous runs, got %v", parts)
+	}
+
+	if Partition(g, 0) != nil {
+		t.Error("expected nil for k < 1")
+	}
+}
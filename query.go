@@ -3,6 +3,8 @@ package spine
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"slices"
 	"sort"
 )
 
@@ -47,7 +49,7 @@ func Ancestors[N, E any](g *Graph[N, E], id string) []string {
 	for v := range visited {
 		result = append(result, v)
 	}
-	sort.Strings(result)
+	g.sortIDs(result)
 	return result
 }
 
@@ -70,10 +72,134 @@ func Descendants[N, E any](g *Graph[N, E], id string) []string {
 	for v := range visited {
 		result = append(result, v)
 	}
-	sort.Strings(result)
+	g.sortIDs(result)
 	return result
 }
 
+// CommonDescendants returns the nodes reachable from both a and b, sorted by
+// ID: the intersection of Descendants(g, a) and Descendants(g, b).
+func CommonDescendants[N, E any](g *Graph[N, E], a, b string) []string {
+	return intersectSorted(Descendants(g, a), Descendants(g, b), g.lessID)
+}
+
+// CommonAncestors returns the nodes that can reach both a and b, sorted by
+// ID: the intersection of Ancestors(g, a) and Ancestors(g, b).
+func CommonAncestors[N, E any](g *Graph[N, E], a, b string) []string {
+	return intersectSorted(Ancestors(g, a), Ancestors(g, b), g.lessID)
+}
+
+// intersectSorted returns the elements present in both slices, which must be
+// sorted by less.
+func intersectSorted(x, y []string, less func(a, b string) bool) []string {
+	result := make([]string, 0)
+	for i, j := 0, 0; i < len(x) && j < len(y); {
+		switch {
+		case less(x[i], y[j]):
+			i++
+		case less(y[j], x[i]):
+			j++
+		default:
+			result = append(result, x[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// Transpose returns a new graph with every edge reversed. Node and edge data
+// are copied, as is the ID order set with SetIDLess; metadata is not. For
+// undirected graphs the result has the same structure as g.
+func Transpose[N, E any](g *Graph[N, E]) *Graph[N, E] {
+	t := NewGraph[N, E](g.Directed)
+	t.idLess = g.idLess
+	for _, n := range g.Nodes() {
+		t.AddNode(n.ID, n.Data)
+	}
+	for _, e := range g.Edges() {
+		t.AddEdge(e.To, e.From, e.Data, e.Weight)
+	}
+	return t
+}
+
+// EdgeConflict describes a pair of opposing directed edges with divergent
+// weight or data that ToUndirected merged into one undirected edge. From is
+// the lexicographically smaller endpoint.
+type EdgeConflict[E any] struct {
+	From     string
+	To       string
+	Forward  Edge[E] // From -> To
+	Backward Edge[E] // To -> From
+	Merged   Edge[E]
+}
+
+// ToUndirected returns an undirected copy of g. Node data and node and edge
+// metadata are copied. When both a->b and b->a exist with different weights
+// or data, merge chooses the resulting edge (only its Data and Weight are
+// used) and the pair is reported as a conflict; a nil merge keeps the edge
+// whose From is smaller. On metadata key collisions the forward edge wins.
+// Conflicts are sorted by From then To. If g is already undirected, a copy is
+// returned with no conflicts.
+func ToUndirected[N, E any](g *Graph[N, E], merge func(forward, backward Edge[E]) Edge[E]) (*Graph[N, E], []EdgeConflict[E]) {
+	if !g.Directed {
+		return g.Copy(), nil
+	}
+	u := NewGraph[N, E](false)
+	u.idLess = g.idLess
+	for _, n := range g.Nodes() {
+		u.AddNode(n.ID, n.Data)
+		if g.NodeMetaCount(n.ID) > 0 {
+			u.nodeMeta[n.ID] = g.NodeMeta(n.ID).Copy()
+		}
+	}
+	if g.graphMeta != nil {
+		u.graphMeta = g.graphMeta.Copy()
+	}
+
+	edges, _ := g.EdgesPage(0, 0)
+	var conflicts []EdgeConflict[E]
+	for _, e := range edges {
+		back, hasBack := g.GetEdge(e.To, e.From)
+		if hasBack && e.From > e.To {
+			continue // handled when visiting the smaller endpoint
+		}
+		merged := e
+		if hasBack && e.From != e.To && (e.Weight != back.Weight || !reflect.DeepEqual(e.Data, back.Data)) {
+			if merge != nil {
+				merged = merge(e, back)
+			}
+			merged.From, merged.To = e.From, e.To
+			conflicts = append(conflicts, EdgeConflict[E]{
+				From: e.From, To: e.To, Forward: e, Backward: back, Merged: merged,
+			})
+		}
+		u.AddEdge(e.From, e.To, merged.Data, merged.Weight)
+
+		// Copy backward metadata first so forward keys overwrite it.
+		for _, pair := range [][2]string{{e.To, e.From}, {e.From, e.To}} {
+			if !g.HasEdge(pair[0], pair[1]) || g.EdgeMetaCount(pair[0], pair[1]) == 0 {
+				continue
+			}
+			dst := u.EdgeMeta(e.From, e.To)
+			g.EdgeMeta(pair[0], pair[1]).Range(func(k string, v any) bool {
+				dst.Set(k, v)
+				return true
+			})
+		}
+	}
+	return u, conflicts
+}
+
+// AncestorIndex builds the transpose of g once and returns a function that
+// answers Ancestors queries against it. Use it when issuing many ancestor
+// queries on a graph that is not changing; later mutations of g are not seen.
+func AncestorIndex[N, E any](g *Graph[N, E]) func(id string) []string {
+	t := Transpose(g)
+	return func(id string) []string {
+		return Descendants(t, id)
+	}
+}
+
 // Roots returns nodes with in-degree 0 (no incoming edges).
 func Roots[N, E any](g *Graph[N, E]) []Node[N] {
 	var result []Node[N]
@@ -96,6 +222,85 @@ func Leaves[N, E any](g *Graph[N, E]) []Node[N] {
 	return result
 }
 
+// PruneUnreachable removes every node that is not reachable from any of the
+// given roots by following out-edges, and returns the removed IDs sorted.
+// If roots is empty, the graph's computed Roots are used. Root IDs that do
+// not exist are ignored. If that leaves no root, as in a graph where every
+// node lies on or below a cycle, nothing is removed.
+func PruneUnreachable[N, E any](g *Graph[N, E], roots []string) []string {
+	if len(roots) == 0 {
+		for _, n := range Roots(g) {
+			roots = append(roots, n.ID)
+		}
+	}
+	if !slices.ContainsFunc(roots, g.HasNode) {
+		return nil
+	}
+	reachable := make(map[string]bool)
+	for _, r := range roots {
+		if !g.HasNode(r) || reachable[r] {
+			continue
+		}
+		reachable[r] = true
+		for _, id := range Descendants(g, r) {
+			reachable[id] = true
+		}
+	}
+	var removed []string
+	for _, n := range g.Nodes() {
+		if !reachable[n.ID] {
+			removed = append(removed, n.ID)
+		}
+	}
+	for _, id := range removed {
+		g.RemoveNode(id)
+	}
+	return removed
+}
+
+// NormalizeWeights rescales every edge weight in place. Mode "minmax" maps
+// the smallest weight to 0 and the largest to 1 (all weights become 0 if they
+// are equal); mode "sum" divides each weight by the total so they sum to 1,
+// counting an undirected edge once. Undirected reverse edges are updated too.
+// Each updated edge is recorded as an OpAddEdge.
+func NormalizeWeights[N, E any](g *Graph[N, E], mode string) error {
+	edges := g.Edges()
+	if len(edges) == 0 {
+		return nil
+	}
+	var scale func(w float64) float64
+	switch mode {
+	case "minmax":
+		lo, hi := edges[0].Weight, edges[0].Weight
+		for _, e := range edges[1:] {
+			lo = min(lo, e.Weight)
+			hi = max(hi, e.Weight)
+		}
+		scale = func(w float64) float64 {
+			if hi == lo {
+				return 0
+			}
+			return (w - lo) / (hi - lo)
+		}
+	case "sum":
+		total := 0.0
+		for _, e := range edges {
+			total += e.Weight
+		}
+		if total == 0 {
+			return errors.New("cannot normalize weights that sum to zero")
+		}
+		scale = func(w float64) float64 { return w / total }
+	default:
+		return fmt.Errorf("unknown normalization mode %q", mode)
+	}
+	// Going through AddEdge records each change in history.
+	for _, e := range edges {
+		_ = g.AddEdge(e.From, e.To, e.Data, scale(e.Weight))
+	}
+	return nil
+}
+
 // Analytics holds graph-level statistics.
 type Analytics struct {
 	NodeCount    int            `json:"node_count"`
@@ -356,7 +561,8 @@ type WeightChange struct {
 	NewWeight float64 `json:"new_weight"`
 }
 
-// Diff computes the differences between two graphs.
+// Diff computes the differences between two graphs. Results are sorted
+// using the ID order of a (see SetIDLess).
 func Diff[N, E any](a, b *Graph[N, E]) (*DiffResult, error) {
 	if a.Directed != b.Directed {
 		return nil, errors.New("cannot diff graphs with different directed modes")
@@ -379,14 +585,14 @@ func Diff[N, E any](a, b *Graph[N, E]) (*DiffResult, error) {
 			result.NodesAdded = append(result.NodesAdded, id)
 		}
 	}
-	sort.Strings(result.NodesAdded)
+	a.sortIDs(result.NodesAdded)
 
 	for id := range aNodes {
 		if !bNodes[id] {
 			result.NodesRemoved = append(result.NodesRemoved, id)
 		}
 	}
-	sort.Strings(result.NodesRemoved)
+	a.sortIDs(result.NodesRemoved)
 
 	// Edge differences
 	aEdges := make(map[[2]string]float64)
@@ -412,15 +618,15 @@ func Diff[N, E any](a, b *Graph[N, E]) (*DiffResult, error) {
 	}
 	sort.Slice(result.EdgesAdded, func(i, j int) bool {
 		if result.EdgesAdded[i][0] != result.EdgesAdded[j][0] {
-			return result.EdgesAdded[i][0] < result.EdgesAdded[j][0]
+			return a.lessID(result.EdgesAdded[i][0], result.EdgesAdded[j][0])
 		}
-		return result.EdgesAdded[i][1] < result.EdgesAdded[j][1]
+		return a.lessID(result.EdgesAdded[i][1], result.EdgesAdded[j][1])
 	})
 	sort.Slice(result.WeightChanges, func(i, j int) bool {
 		if result.WeightChanges[i].From != result.WeightChanges[j].From {
-			return result.WeightChanges[i].From < result.WeightChanges[j].From
+			return a.lessID(result.WeightChanges[i].From, result.WeightChanges[j].From)
 		}
-		return result.WeightChanges[i].To < result.WeightChanges[j].To
+		return a.lessID(result.WeightChanges[i].To, result.WeightChanges[j].To)
 	})
 
 	for key := range aEdges {
@@ -430,9 +636,9 @@ func Diff[N, E any](a, b *Graph[N, E]) (*DiffResult, error) {
 	}
 	sort.Slice(result.EdgesRemoved, func(i, j int) bool {
 		if result.EdgesRemoved[i][0] != result.EdgesRemoved[j][0] {
-			return result.EdgesRemoved[i][0] < result.EdgesRemoved[j][0]
+			return a.lessID(result.EdgesRemoved[i][0], result.EdgesRemoved[j][0])
 		}
-		return result.EdgesRemoved[i][1] < result.EdgesRemoved[j][1]
+		return a.lessID(result.EdgesRemoved[i][1], result.EdgesRemoved[j][1])
 	})
 
 	return result, nil
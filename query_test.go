@@ -3,6 +3,7 @@ package spine
 import (
 	"math"
 	"sort"
+	"strings"
 	"testing"
 )
 
@@ -110,6 +111,121 @@ func TestLeaves(t *testing.T) {
 	}
 }
 
+func TestPruneUnreachable(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"a", "b", "c", "x", "y"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", 0, 0)
+	g.AddEdge("b", "c", 0, 0)
+	// Subtree x -> y was detached from a during an edit.
+	g.AddEdge("x", "y", 0, 0)
+
+	removed := PruneUnreachable(g, []string{"a"})
+	if len(removed) != 2 || removed[0] != "x" || removed[1] != "y" {
+		t.Fatalf("expected [x y] removed, got %v", removed)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if !g.HasNode(id) {
+			t.Errorf("expected reachable node %s to remain", id)
+		}
+	}
+	if g.HasNode("x") || g.HasNode("y") || g.Size() != 2 {
+		t.Errorf("unexpected graph after prune: order=%d size=%d", g.Order(), g.Size())
+	}
+}
+
+func TestNormalizeWeights(t *testing.T) {
+	build := func() *Graph[string, int] {
+		g := NewGraph[string, int](false)
+		for _, id := range []string{"a", "b", "c", "d"} {
+			g.AddNode(id, id)
+		}
+		g.AddEdge("a", "b", 0, 2)
+		g.AddEdge("b", "c", 0, 4)
+		g.AddEdge("c", "d", 0, 10)
+		return g
+	}
+
+	g := build()
+	if err := NormalizeWeights(g, "minmax"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		from, to string
+		want     float64
+	}{{"a", "b", 0}, {"b", "c", 0.25}, {"c", "d", 1}, {"d", "c", 1}, {"b", "a", 0}} {
+		e, _ := g.GetEdge(tc.from, tc.to)
+		if e.Weight != tc.want {
+			t.Errorf("minmax %s->%s: expected %v, got %v", tc.from, tc.to, tc.want, e.Weight)
+		}
+		in := g.InEdges(tc.to)
+		for _, ie := range in {
+			if ie.From == tc.from && ie.Weight != tc.want {
+				t.Errorf("minmax in-edge %s->%s: expected %v, got %v", tc.from, tc.to, tc.want, ie.Weight)
+			}
+		}
+	}
+
+	g = build()
+	var ops []HistoryOp[string, int]
+	g.OnChange(func(op HistoryOp[string, int]) { ops = append(ops, op) })
+	if err := NormalizeWeights(g, "sum"); err != nil {
+		t.Fatal(err)
+	}
+	total := 0.0
+	for _, e := range g.Edges() {
+		total += e.Weight
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("sum: expected weights to total 1, got %v", total)
+	}
+	if e, _ := g.GetEdge("c", "b"); e.Weight != 0.25 {
+		t.Errorf("sum: expected reverse edge weight 0.25, got %v", e.Weight)
+	}
+	if len(ops) != 3 {
+		t.Errorf("expected one recorded change per edge, got %+v", ops)
+	}
+	for _, op := range ops {
+		if e, _ := g.GetEdge(op.From, op.To); op.Kind != OpAddEdge || op.Weight != e.Weight {
+			t.Errorf("expected the new weight of %s->%s to be recorded, got %+v", op.From, op.To, op)
+		}
+	}
+
+	if err := NormalizeWeights(build(), "zscore"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+func TestPruneUnreachableDefaultRoots(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"a", "b", "x", "y"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", 0, 0)
+	// x and y form a cycle with no root, so nothing can reach them.
+	g.AddEdge("x", "y", 0, 0)
+	g.AddEdge("y", "x", 0, 0)
+
+	removed := PruneUnreachable(g, nil)
+	if len(removed) != 2 || removed[0] != "x" || removed[1] != "y" {
+		t.Fatalf("expected [x y] removed, got %v", removed)
+	}
+	if g.Order() != 2 {
+		t.Errorf("expected 2 nodes to remain, got %d", g.Order())
+	}
+
+	// With no root at all, nothing is removed.
+	g.RemoveNode("a")
+	g.AddEdge("b", "b", 0, 0)
+	if removed := PruneUnreachable(g, nil); removed != nil || g.Order() != 1 {
+		t.Errorf("expected no removals without roots, got %v", removed)
+	}
+	if removed := PruneUnreachable(g, []string{"missing"}); removed != nil || g.Order() != 1 {
+		t.Errorf("expected no removals for missing roots, got %v", removed)
+	}
+}
+
 func TestRootsAndLeavesEmpty(t *testing.T) {
 	g := NewGraph[int, int](true)
 	if roots := Roots(g); len(roots) != 0 {
@@ -132,6 +248,172 @@ func TestAncestorsOfRoot(t *testing.T) {
 	}
 }
 
+func TestAncestorIndexMatchesAncestors(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", 0, 0)
+	g.AddEdge("b", "c", 0, 0)
+	g.AddEdge("a", "d", 0, 0)
+	g.AddEdge("d", "c", 0, 0)
+	g.AddEdge("c", "e", 0, 0)
+	g.AddEdge("e", "b", 0, 0) // cycle b -> c -> e -> b
+
+	ancestors := AncestorIndex(g)
+	for _, n := range g.Nodes() {
+		want := Ancestors(g, n.ID)
+		got := ancestors(n.ID)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: expected %v, got %v", n.ID, want, got)
+		}
+	}
+}
+
+func TestAncestorIndexCustomOrder(t *testing.T) {
+	g := NewGraph[string, int](true)
+	g.SetIDLess(NaturalIDLess)
+	for _, id := range []string{"v2", "v10", "x"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("v2", "x", 0, 0)
+	g.AddEdge("v10", "x", 0, 0)
+
+	if got, want := AncestorIndex(g)("x"), Ancestors(g, "x"); strings.Join(got, ",") != strings.Join(want, ",") || want[0] != "v2" {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestToUndirectedKeepsIDOrder(t *testing.T) {
+	g := NewGraph[string, string](true)
+	g.SetIDLess(NaturalIDLess)
+	for _, id := range []string{"n10", "n2", "n1"} {
+		g.AddNode(id, id)
+	}
+	u, _ := ToUndirected(g, nil)
+	var ids []string
+	for _, n := range u.Nodes() {
+		ids = append(ids, n.ID)
+	}
+	if got := strings.Join(ids, ","); got != "n1,n2,n10" {
+		t.Errorf("expected the natural ID order carried over, got %s", got)
+	}
+}
+
+func TestToUndirectedConflicts(t *testing.T) {
+	g := NewGraph[string, string](true)
+	for _, id := range []string{"a", "b", "c"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", "ab", 1)
+	g.AddEdge("b", "a", "ba", 2)
+	g.AddEdge("b", "c", "bc", 3)
+	g.AddEdge("c", "b", "bc", 3) // identical: merged silently
+	g.EdgeMeta("a", "b").Set("owner", "fwd")
+	g.EdgeMeta("b", "a").Set("owner", "back")
+	g.EdgeMeta("b", "a").Set("note", "kept")
+
+	maxWeight := func(fwd, back Edge[string]) Edge[string] {
+		if back.Weight > fwd.Weight {
+			return back
+		}
+		return fwd
+	}
+	u, conflicts := ToUndirected(g, maxWeight)
+	if u.Directed {
+		t.Fatal("expected undirected result")
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %+v", conflicts)
+	}
+	c := conflicts[0]
+	if c.From != "a" || c.To != "b" || c.Forward.Weight != 1 || c.Backward.Weight != 2 {
+		t.Errorf("unexpected conflict: %+v", c)
+	}
+	if c.Merged.Weight != 2 || c.Merged.Data != "ba" || c.Merged.From != "a" {
+		t.Errorf("expected merged edge from merge func, got %+v", c.Merged)
+	}
+
+	if u.Size() != 2 {
+		t.Errorf("expected 2 undirected edges, got size %d", u.Size())
+	}
+	if e, _ := u.GetEdge("b", "a"); e.Weight != 2 || e.Data != "ba" {
+		t.Errorf("expected resolved edge weight 2, got %+v", e)
+	}
+	if v, _ := u.EdgeMeta("a", "b").Get("owner"); v != "fwd" {
+		t.Errorf("expected forward metadata to win, got %v", v)
+	}
+	if v, _ := u.EdgeMeta("a", "b").Get("note"); v != "kept" {
+		t.Errorf("expected backward-only metadata kept, got %v", v)
+	}
+
+	// Without a merge func the forward (smaller From) edge is kept.
+	u, conflicts = ToUndirected(g, nil)
+	if e, _ := u.GetEdge("a", "b"); len(conflicts) != 1 || e.Weight != 1 {
+		t.Errorf("expected default to keep a->b, got %+v", e)
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	g := NewGraph[string, int](true)
+	g.AddNode("a", "A")
+	g.AddNode("b", "B")
+	g.AddEdge("a", "b", 7, 2)
+
+	tr := Transpose(g)
+	if tr.HasEdge("a", "b") || !tr.HasEdge("b", "a") {
+		t.Fatal("expected edge to be reversed")
+	}
+	e, _ := tr.GetEdge("b", "a")
+	if e.Data != 7 || e.Weight != 2 {
+		t.Errorf("expected edge data and weight preserved, got %+v", e)
+	}
+}
+
+func TestCommonDescendantsAndAncestors(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"root", "a", "b", "x", "y", "shared", "leaf"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("root", "a", 0, 0)
+	g.AddEdge("root", "b", 0, 0)
+	g.AddEdge("a", "x", 0, 0)
+	g.AddEdge("b", "y", 0, 0)
+	g.AddEdge("a", "shared", 0, 0)
+	g.AddEdge("b", "shared", 0, 0)
+	g.AddEdge("shared", "leaf", 0, 0)
+
+	if got := CommonDescendants(g, "a", "b"); strings.Join(got, ",") != "leaf,shared" {
+		t.Errorf("expected [leaf shared], got %v", got)
+	}
+	if got := CommonAncestors(g, "x", "shared"); strings.Join(got, ",") != "a,root" {
+		t.Errorf("expected [a root], got %v", got)
+	}
+	if got := CommonDescendants(g, "x", "y"); got == nil || len(got) != 0 {
+		t.Errorf("expected empty non-nil result, got %v", got)
+	}
+}
+
+func TestCommonDescendantsCustomOrder(t *testing.T) {
+	g := NewGraph[string, int](true)
+	g.SetIDLess(NaturalIDLess)
+	for _, id := range []string{"a", "b", "v2", "v10"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "v2", 0, 0)
+	g.AddEdge("a", "v10", 0, 0)
+	g.AddEdge("b", "v10", 0, 0)
+	g.AddEdge("b", "v2", 0, 0)
+
+	if got := CommonDescendants(g, "a", "b"); strings.Join(got, ",") != "v2,v10" {
+		t.Errorf("expected [v2 v10], got %v", got)
+	}
+	g.RemoveEdge("b", "v2")
+	if got := CommonDescendants(g, "a", "b"); strings.Join(got, ",") != "v10" {
+		t.Errorf("expected [v10], got %v", got)
+	}
+}
+
 func TestDescendantsOfLeaf(t *testing.T) {
 	g := NewGraph[string, int](true)
 	g.AddNode("a", "A")
@@ -1,6 +1,8 @@
 package spine
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -12,6 +14,7 @@ type Snapshot[N, E any] struct {
 	Directed bool             `json:"directed"`
 	Graph    *GraphData[N, E] `json:"graph,omitempty"`
 	Meta     *MetaData        `json:"metadata,omitempty"`
+	Config   map[string]any   `json:"config,omitempty"`
 }
 
 // GraphData holds the graph topology (nodes + edges).
@@ -34,10 +37,12 @@ type EdgeData[E any] struct {
 	Weight float64 `json:"weight"`
 }
 
-// MetaData holds all metadata for nodes and edges.
+// MetaData holds all metadata for nodes and edges. DirectedEdges holds the
+// per-direction stores of undirected graphs (see Graph.DirectedEdgeMeta).
 type MetaData struct {
-	Nodes []NodeMetaData `json:"nodes"`
-	Edges []EdgeMetaData `json:"edges"`
+	Nodes         []NodeMetaData `json:"nodes"`
+	Edges         []EdgeMetaData `json:"edges"`
+	DirectedEdges []EdgeMetaData `json:"directed_edges,omitempty"`
 }
 
 // NodeMetaData is the serialized metadata for a single node.
@@ -65,6 +70,8 @@ type MarshalOptions struct {
 }
 
 // Marshal serializes a graph to JSON. If opts is nil, everything is included with pretty-printing.
+// The whole Snapshot is built in memory first; use MarshalTo to stream very
+// large graphs instead.
 func Marshal[N, E any](g *Graph[N, E], opts *MarshalOptions) ([]byte, error) {
 	if opts == nil {
 		opts = &MarshalOptions{Graph: true, Meta: true, Schemas: true, Indent: true}
@@ -80,12 +87,18 @@ func Marshal[N, E any](g *Graph[N, E], opts *MarshalOptions) ([]byte, error) {
 		Directed: target.Directed,
 	}
 
+	nodes := target.Nodes()
+	if target.idLess != nil {
+		// Snapshots stay in lexical order whatever the graph's ID order.
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	}
+
 	if opts.Graph {
 		gd := &GraphData[N, E]{
 			Nodes: make([]NodeData[N], 0),
 			Edges: make([]EdgeData[E], 0),
 		}
-		for _, n := range target.Nodes() {
+		for _, n := range nodes {
 			gd.Nodes = append(gd.Nodes, NodeData[N]{ID: n.ID, Data: n.Data})
 		}
 		edges := target.Edges()
@@ -115,18 +128,15 @@ func Marshal[N, E any](g *Graph[N, E], opts *MarshalOptions) ([]byte, error) {
 			Edges: make([]EdgeMetaData, 0),
 		}
 
-		// Node metadata — iterate Nodes() which returns sorted by ID.
-		for _, n := range target.Nodes() {
+		// Node metadata — iterate nodes, which are sorted by ID.
+		for _, n := range nodes {
 			store, ok := target.nodeMeta[n.ID]
 			if !ok || store.Len() == 0 {
 				continue
 			}
 			nm := NodeMetaData{
 				ID:      n.ID,
-				Entries: make(map[string]any, store.Len()),
-			}
-			for k, v := range store.entries {
-				nm.Entries[k] = v
+				Entries: marshalEntries(store),
 			}
 			if opts.Schemas {
 				if schema := store.GetSchema(); schema != nil {
@@ -136,51 +146,147 @@ func Marshal[N, E any](g *Graph[N, E], opts *MarshalOptions) ([]byte, error) {
 			md.Nodes = append(md.Nodes, nm)
 		}
 
-		// Edge metadata — collect and sort by (from, to).
-		type edgeKey struct{ from, to string }
-		var keys []edgeKey
-		for from, m := range target.edgeMeta {
-			for to, store := range m {
-				if store.Len() > 0 {
-					keys = append(keys, edgeKey{from, to})
-				}
+		md.Edges = append(md.Edges, edgeMetaData(target.edgeMeta, opts.Schemas)...)
+		md.DirectedEdges = edgeMetaData(target.dirEdgeMeta, opts.Schemas)
+
+		snap.Meta = md
+	}
+
+	// Graph-level config always comes from the full graph, even for subsets.
+	if g.graphMeta != nil && g.graphMeta.Len() > 0 {
+		snap.Config = make(map[string]any, g.graphMeta.Len())
+		for k, v := range g.graphMeta.live() {
+			snap.Config[k] = v
+		}
+	}
+
+	if opts.Indent {
+		return json.MarshalIndent(snap, "", "  ")
+	}
+	return json.Marshal(snap)
+}
+
+// edgeMetaData serializes the non-empty stores of a from -> to -> store map,
+// sorted by (from, to).
+func edgeMetaData(stores map[string]map[string]*Store, schemas bool) []EdgeMetaData {
+	type edgeKey struct{ from, to string }
+	var keys []edgeKey
+	for from, m := range stores {
+		for to, store := range m {
+			if store.Len() > 0 {
+				keys = append(keys, edgeKey{from, to})
 			}
 		}
-		sort.Slice(keys, func(i, j int) bool {
-			if keys[i].from != keys[j].from {
-				return keys[i].from < keys[j].from
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].from != keys[j].from {
+			return keys[i].from < keys[j].from
+		}
+		return keys[i].to < keys[j].to
+	})
+	var out []EdgeMetaData
+	for _, k := range keys {
+		store := stores[k.from][k.to]
+		em := EdgeMetaData{
+			From:    k.from,
+			To:      k.to,
+			Entries: marshalEntries(store),
+		}
+		if schemas {
+			if schema := store.GetSchema(); schema != nil {
+				em.Schema = schema
 			}
-			return keys[i].to < keys[j].to
-		})
-		for _, k := range keys {
-			store := target.edgeMeta[k.from][k.to]
-			em := EdgeMetaData{
-				From:    k.from,
-				To:      k.to,
-				Entries: make(map[string]any, store.Len()),
+		}
+		out = append(out, em)
+	}
+	return out
+}
+
+// marshalEntries copies a store's entries for serialization. Integral float
+// values under a FieldInt schema field are written as integers, so Unmarshal
+// can restore them as int.
+func marshalEntries(store *Store) map[string]any {
+	entries := make(map[string]any, store.Len())
+	for k, v := range store.live() {
+		if def, ok := store.schema[k]; ok && def.Type == FieldInt {
+			if n, ok := coerceValue(v, FieldInt); ok {
+				v = n
 			}
-			for key, val := range store.entries {
-				em.Entries[key] = val
+		}
+		entries[k] = v
+	}
+	return entries
+}
+
+// TreeNode is a node in the nested form produced by MarshalTree.
+type TreeNode struct {
+	ID       string      `json:"id"`
+	Label    string      `json:"label"`
+	Children []*TreeNode `json:"children,omitempty"`
+}
+
+// MarshalTree serializes the part of g reachable from root as a nested JSON
+// tree, following out-edges with children sorted by ID. label supplies each
+// node's display label; if nil, the node ID is used. In a DAG, a node with
+// several parents appears under each of them. On an undirected graph the edge
+// back to a node's parent is not followed, so any tree can be serialized from
+// any of its nodes. An error is returned if root does not exist or a cycle is
+// reachable from it.
+func MarshalTree[N, E any](g *Graph[N, E], root string, label func(Node[N]) string) ([]byte, error) {
+	if !g.HasNode(root) {
+		return nil, fmt.Errorf("root node %q not found", root)
+	}
+	onPath := make(map[string]bool)
+	var build func(id, parent string) (*TreeNode, error)
+	build = func(id, parent string) (*TreeNode, error) {
+		if onPath[id] {
+			return nil, fmt.Errorf("cycle detected at node %q", id)
+		}
+		onPath[id] = true
+		defer delete(onPath, id)
+
+		n, _ := g.GetNode(id)
+		tn := &TreeNode{ID: id, Label: id}
+		if label != nil {
+			tn.Label = label(n)
+		}
+		for _, e := range g.OutEdges(id) {
+			if !g.Directed && e.To == parent && id != root {
+				continue
 			}
-			if opts.Schemas {
-				if schema := store.GetSchema(); schema != nil {
-					em.Schema = schema
-				}
+			child, err := build(e.To, id)
+			if err != nil {
+				return nil, err
 			}
-			md.Edges = append(md.Edges, em)
+			tn.Children = append(tn.Children, child)
 		}
-
-		snap.Meta = md
+		return tn, nil
+	}
+	tree, err := build(root, "")
+	if err != nil {
+		return nil, err
 	}
+	return json.Marshal(tree)
+}
 
-	if opts.Indent {
-		return json.MarshalIndent(snap, "", "  ")
+// Fingerprint returns a hex-encoded SHA-256 hash of the graph's canonical
+// serialized form (topology, metadata, schemas and config). Graphs with the
+// same content always produce the same fingerprint, so it can be used as a
+// cache key or to detect changes. It returns "" if the graph cannot be
+// serialized.
+func Fingerprint[N, E any](g *Graph[N, E]) string {
+	data, err := Marshal(g, &MarshalOptions{Graph: true, Meta: true, Schemas: true})
+	if err != nil {
+		return ""
 	}
-	return json.Marshal(snap)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
 }
 
 // Unmarshal deserializes JSON into a new graph. Both graph topology and metadata
-// sections are applied when present.
+// sections are applied when present. Metadata values under a schema are
+// coerced to their field types (see Store.CoerceToSchema), so a FieldInt value
+// comes back as int rather than the float64 that JSON numbers decode to.
 func Unmarshal[N, E any](data []byte) (*Graph[N, E], error) {
 	var snap Snapshot[N, E]
 	if err := json.Unmarshal(data, &snap); err != nil {
@@ -204,29 +310,13 @@ func Unmarshal[N, E any](data []byte) (*Graph[N, E], error) {
 	}
 
 	if snap.Meta != nil {
-		for _, nm := range snap.Meta.Nodes {
-			if !g.HasNode(nm.ID) {
-				continue
-			}
-			store := g.NodeMeta(nm.ID)
-			for k, v := range nm.Entries {
-				store.Set(k, v)
-			}
-			if nm.Schema != nil {
-				store.SetSchema(nm.Schema)
-			}
-		}
-		for _, em := range snap.Meta.Edges {
-			if !g.HasEdge(em.From, em.To) {
-				continue
-			}
-			store := g.EdgeMeta(em.From, em.To)
-			for k, v := range em.Entries {
-				store.Set(k, v)
-			}
-			if em.Schema != nil {
-				store.SetSchema(em.Schema)
-			}
+		applyMetaData(g, snap.Meta)
+	}
+
+	if len(snap.Config) > 0 {
+		store := g.GraphMeta()
+		for k, v := range snap.Config {
+			store.Set(k, v)
 		}
 	}
 
@@ -261,31 +351,40 @@ func ApplyMeta[N, E any](data []byte, g *Graph[N, E]) error {
 	if raw.Meta == nil {
 		return nil
 	}
+	applyMetaData(g, raw.Meta)
+	return nil
+}
 
-	for _, nm := range raw.Meta.Nodes {
-		if !g.HasNode(nm.ID) {
-			continue
+// applyMetaData copies a serialized metadata section into g, skipping nodes
+// and edges g does not have. JSON decodes every number as float64, so
+// integral values of FieldInt fields are turned back into ints; everything
+// else is loaded as written.
+func applyMetaData[N, E any](g *Graph[N, E], md *MetaData) {
+	apply := func(store *Store, entries map[string]any, schema Schema) {
+		if store == nil {
+			return
 		}
-		store := g.NodeMeta(nm.ID)
-		for k, v := range nm.Entries {
+		for k, v := range entries {
 			store.Set(k, v)
 		}
-		if nm.Schema != nil {
-			store.SetSchema(nm.Schema)
+		if schema != nil {
+			store.SetSchema(schema)
+			for k, def := range schema {
+				if f, ok := entries[k].(float64); ok && def.Type == FieldInt {
+					if n, ok := coerceValue(f, FieldInt); ok {
+						store.Set(k, n)
+					}
+				}
+			}
 		}
 	}
-	for _, em := range raw.Meta.Edges {
-		if !g.HasEdge(em.From, em.To) {
-			continue
-		}
-		store := g.EdgeMeta(em.From, em.To)
-		for k, v := range em.Entries {
-			store.Set(k, v)
-		}
-		if em.Schema != nil {
-			store.SetSchema(em.Schema)
-		}
+	for _, nm := range md.Nodes {
+		apply(g.NodeMeta(nm.ID), nm.Entries, nm.Schema)
+	}
+	for _, em := range md.Edges {
+		apply(g.EdgeMeta(em.From, em.To), em.Entries, em.Schema)
+	}
+	for _, em := range md.DirectedEdges {
+		apply(g.DirectedEdgeMeta(em.From, em.To), em.Entries, em.Schema)
 	}
-
-	return nil
 }
@@ -3,6 +3,7 @@ package spine
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -254,6 +255,32 @@ func TestMarshalUndirected(t *testing.T) {
 	}
 }
 
+func TestConfigRoundTrip(t *testing.T) {
+	g := NewGraph[string, string](true)
+	g.AddNode("a", "A")
+	g.GraphMeta().Set("layout", "horizontal")
+
+	data, err := Marshal(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"config"`) {
+		t.Fatalf("expected config section, got %s", data)
+	}
+	g2, err := Unmarshal[string, string](data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := g2.GraphMeta().Get("layout"); v != "horizontal" {
+		t.Errorf("expected layout config to round trip, got %v", v)
+	}
+
+	empty, _ := Marshal(NewGraph[string, string](true), nil)
+	if strings.Contains(string(empty), `"config"`) {
+		t.Error("expected config omitted when empty")
+	}
+}
+
 func TestUnmarshalRoundTrip(t *testing.T) {
 	g := NewGraph[string, string](true)
 	g.AddNode("a", "alpha")
@@ -444,3 +471,195 @@ func TestMarshalDeterministic(t *testing.T) {
 		t.Fatalf("non-deterministic output:\n%s\nvs\n%s", d1, d2)
 	}
 }
+
+func TestFingerprint(t *testing.T) {
+	build := func(order []string) *Graph[string, string] {
+		g := NewGraph[string, string](true)
+		for _, id := range order {
+			g.AddNode(id, id)
+		}
+		g.AddEdge("a", "b", "", 1)
+		g.AddEdge("b", "c", "", 2)
+		g.NodeMeta("a").Set("lang", "go")
+		return g
+	}
+
+	g1 := build([]string{"a", "b", "c"})
+	g2 := build([]string{"c", "b", "a"})
+	fp1, fp2 := Fingerprint(g1), Fingerprint(g2)
+	if fp1 == "" || fp1 != fp2 {
+		t.Fatalf("expected identical fingerprints, got %q and %q", fp1, fp2)
+	}
+	if len(fp1) != 64 {
+		t.Errorf("expected 64 hex chars, got %d", len(fp1))
+	}
+
+	g2.AddEdge("a", "c", "", 1)
+	if Fingerprint(g2) == fp1 {
+		t.Error("expected fingerprint to change after adding an edge")
+	}
+
+	g1.NodeMeta("a").Set("lang", "rust")
+	if Fingerprint(g1) == fp1 {
+		t.Error("expected fingerprint to change after a metadata update")
+	}
+}
+
+func TestMarshalTree(t *testing.T) {
+	g := NewGraph[string, string](true)
+	for id, label := range map[string]string{
+		"ceo": "CEO", "cto": "CTO", "cfo": "CFO", "eng1": "Eng Lead", "fin1": "Controller",
+	} {
+		g.AddNode(id, label)
+	}
+	g.AddEdge("ceo", "cto", "", 1)
+	g.AddEdge("ceo", "cfo", "", 1)
+	g.AddEdge("cto", "eng1", "", 1)
+	g.AddEdge("cfo", "fin1", "", 1)
+
+	data, err := MarshalTree(g, "ceo", func(n Node[string]) string { return n.Data })
+	if err != nil {
+		t.Fatalf("marshal tree: %v", err)
+	}
+	var root TreeNode
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatalf("parse JSON: %v", err)
+	}
+	if root.ID != "ceo" || root.Label != "CEO" {
+		t.Fatalf("expected ceo root, got %+v", root)
+	}
+	if len(root.Children) != 2 || root.Children[0].ID != "cfo" || root.Children[1].ID != "cto" {
+		t.Fatalf("expected children [cfo cto], got %+v", root.Children)
+	}
+	cto := root.Children[1]
+	if len(cto.Children) != 1 || cto.Children[0].ID != "eng1" || cto.Children[0].Label != "Eng Lead" {
+		t.Errorf("expected eng1 under cto, got %+v", cto.Children)
+	}
+	if len(cto.Children[0].Children) != 0 {
+		t.Errorf("expected eng1 to be a leaf")
+	}
+}
+
+func TestMarshalTreeErrors(t *testing.T) {
+	g := NewGraph[string, string](true)
+	g.AddNode("a", "")
+	g.AddNode("b", "")
+	g.AddEdge("a", "b", "", 1)
+	g.AddEdge("b", "a", "", 1)
+
+	if _, err := MarshalTree(g, "a", nil); err == nil {
+		t.Error("expected cycle error")
+	}
+	if _, err := MarshalTree(g, "missing", nil); err == nil {
+		t.Error("expected missing root error")
+	}
+
+	u := NewGraph[string, string](false)
+	for _, id := range []string{"a", "b", "c"} {
+		u.AddNode(id, "")
+	}
+	u.AddEdge("a", "b", "", 1)
+	u.AddEdge("b", "c", "", 1)
+	u.AddEdge("c", "a", "", 1)
+	if _, err := MarshalTree(u, "a", nil); err == nil {
+		t.Error("expected cycle error on an undirected cycle")
+	}
+}
+
+func TestMarshalTreeUndirected(t *testing.T) {
+	g := NewGraph[string, string](false)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(id, "")
+	}
+	g.AddEdge("a", "b", "", 1)
+	g.AddEdge("b", "c", "", 1)
+	g.AddEdge("b", "d", "", 1)
+
+	data, err := MarshalTree(g, "b", nil)
+	if err != nil {
+		t.Fatalf("marshal tree: %v", err)
+	}
+	want := `{"id":"b","label":"b","children":[{"id":"a","label":"a"},{"id":"c","label":"c"},{"id":"d","label":"d"}]}`
+	if string(data) != want {
+		t.Errorf("got %s\nwant %s", data, want)
+	}
+	data, err = MarshalTree(g, "c", nil)
+	if err != nil {
+		t.Fatalf("marshal tree from a leaf: %v", err)
+	}
+	want = `{"id":"c","label":"c","children":[{"id":"b","label":"b","children":[{"id":"a","label":"a"},{"id":"d","label":"d"}]}]}`
+	if string(data) != want {
+		t.Errorf("got %s\nwant %s", data, want)
+	}
+}
+
+func TestMarshalSchemaTypesRoundTrip(t *testing.T) {
+	g := NewGraph[string, string](true)
+	g.AddNode("a", "A")
+	g.AddNode("b", "B")
+	g.AddEdge("a", "b", "", 1)
+	schema := Schema{
+		"count": {Type: FieldInt},
+		"ratio": {Type: FieldFloat},
+		"ok":    {Type: FieldBool},
+	}
+	store := g.NodeMeta("a")
+	store.SetSchema(schema)
+	store.Set("count", 42)
+	store.Set("ratio", 2.0)
+	store.Set("ok", true)
+	edge := g.EdgeMeta("a", "b")
+	edge.SetSchema(schema)
+	edge.Set("count", float64(7)) // integral float under an int field
+
+	data, err := Marshal(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"count": 7`) {
+		t.Errorf("expected edge count written as an integer:\n%s", data)
+	}
+	g2, err := Unmarshal[string, string](data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{"count": 42, "ratio": 2.0, "ok": true}
+	for k, w := range want {
+		v, _ := g2.NodeMeta("a").Get(k)
+		if v != w {
+			t.Errorf("%s: expected %T %v, got %T %v", k, w, w, v, v)
+		}
+	}
+	if v, _ := g2.EdgeMeta("a", "b").Get("count"); v != 7 {
+		t.Errorf("edge count: expected int 7, got %T %v", v, v)
+	}
+	if errs := g2.NodeMeta("a").Validate(); errs != nil {
+		t.Errorf("expected round-tripped store to validate, got %v", errs)
+	}
+}
+
+func TestUnmarshalKeepsMismatchedValues(t *testing.T) {
+	g := NewGraph[string, string](true)
+	g.AddNode("a", "A")
+	store := g.NodeMeta("a")
+	store.SetSchema(Schema{"name": {Type: FieldString}, "count": {Type: FieldInt}})
+	store.Set("name", 5)
+	store.Set("count", 2.5)
+
+	data, err := Marshal(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2, err := Unmarshal[string, string](data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only integral numbers under int fields are converted on load.
+	if v, _ := g2.NodeMeta("a").Get("name"); v != 5.0 {
+		t.Errorf("name: expected float64 5 as written, got %T %v", v, v)
+	}
+	if v, _ := g2.NodeMeta("a").Get("count"); v != 2.5 {
+		t.Errorf("count: expected 2.5 as written, got %T %v", v, v)
+	}
+}
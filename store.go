@@ -2,15 +2,27 @@ package spine
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Store is a standalone key-value metadata store with pagination and schema validation.
 type Store struct {
-	entries map[string]any
-	schema  Schema
+	entries  map[string]any
+	schema   Schema
+	expires  map[string]time.Time                      // keys set with SetWithTTL
+	versions map[string][]Version                      // non-nil while TrackVersions is on
+	strict   bool                                      // reject writes that violate the schema
+	onChange func(key string, value any, deleted bool) // set by a graph recording history
 }
 
+// timeNow is the clock used for TTL expiry; tests replace it.
+var timeNow = time.Now
+
 // Entry represents a single key-value pair in a Store.
 type Entry struct {
 	Key   string
@@ -40,10 +52,15 @@ const (
 	FieldAny   FieldType = "any"
 )
 
-// FieldDef defines the type and requirement for a schema field.
+// FieldDef defines the type and requirement for a schema field. Enum, if
+// non-empty, lists the allowed values; Min and Max, if set, bound numeric
+// values inclusively.
 type FieldDef struct {
 	Type     FieldType `json:"type"`
 	Required bool      `json:"required"`
+	Enum     []any     `json:"enum,omitempty"`
+	Min      *float64  `json:"min,omitempty"`
+	Max      *float64  `json:"max,omitempty"`
 }
 
 // Schema maps field names to their definitions for store validation.
@@ -54,42 +71,142 @@ func NewStore() *Store {
 	return &Store{entries: make(map[string]any)}
 }
 
-// Set adds or updates a key-value pair.
-func (s *Store) Set(key string, value any) {
+// Set adds or updates a key-value pair. It clears any TTL set on the key.
+// In strict mode (see SetStrict) a value that violates its schema field is
+// rejected with an error and the store is left unchanged; otherwise Set
+// always returns nil.
+func (s *Store) Set(key string, value any) error {
+	if s.strict {
+		if def, ok := s.schema[key]; ok {
+			if err := checkField(key, def, value); err != nil {
+				return err
+			}
+		}
+	}
 	s.entries[key] = value
+	delete(s.expires, key)
+	s.recordVersion(key, value, false)
+	if s.onChange != nil {
+		s.onChange(key, value, false)
+	}
+	return nil
+}
+
+// SetWithTTL sets a key-value pair that expires after ttl. Expired entries
+// are hidden from reads at once but stay in the store until DeleteExpired
+// removes them; reads never modify the store, so they remain safe under a
+// read lock. A ttl <= 0 sets the key without expiry, like Set. Returns the
+// error of Set if the value is rejected.
+//
+// Expiry is not persisted: serialized graphs omit expired entries and write
+// live ones as plain values.
+func (s *Store) SetWithTTL(key string, value any, ttl time.Duration) error {
+	if err := s.Set(key, value); err != nil || ttl <= 0 {
+		return err
+	}
+	if s.expires == nil {
+		s.expires = make(map[string]time.Time)
+	}
+	s.expires[key] = timeNow().Add(ttl)
+	return nil
+}
+
+// ExpiresAt returns when key expires, or false if it has no TTL.
+func (s *Store) ExpiresAt(key string) (time.Time, bool) {
+	t, ok := s.expires[key]
+	return t, ok
+}
+
+// expired reports whether key has a TTL that has passed.
+func (s *Store) expired(key string, now time.Time) bool {
+	t, ok := s.expires[key]
+	return ok && !now.Before(t)
+}
+
+// Expired returns the keys whose TTL has passed but that have not been
+// removed yet, in sorted order.
+func (s *Store) Expired() []string {
+	var keys []string
+	now := timeNow()
+	for k := range s.expires {
+		if s.expired(k, now) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DeleteExpired removes every expired entry and returns how many were
+// removed. Call it periodically, e.g. from a ticker under SyncGraph.Write, for
+// background cleanup.
+func (s *Store) DeleteExpired() int {
+	keys := s.Expired()
+	for _, k := range keys {
+		s.Delete(k)
+	}
+	return len(keys)
+}
+
+// live returns the entries that have not expired. It returns the entries map
+// itself when nothing has expired, so callers must not modify the result.
+func (s *Store) live() map[string]any {
+	if len(s.Expired()) == 0 {
+		return s.entries
+	}
+	now := timeNow()
+	m := make(map[string]any, len(s.entries))
+	for k, v := range s.entries {
+		if !s.expired(k, now) {
+			m[k] = v
+		}
+	}
+	return m
 }
 
 // Get returns the value for the given key and whether it exists.
 func (s *Store) Get(key string) (any, bool) {
 	v, ok := s.entries[key]
+	if ok && s.expired(key, timeNow()) {
+		return nil, false
+	}
 	return v, ok
 }
 
-// Delete removes a key. Returns true if the key existed.
+// Delete removes a key. Returns true if the key existed, including an expired
+// key that had not been removed yet.
 func (s *Store) Delete(key string) bool {
 	_, ok := s.entries[key]
 	if ok {
 		delete(s.entries, key)
+		delete(s.expires, key)
+		s.recordVersion(key, nil, true)
+		if s.onChange != nil {
+			s.onChange(key, nil, true)
+		}
 	}
 	return ok
 }
 
 // Has returns true if the key exists.
 func (s *Store) Has(key string) bool {
-	_, ok := s.entries[key]
+	_, ok := s.Get(key)
 	return ok
 }
 
 // Len returns the number of entries.
 func (s *Store) Len() int {
-	return len(s.entries)
+	return len(s.entries) - len(s.Expired())
 }
 
 // Keys returns all keys in sorted order.
 func (s *Store) Keys() []string {
 	keys := make([]string, 0, len(s.entries))
+	now := timeNow()
 	for k := range s.entries {
-		keys = append(keys, k)
+		if !s.expired(k, now) {
+			keys = append(keys, k)
+		}
 	}
 	sort.Strings(keys)
 	return keys
@@ -97,13 +214,32 @@ func (s *Store) Keys() []string {
 
 // Clear removes all entries.
 func (s *Store) Clear() {
+	for _, k := range s.Keys() {
+		s.recordVersion(k, nil, true)
+		if s.onChange != nil {
+			s.onChange(k, nil, true)
+		}
+	}
 	s.entries = make(map[string]any)
+	s.expires = nil
 }
 
 // List returns a paginated view of store entries sorted by key.
 // If limit <= 0, all entries from offset onward are returned.
 func (s *Store) List(offset, limit int) Page {
+	return s.page(s.Keys(), offset, limit)
+}
+
+// ListNatural is like List but orders keys naturally, comparing runs of
+// digits numerically so that "item2" sorts before "item10".
+func (s *Store) ListNatural(offset, limit int) Page {
 	keys := s.Keys()
+	sort.SliceStable(keys, func(i, j int) bool { return naturalLess(keys[i], keys[j]) })
+	return s.page(keys, offset, limit)
+}
+
+// page slices the ordered keys into a Page of entries.
+func (s *Store) page(keys []string, offset, limit int) Page {
 	total := len(keys)
 
 	if offset < 0 {
@@ -140,6 +276,46 @@ func (s *Store) List(offset, limit int) Page {
 	}
 }
 
+// naturalLess compares a and b chunk by chunk, treating runs of ASCII digits
+// as numbers. Equal numbers with more leading zeros sort first.
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		ad, bd := isDigit(a[0]), isDigit(b[0])
+		if ad && bd {
+			an, arest := splitDigits(a)
+			bn, brest := splitDigits(b)
+			at, bt := strings.TrimLeft(an, "0"), strings.TrimLeft(bn, "0")
+			if len(at) != len(bt) {
+				return len(at) < len(bt)
+			}
+			if at != bt {
+				return at < bt
+			}
+			if len(an) != len(bn) {
+				return len(an) > len(bn)
+			}
+			a, b = arest, brest
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// splitDigits splits s into its leading run of digits and the remainder.
+func splitDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
 // Range iterates over entries in sorted key order.
 // If fn returns false, iteration stops.
 func (s *Store) Range(fn func(key string, value any) bool) {
@@ -156,6 +332,21 @@ func (s *Store) SetSchema(schema Schema) {
 	s.schema = schema
 }
 
+// SetStrict turns strict mode on or off. In strict mode Set rejects values
+// that do not match their schema field's type, enum or range, so the store
+// cannot become invalid through writes. Keys without a schema field are
+// accepted as usual, and entries already in the store are not rechecked;
+// call Validate for that. Deletes are not checked, so a required field can
+// still be removed.
+func (s *Store) SetStrict(strict bool) {
+	s.strict = strict
+}
+
+// Strict reports whether strict mode is on.
+func (s *Store) Strict() bool {
+	return s.strict
+}
+
 // GetSchema returns the current schema, or nil if none is set.
 func (s *Store) GetSchema() Schema {
 	return s.schema
@@ -179,7 +370,7 @@ func (s *Store) Validate() []error {
 
 	for _, key := range keys {
 		def := s.schema[key]
-		val, exists := s.entries[key]
+		val, exists := s.Get(key)
 
 		if !exists {
 			if def.Required {
@@ -188,12 +379,8 @@ func (s *Store) Validate() []error {
 			continue
 		}
 
-		if def.Type == FieldAny {
-			continue
-		}
-
-		if !matchesType(val, def.Type) {
-			errs = append(errs, fmt.Errorf("field %q: expected type %s, got %T", key, def.Type, val))
+		if err := checkField(key, def, val); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
@@ -203,6 +390,72 @@ func (s *Store) Validate() []error {
 	return errs
 }
 
+// checkField reports whether val satisfies the type, enum and range of def.
+func checkField(key string, def FieldDef, val any) error {
+	if def.Type != FieldAny && !matchesType(val, def.Type) {
+		return fmt.Errorf("field %q: expected type %s, got %T", key, def.Type, val)
+	}
+	if len(def.Enum) > 0 && !slices.ContainsFunc(def.Enum, func(e any) bool { return sameValue(e, val) }) {
+		return fmt.Errorf("field %q: %v is not one of %v", key, val, def.Enum)
+	}
+	if def.Min == nil && def.Max == nil {
+		return nil
+	}
+	f, ok := numericValue(val)
+	if !ok {
+		return fmt.Errorf("field %q: expected a number, got %T", key, val)
+	}
+	if def.Min != nil && f < *def.Min {
+		return fmt.Errorf("field %q: %v is below the minimum %v", key, val, *def.Min)
+	}
+	if def.Max != nil && f > *def.Max {
+		return fmt.Errorf("field %q: %v is above the maximum %v", key, val, *def.Max)
+	}
+	return nil
+}
+
+// sameValue compares enum values, treating numbers of any type as equal when
+// their values are, since schemas loaded from JSON hold float64s.
+func sameValue(a, b any) bool {
+	fa, okA := numericValue(a)
+	fb, okB := numericValue(b)
+	if okA && okB {
+		return fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// numericValue returns v as a float64 if it is a Go number.
+func numericValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
 func matchesType(val any, ft FieldType) bool {
 	switch ft {
 	case FieldString:
@@ -244,12 +497,113 @@ func matchesType(val any, ft FieldType) bool {
 	return false
 }
 
+// CoerceToSchema converts entries whose value does not match their schema
+// type, e.g. the string "5" under a FieldInt field becomes int 5. Numeric
+// strings, integral floats, booleans and their string forms are converted
+// where the conversion is lossless. Values that cannot be converted are left
+// unchanged and reported as errors. Returns nil if no schema is set or every
+// mismatched value was converted.
+func (s *Store) CoerceToSchema() []error {
+	if s.schema == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(s.schema))
+	for k := range s.schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		def := s.schema[key]
+		val, exists := s.Get(key)
+		if !exists || matchesType(val, def.Type) {
+			continue
+		}
+		coerced, ok := coerceValue(val, def.Type)
+		if !ok {
+			errs = append(errs, fmt.Errorf("field %q: cannot coerce %T %v to %s", key, val, val, def.Type))
+			continue
+		}
+		if err := s.Set(key, coerced); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func coerceValue(val any, ft FieldType) (any, bool) {
+	switch ft {
+	case FieldString:
+		switch v := val.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool:
+			return fmt.Sprint(v), true
+		case []byte:
+			return string(v), true
+		}
+	case FieldInt:
+		switch v := val.(type) {
+		case string:
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			return n, err == nil
+		case float64:
+			if v == float64(int(v)) {
+				return int(v), true
+			}
+		case float32:
+			if v == float32(int(v)) {
+				return int(v), true
+			}
+		}
+	case FieldFloat:
+		switch v := val.(type) {
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			return f, err == nil
+		case int:
+			return float64(v), true
+		case int64:
+			return float64(v), true
+		case int32:
+			return float64(v), true
+		}
+	case FieldBool:
+		if v, ok := val.(string); ok {
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			return b, err == nil
+		}
+	case FieldBytes:
+		if v, ok := val.(string); ok {
+			return []byte(v), true
+		}
+	}
+	return nil, false
+}
+
 // Copy returns a structural copy of the store. Values are shallow-copied.
 func (s *Store) Copy() *Store {
 	c := NewStore()
+	c.strict = s.strict
 	for k, v := range s.entries {
 		c.entries[k] = v
 	}
+	for k, t := range s.expires {
+		if c.expires == nil {
+			c.expires = make(map[string]time.Time, len(s.expires))
+		}
+		c.expires[k] = t
+	}
+	if s.versions != nil {
+		c.versions = make(map[string][]Version, len(s.versions))
+		for k, vs := range s.versions {
+			c.versions[k] = append([]Version(nil), vs...)
+		}
+	}
 	if s.schema != nil {
 		sc := make(Schema, len(s.schema))
 		for k, v := range s.schema {
@@ -1,7 +1,10 @@
 package spine
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestStoreSetAndGet(t *testing.T) {
@@ -143,6 +146,50 @@ func TestStoreListAll(t *testing.T) {
 	}
 }
 
+func TestStoreListNatural(t *testing.T) {
+	s := NewStore()
+	for i := 1; i <= 12; i++ {
+		s.Set(fmt.Sprintf("item%d", i), i)
+	}
+
+	lexical := s.List(0, 0)
+	if lexical.Items[1].Key != "item10" {
+		t.Fatalf("expected lexical order to stay the default, got %s second", lexical.Items[1].Key)
+	}
+
+	p := s.ListNatural(0, 0)
+	for i, e := range p.Items {
+		if want := fmt.Sprintf("item%d", i+1); e.Key != want {
+			t.Fatalf("position %d: expected %s, got %s", i, want, e.Key)
+		}
+	}
+
+	page := s.ListNatural(1, 2)
+	if len(page.Items) != 2 || page.Items[0].Key != "item2" || page.Items[1].Key != "item3" || !page.HasMore {
+		t.Fatalf("unexpected natural page: %+v", page)
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"item2", "item10", true},
+		{"item10", "item2", false},
+		{"a1b2", "a1b10", true},
+		{"abc", "abd", true},
+		{"x", "x1", true},
+		{"v007", "v7", true},
+		{"same", "same", false},
+	}
+	for _, tt := range tests {
+		if got := naturalLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("naturalLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
 func TestStoreRange(t *testing.T) {
 	s := NewStore()
 	s.Set("a", 1)
@@ -248,6 +295,68 @@ func TestStoreValidateTypes(t *testing.T) {
 	}
 }
 
+func TestStoreValidateEnumAndRange(t *testing.T) {
+	lo, hi := 0.0, 1.0
+	s := NewStore()
+	s.SetSchema(Schema{
+		"status":   {Type: FieldString, Enum: []any{"pending", "done"}},
+		"priority": {Type: FieldInt, Enum: []any{1.0, 2.0, 3.0}},
+		"score":    {Type: FieldAny, Min: &lo, Max: &hi},
+	})
+	s.Set("status", "pending")
+	s.Set("priority", 2) // enum values loaded from JSON are float64
+	s.Set("score", 0.5)
+	if errs := s.Validate(); errs != nil {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+
+	s.Set("status", "lost")
+	s.Set("priority", 4)
+	s.Set("score", 1.5)
+	if errs := s.Validate(); len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %v", errs)
+	}
+	s.Set("score", "high")
+	if errs := s.Validate(); len(errs) != 3 {
+		t.Fatalf("expected a non-number to fail the range check, got %v", errs)
+	}
+}
+
+func TestStoreStrict(t *testing.T) {
+	hi := 10.0
+	s := NewStore()
+	s.SetSchema(Schema{
+		"count":  {Type: FieldInt, Max: &hi},
+		"status": {Type: FieldString, Enum: []any{"open", "closed"}},
+	})
+	s.Set("count", "not a number")
+	if s.Strict() || !s.Has("count") {
+		t.Fatal("expected writes to be accepted outside strict mode")
+	}
+
+	s.SetStrict(true)
+	if err := s.Set("count", 3); err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range map[string]any{"count": 11, "status": "pending"} {
+		if err := s.Set(key, value); err == nil {
+			t.Errorf("expected %s=%v to be rejected", key, value)
+		}
+	}
+	if err := s.SetWithTTL("count", "x", time.Minute); err == nil {
+		t.Error("expected SetWithTTL to be checked too")
+	}
+	if v, _ := s.Get("count"); v != 3 || s.Has("status") {
+		t.Errorf("expected rejected writes to leave the store unchanged, got count %v", v)
+	}
+	if err := s.Set("other", struct{}{}); err != nil {
+		t.Errorf("expected keys outside the schema to be accepted, got %v", err)
+	}
+	if c := s.Copy(); !c.Strict() || c.Set("count", 20) == nil {
+		t.Error("expected the copy to stay strict")
+	}
+}
+
 func TestStoreValidateOpenWorld(t *testing.T) {
 	s := NewStore()
 	s.SetSchema(Schema{
@@ -271,6 +380,47 @@ func TestStoreValidateNoSchema(t *testing.T) {
 	}
 }
 
+func TestStoreCoerceToSchema(t *testing.T) {
+	s := NewStore()
+	s.SetSchema(Schema{
+		"count":   {Type: FieldInt},
+		"bad":     {Type: FieldInt},
+		"ratio":   {Type: FieldFloat},
+		"enabled": {Type: FieldBool},
+		"name":    {Type: FieldString},
+	})
+	s.Set("count", "5")
+	s.Set("bad", "abc")
+	s.Set("ratio", "0.5")
+	s.Set("enabled", "true")
+	s.Set("name", 7)
+
+	errs := s.CoerceToSchema()
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+	}
+	if v, _ := s.Get("count"); v != 5 {
+		t.Errorf("expected count=5 (int), got %v (%T)", v, v)
+	}
+	if v, _ := s.Get("bad"); v != "abc" {
+		t.Errorf("expected unconvertible value left unchanged, got %v", v)
+	}
+	if v, _ := s.Get("ratio"); v != 0.5 {
+		t.Errorf("expected ratio=0.5, got %v (%T)", v, v)
+	}
+	if v, _ := s.Get("enabled"); v != true {
+		t.Errorf("expected enabled=true, got %v (%T)", v, v)
+	}
+	if v, _ := s.Get("name"); v != "7" {
+		t.Errorf("expected name=\"7\", got %v (%T)", v, v)
+	}
+
+	s.Delete("bad")
+	if errs := s.Validate(); errs != nil {
+		t.Errorf("expected store to validate after coercion, got %v", errs)
+	}
+}
+
 func TestStoreCopy(t *testing.T) {
 	s := NewStore()
 	s.Set("a", 1)
@@ -294,3 +444,112 @@ func TestStoreCopy(t *testing.T) {
 		t.Fatal("copy should have schema")
 	}
 }
+
+// fakeClock replaces the TTL clock for the duration of the test and returns a
+// function that advances it.
+func fakeClock(t *testing.T) func(time.Duration) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+	return func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestStoreTTL(t *testing.T) {
+	advance := fakeClock(t)
+	s := NewStore()
+	s.Set("keep", 1)
+	s.SetWithTTL("scratch", "draft", time.Minute)
+	s.SetWithTTL("later", "x", time.Hour)
+
+	if v, ok := s.Get("scratch"); !ok || v != "draft" || s.Len() != 3 {
+		t.Fatalf("expected live entry before expiry, got %v %v len %d", v, ok, s.Len())
+	}
+	if at, ok := s.ExpiresAt("scratch"); !ok || !at.Equal(timeNow().Add(time.Minute)) {
+		t.Errorf("unexpected expiry %v %v", at, ok)
+	}
+
+	advance(time.Minute)
+	if s.Has("scratch") || s.Len() != 2 {
+		t.Fatalf("expected scratch to be hidden after expiry, len %d", s.Len())
+	}
+	if got := s.Keys(); !reflect.DeepEqual(got, []string{"keep", "later"}) {
+		t.Errorf("unexpected keys %v", got)
+	}
+	if got := s.Expired(); !reflect.DeepEqual(got, []string{"scratch"}) {
+		t.Errorf("expected scratch to be listed as expired, got %v", got)
+	}
+	if n := s.DeleteExpired(); n != 1 || len(s.Expired()) != 0 {
+		t.Errorf("expected one expired entry to be removed, got %d", n)
+	}
+
+	// Set clears a TTL; a non-positive TTL sets none.
+	s.Set("later", "y")
+	s.SetWithTTL("forever", 1, 0)
+	advance(2 * time.Hour)
+	if !s.Has("later") || !s.Has("forever") {
+		t.Error("expected keys without TTL to persist")
+	}
+}
+
+func TestStoreTTLCopyAndSchema(t *testing.T) {
+	advance := fakeClock(t)
+	s := NewStore()
+	s.SetSchema(Schema{"token": {Type: FieldString, Required: true}})
+	s.SetWithTTL("token", "abc", time.Second)
+	c := s.Copy()
+	if errs := s.Validate(); errs != nil {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	advance(time.Second)
+	if errs := s.Validate(); len(errs) != 1 {
+		t.Errorf("expected an expired required field to be missing, got %v", errs)
+	}
+	if c.Has("token") {
+		t.Error("expected the copy to keep the TTL")
+	}
+}
+
+func TestGraphDeleteExpiredMeta(t *testing.T) {
+	advance := fakeClock(t)
+	g := NewGraph[string, string](false)
+	g.AddNode("a", "")
+	g.AddNode("b", "")
+	g.AddEdge("a", "b", "", 1)
+	g.NodeMeta("a").SetWithTTL("note", "tmp", time.Minute)
+	g.NodeMeta("b").Set("note", "kept")
+	g.EdgeMeta("a", "b").SetWithTTL("note", "tmp", time.Minute)
+	g.DirectedEdgeMeta("b", "a").SetWithTTL("note", "tmp", time.Hour)
+	g.GraphMeta().SetWithTTL("note", "tmp", time.Minute)
+
+	data, err := Marshal(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	advance(time.Minute)
+	expired, err := Marshal(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := g.DeleteExpiredMeta(); n != 3 {
+		t.Errorf("expected 3 expired entries, got %d", n)
+	}
+	if g.NodeMetaCount("a") != 0 || g.NodeMetaCount("b") != 1 || g.DirectedEdgeMeta("b", "a").Len() != 1 {
+		t.Error("expected only expired entries to be removed")
+	}
+
+	// Marshal writes live entries only, as plain values.
+	g2, err := Unmarshal[string, string](data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g2.NodeMeta("a").ExpiresAt("note"); ok || !g2.NodeMeta("a").Has("note") {
+		t.Error("expected the entry to be restored without a TTL")
+	}
+	g3, err := Unmarshal[string, string](expired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g3.GraphMetaCount() != 0 || g3.EdgeMetaCount("a", "b") != 0 || g3.NodeMetaCount("b") != 1 {
+		t.Error("expected expired entries to be left out of the snapshot")
+	}
+}
@@ -0,0 +1,201 @@
+package spine
+
+import "sync"
+
+// SyncGraph is a Graph guarded by a sync.RWMutex so it can be shared between
+// goroutines. Reads take the read lock and run in parallel; mutations take
+// the write lock. Use Read and Write to run algorithms or several
+// operations under one lock.
+type SyncGraph[N, E any] struct {
+	mu sync.RWMutex
+	g  *Graph[N, E]
+}
+
+// NewSyncGraph creates an empty SyncGraph. If directed is true, edges are
+// one-way.
+func NewSyncGraph[N, E any](directed bool) *SyncGraph[N, E] {
+	return &SyncGraph[N, E]{g: NewGraph[N, E](directed)}
+}
+
+// Synchronized wraps an existing graph. g must not be used directly
+// afterwards, except through Read and Write.
+func Synchronized[N, E any](g *Graph[N, E]) *SyncGraph[N, E] {
+	return &SyncGraph[N, E]{g: g}
+}
+
+// Read calls fn with the graph under the read lock. fn must not modify the
+// graph, and that includes the lazy metadata accessors: NodeMeta, EdgeMeta
+// and GraphMeta create a store on first use, so only call them for nodes or
+// edges whose NodeMetaCount or EdgeMetaCount is non-zero, or use
+// NodeMetaValue and EdgeMetaValue instead.
+func (s *SyncGraph[N, E]) Read(fn func(g *Graph[N, E])) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(s.g)
+}
+
+// Write calls fn with the graph under the write lock and returns its error.
+// Changes are not rolled back on error; use Graph.Batch inside fn for that.
+func (s *SyncGraph[N, E]) Write(fn func(g *Graph[N, E]) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.g)
+}
+
+// AddNode adds or overwrites a node; see Graph.AddNode.
+func (s *SyncGraph[N, E]) AddNode(id string, data N) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.g.AddNode(id, data)
+}
+
+// AddEdge adds or overwrites an edge; see Graph.AddEdge.
+func (s *SyncGraph[N, E]) AddEdge(from, to string, data E, weight float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.g.AddEdge(from, to, data, weight)
+}
+
+// RemoveNode removes a node and its incident edges.
+func (s *SyncGraph[N, E]) RemoveNode(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.g.RemoveNode(id)
+}
+
+// RemoveEdge removes the edge from -> to.
+func (s *SyncGraph[N, E]) RemoveEdge(from, to string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.g.RemoveEdge(from, to)
+}
+
+// GetNode returns a node by ID.
+func (s *SyncGraph[N, E]) GetNode(id string) (Node[N], bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.GetNode(id)
+}
+
+// HasNode reports whether a node exists.
+func (s *SyncGraph[N, E]) HasNode(id string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.HasNode(id)
+}
+
+// GetEdge returns the edge from -> to.
+func (s *SyncGraph[N, E]) GetEdge(from, to string) (Edge[E], bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.GetEdge(from, to)
+}
+
+// HasEdge reports whether the edge from -> to exists.
+func (s *SyncGraph[N, E]) HasEdge(from, to string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.HasEdge(from, to)
+}
+
+// Nodes returns all nodes sorted by ID.
+func (s *SyncGraph[N, E]) Nodes() []Node[N] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.Nodes()
+}
+
+// Edges returns all edges.
+func (s *SyncGraph[N, E]) Edges() []Edge[E] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.Edges()
+}
+
+// Neighbors returns the IDs of the nodes id has edges to.
+func (s *SyncGraph[N, E]) Neighbors(id string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.Neighbors(id)
+}
+
+// OutEdges returns the edges leaving id.
+func (s *SyncGraph[N, E]) OutEdges(id string) []Edge[E] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.OutEdges(id)
+}
+
+// InEdges returns the edges entering id.
+func (s *SyncGraph[N, E]) InEdges(id string) []Edge[E] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.InEdges(id)
+}
+
+// Order returns the number of nodes.
+func (s *SyncGraph[N, E]) Order() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.Order()
+}
+
+// Size returns the number of edges.
+func (s *SyncGraph[N, E]) Size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.Size()
+}
+
+// Copy returns an unsynchronized deep copy of the graph, e.g. to run a long
+// computation without holding the lock.
+func (s *SyncGraph[N, E]) Copy() *Graph[N, E] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.Copy()
+}
+
+// NodeMetaValue returns the metadata value of key on node id.
+func (s *SyncGraph[N, E]) NodeMetaValue(id, key string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if store := s.g.nodeMeta[id]; store != nil {
+		return store.Get(key)
+	}
+	return nil, false
+}
+
+// EdgeMetaValue returns the metadata value of key on the edge from -> to.
+func (s *SyncGraph[N, E]) EdgeMetaValue(from, to, key string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f, t := s.g.edgeMetaKey(from, to)
+	if store := s.g.edgeMeta[f][t]; store != nil {
+		return store.Get(key)
+	}
+	return nil, false
+}
+
+// SetNodeMeta sets a metadata value on node id. It returns false if the node
+// does not exist or a strict store rejects the value.
+func (s *SyncGraph[N, E]) SetNodeMeta(id, key string, value any) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	store := s.g.NodeMeta(id)
+	if store == nil {
+		return false
+	}
+	return store.Set(key, value) == nil
+}
+
+// SetEdgeMeta sets a metadata value on the edge from -> to. It returns false
+// if the edge does not exist or a strict store rejects the value.
+func (s *SyncGraph[N, E]) SetEdgeMeta(from, to, key string, value any) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	store := s.g.EdgeMeta(from, to)
+	if store == nil {
+		return false
+	}
+	return store.Set(key, value) == nil
+}
@@ -0,0 +1,122 @@
+package spine
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSyncGraphConcurrent(t *testing.T) {
+	sg := NewSyncGraph[int, string](true)
+	sg.AddNode("root", 0)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				id := fmt.Sprintf("n%d-%d", w, i)
+				err := sg.Write(func(g *Graph[int, string]) error {
+					g.AddNode(id, i)
+					return g.AddEdge("root", id, "", 1)
+				})
+				if err != nil {
+					t.Error(err)
+				}
+				sg.SetNodeMeta(id, "worker", w)
+			}
+		}(w)
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				sg.Read(func(g *Graph[int, string]) {
+					if got := len(BFS(g, "root", nil)); got != g.Order() {
+						t.Errorf("BFS reached %d of %d nodes", got, g.Order())
+					}
+				})
+				sg.NodeMetaValue("n0-0", "worker")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if sg.Order() != 201 || sg.Size() != 200 {
+		t.Fatalf("expected 201 nodes and 200 edges, got %d and %d", sg.Order(), sg.Size())
+	}
+	if v, ok := sg.NodeMetaValue("n3-7", "worker"); !ok || v != 3 {
+		t.Errorf("expected worker 3, got %v", v)
+	}
+}
+
+func TestSyncGraphWrite(t *testing.T) {
+	g := NewGraph[int, int](false)
+	g.AddNode("a", 1)
+	sg := Synchronized(g)
+
+	err := sg.Write(func(g *Graph[int, int]) error {
+		g.AddNode("b", 2)
+		return g.AddEdge("a", "b", 0, 3)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sg.SetEdgeMeta("b", "a", "kind", "road") {
+		t.Fatal("expected edge metadata to be set")
+	}
+	if v, _ := sg.EdgeMetaValue("a", "b", "kind"); v != "road" {
+		t.Errorf("expected kind road on the undirected edge, got %v", v)
+	}
+	if sg.SetNodeMeta("missing", "k", 1) {
+		t.Error("expected SetNodeMeta to fail for a missing node")
+	}
+	if _, ok := sg.NodeMetaValue("a", "k"); ok {
+		t.Error("expected no metadata on a")
+	}
+	if c := sg.Copy(); c.Order() != 2 || !c.HasEdge("b", "a") {
+		t.Error("expected the copy to hold both nodes and the edge")
+	}
+}
+
+func TestSyncGraphTrackedComponentsRead(t *testing.T) {
+	sg := NewSyncGraph[int, string](false)
+	sg.Write(func(g *Graph[int, string]) error {
+		g.TrackComponents(true)
+		for i := 0; i < 20; i++ {
+			g.AddNode(fmt.Sprint(i), i)
+			if i > 0 && i%5 != 0 {
+				g.AddEdge(fmt.Sprint(i-1), fmt.Sprint(i), "", 1)
+			}
+		}
+		return nil
+	})
+
+	// Reads must not rebuild or compress the tracked set, stale or not.
+	for _, mutate := range []func(g *Graph[int, string]){
+		func(g *Graph[int, string]) {},
+		func(g *Graph[int, string]) { g.RemoveEdge("1", "2") },
+		func(g *Graph[int, string]) { g.AddNode("x", 0) },
+	} {
+		sg.Write(func(g *Graph[int, string]) error { mutate(g); return nil })
+		var want int
+		sg.Read(func(g *Graph[int, string]) {
+			want = len(ConnectedComponents(g))
+		})
+		var wg sync.WaitGroup
+		for r := 0; r < 4; r++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sg.Read(func(g *Graph[int, string]) {
+					if got := len(ConnectedComponents(g)); got != want {
+						t.Errorf("expected %d components, got %d", want, got)
+					}
+				})
+			}()
+		}
+		wg.Wait()
+	}
+}
@@ -2,6 +2,7 @@ package spine
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
@@ -38,6 +39,35 @@ func (s TaskState) String() string {
 	}
 }
 
+// MarshalJSON encodes the state as its string name, e.g. "Done".
+func (s TaskState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes a state from its string name or, for backward
+// compatibility with older snapshots, from its numeric value.
+func (s *TaskState) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		for st := Pending; st <= Skipped; st++ {
+			if st.String() == name {
+				*s = st
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown task state %q", name)
+	}
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("task state must be a string or number: %s", data)
+	}
+	if n < int(Pending) || n > int(Skipped) {
+		return fmt.Errorf("unknown task state %d", n)
+	}
+	*s = TaskState(n)
+	return nil
+}
+
 // validTransitions defines the allowed state transitions.
 var validTransitions = map[TaskState][]TaskState{
 	Pending: {Ready, Skipped},
@@ -54,23 +84,38 @@ type Task[T any] struct {
 
 // TaskGraph manages tasks with dependencies, state tracking, and execution.
 type TaskGraph[T any] struct {
-	mu    sync.Mutex
-	graph *Graph[Task[T], struct{}]
+	mu      sync.RWMutex
+	graph   *Graph[Task[T], struct{}]
+	changed chan struct{} // closed and replaced whenever task states change
 }
 
 // NewTaskGraph creates a new task graph.
 func NewTaskGraph[T any]() *TaskGraph[T] {
 	return &TaskGraph[T]{
-		graph: NewGraph[Task[T], struct{}](true),
+		graph:   NewGraph[Task[T], struct{}](true),
+		changed: make(chan struct{}),
 	}
 }
 
+// Errors returned by WaitReady when no task can become ready.
+var (
+	ErrTasksComplete   = errors.New("all tasks have finished")
+	ErrTasksDeadlocked = errors.New("no task is ready or running but some are still pending")
+)
+
+// notifyLocked wakes all WaitReady callers. Caller must hold tg.mu.
+func (tg *TaskGraph[T]) notifyLocked() {
+	close(tg.changed)
+	tg.changed = make(chan struct{})
+}
+
 // AddTask adds a task with the given ID and data. Initial state is Pending.
 func (tg *TaskGraph[T]) AddTask(id string, data T) {
 	tg.mu.Lock()
 	defer tg.mu.Unlock()
 	t := Task[T]{ID: id, Data: data, State: Pending}
 	tg.graph.AddNode(id, t)
+	tg.notifyLocked()
 }
 
 // AddDependency adds a dependency: task `from` depends on task `to`.
@@ -84,11 +129,34 @@ func (tg *TaskGraph[T]) AddDependency(from, to string) error {
 // Ready returns all tasks whose dependencies are all Done and whose state is Ready.
 // It also transitions Pending tasks to Ready if all deps are met.
 func (tg *TaskGraph[T]) Ready() []Task[T] {
+	tg.mu.RLock()
+	ready, promote := tg.scanReadyLocked()
+	tg.mu.RUnlock()
+	if !promote {
+		return ready
+	}
+	// Only promoting Pending tasks needs the write lock. readyLocked scans
+	// again since tasks may have changed in between.
 	tg.mu.Lock()
 	defer tg.mu.Unlock()
 	return tg.readyLocked()
 }
 
+// scanReadyLocked returns the Ready tasks and reports whether any Pending
+// task has all its dependencies Done and is due to be promoted. Caller must
+// hold tg.mu for reading.
+func (tg *TaskGraph[T]) scanReadyLocked() (ready []Task[T], promote bool) {
+	for _, n := range tg.graph.Nodes() {
+		switch task := n.Data; task.State {
+		case Ready:
+			ready = append(ready, task)
+		case Pending:
+			promote = promote || tg.allDepsDone(task.ID)
+		}
+	}
+	return ready, promote
+}
+
 func (tg *TaskGraph[T]) readyLocked() []Task[T] {
 	var ready []Task[T]
 	for _, n := range tg.graph.Nodes() {
@@ -122,6 +190,45 @@ func (tg *TaskGraph[T]) allDepsDone(id string) bool {
 	return true
 }
 
+// WaitReady returns the currently ready tasks, blocking until a state change
+// makes at least one task ready if none are. It returns ErrTasksComplete when
+// every task has finished, ErrTasksDeadlocked when tasks are still pending but
+// nothing is ready or running, or ctx.Err() if ctx is done first.
+func (tg *TaskGraph[T]) WaitReady(ctx context.Context) ([]Task[T], error) {
+	for {
+		tg.mu.Lock()
+		ready := tg.readyLocked()
+		if len(ready) > 0 {
+			tg.mu.Unlock()
+			return ready, nil
+		}
+		running, pending := 0, 0
+		for _, n := range tg.graph.Nodes() {
+			switch n.Data.State {
+			case Running:
+				running++
+			case Pending:
+				pending++
+			}
+		}
+		changed := tg.changed
+		tg.mu.Unlock()
+
+		if running == 0 {
+			if pending == 0 {
+				return nil, ErrTasksComplete
+			}
+			return nil, ErrTasksDeadlocked
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
 // Transition moves a task to a new state, validating the transition.
 func (tg *TaskGraph[T]) Transition(id string, newState TaskState) error {
 	tg.mu.Lock()
@@ -140,6 +247,7 @@ func (tg *TaskGraph[T]) transitionLocked(id string, newState TaskState) error {
 		if s == newState {
 			task.State = newState
 			tg.graph.AddNode(id, task)
+			tg.notifyLocked()
 			return nil
 		}
 	}
@@ -148,8 +256,8 @@ func (tg *TaskGraph[T]) transitionLocked(id string, newState TaskState) error {
 
 // GetTask returns the current state of a task.
 func (tg *TaskGraph[T]) GetTask(id string) (Task[T], bool) {
-	tg.mu.Lock()
-	defer tg.mu.Unlock()
+	tg.mu.RLock()
+	defer tg.mu.RUnlock()
 	n, ok := tg.graph.GetNode(id)
 	if !ok {
 		var zero Task[T]
@@ -158,28 +266,93 @@ func (tg *TaskGraph[T]) GetTask(id string) (Task[T], bool) {
 	return n.Data, true
 }
 
+// Generations groups the task IDs into dependency levels: every task in a
+// level depends only on tasks in earlier levels, so each level can run in
+// parallel once the previous one is done (see TopologicalGenerations).
+// Returns an error if the dependencies contain a cycle.
+func (tg *TaskGraph[T]) Generations() ([][]string, error) {
+	tg.mu.RLock()
+	defer tg.mu.RUnlock()
+	return TopologicalGenerations(tg.graph)
+}
+
 // Graph returns the underlying graph for traversal/query operations.
 func (tg *TaskGraph[T]) Graph() *Graph[Task[T], struct{}] {
 	return tg.graph
 }
 
+// Limiter caps the number of tasks executing at once across every Run that
+// shares it, regardless of each run's own concurrency. Create one with
+// NewLimiter and pass it to Run with WithLimiter.
+type Limiter struct {
+	slots chan struct{}
+}
+
+// NewLimiter returns a Limiter allowing at most n concurrent executions.
+// Values of n below 1 are treated as 1.
+func NewLimiter(n int) *Limiter {
+	if n < 1 {
+		n = 1
+	}
+	return &Limiter{slots: make(chan struct{}, n)}
+}
+
+// Acquire blocks until a slot is free or ctx is done.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot obtained with Acquire.
+func (l *Limiter) Release() {
+	<-l.slots
+}
+
+// RunOption configures a call to TaskGraph.Run.
+type RunOption func(*runConfig)
+
+type runConfig struct {
+	limiter *Limiter
+}
+
+// WithLimiter makes Run acquire a slot from l around each task execution, so
+// runs sharing l are collectively capped at l's size.
+func WithLimiter(l *Limiter) RunOption {
+	return func(c *runConfig) { c.limiter = l }
+}
+
 // Run executes tasks in dependency order with the given concurrency limit.
 // The fn function is called for each task. If fn returns an error, the task
 // transitions to Failed; otherwise it transitions to Done.
 // Returns an error if any task fails.
-func (tg *TaskGraph[T]) Run(ctx context.Context, concurrency int, fn func(Task[T]) error) error {
+func (tg *TaskGraph[T]) Run(ctx context.Context, concurrency int, fn func(Task[T]) error, opts ...RunOption) error {
 	if concurrency < 1 {
 		concurrency = 1
 	}
+	var cfg runConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	run := fn
+	if cfg.limiter != nil {
+		run = func(t Task[T]) error {
+			if err := cfg.limiter.Acquire(ctx); err != nil {
+				return err
+			}
+			defer cfg.limiter.Release()
+			return fn(t)
+		}
+	}
 
 	var mu sync.Mutex
 	var taskErrors []error
 
 	for {
-		tg.mu.Lock()
-		ready := tg.readyLocked()
-		tg.mu.Unlock()
-
+		ready := tg.Ready()
 		if len(ready) == 0 {
 			break
 		}
@@ -208,11 +381,11 @@ func (tg *TaskGraph[T]) Run(ctx context.Context, concurrency int, fn func(Task[T
 				defer func() { <-sem }()
 
 				// Re-read the task to get the Running state data.
-				tg.mu.Lock()
+				tg.mu.RLock()
 				current, _ := tg.graph.GetNode(t.ID)
-				tg.mu.Unlock()
+				tg.mu.RUnlock()
 
-				err := fn(current.Data)
+				err := run(current.Data)
 				tg.mu.Lock()
 				if err != nil {
 					tg.transitionLocked(t.ID, Failed)
@@ -251,4 +424,5 @@ func (tg *TaskGraph[T]) Reset() {
 		task.State = Pending
 		tg.graph.AddNode(task.ID, task)
 	}
+	tg.notifyLocked()
 }
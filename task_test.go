@@ -2,7 +2,10 @@ package spine
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -37,6 +40,25 @@ func TestTaskDependency(t *testing.T) {
 	}
 }
 
+func TestTaskGenerations(t *testing.T) {
+	tg := NewTaskGraph[string]()
+	for _, id := range []string{"fetch", "parse", "lint", "store"} {
+		tg.AddTask(id, id)
+	}
+	tg.AddDependency("parse", "fetch")
+	tg.AddDependency("lint", "fetch")
+	tg.AddDependency("store", "parse")
+
+	gens, err := tg.Generations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"fetch"}, {"lint", "parse"}, {"store"}}
+	if !reflect.DeepEqual(gens, want) {
+		t.Errorf("expected %v, got %v", want, gens)
+	}
+}
+
 func TestTaskTransition(t *testing.T) {
 	tg := NewTaskGraph[string]()
 	tg.AddTask("t1", "task")
@@ -162,6 +184,51 @@ func TestTaskRunConcurrency(t *testing.T) {
 	}
 }
 
+func TestTaskRunSharedLimiter(t *testing.T) {
+	build := func() *TaskGraph[int] {
+		tg := NewTaskGraph[int]()
+		for i := 0; i < 5; i++ {
+			tg.AddTask(string(rune('a'+i)), i)
+		}
+		return tg
+	}
+	limiter := NewLimiter(2)
+
+	var running, maxConcurrent atomic.Int32
+	work := func(Task[int]) error {
+		cur := running.Add(1)
+		for {
+			old := maxConcurrent.Load()
+			if cur <= old || maxConcurrent.CompareAndSwap(old, cur) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = build().Run(context.Background(), 4, work, WithLimiter(limiter))
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := maxConcurrent.Load(); got > 2 {
+		t.Errorf("expected at most 2 concurrent executions, got %d", got)
+	}
+}
+
 func TestTaskRunFailure(t *testing.T) {
 	tg := NewTaskGraph[string]()
 	tg.AddTask("t1", "will-fail")
@@ -250,6 +317,119 @@ func TestTaskStateString(t *testing.T) {
 	}
 }
 
+func TestTaskStateJSON(t *testing.T) {
+	task := Task[string]{ID: "t1", Data: "build", State: Done}
+	data, err := json.Marshal(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"State":"Done"`) {
+		t.Fatalf("expected string state in JSON, got %s", data)
+	}
+
+	var back Task[string]
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.State != Done || back.ID != "t1" {
+		t.Fatalf("unexpected round trip: %+v", back)
+	}
+}
+
+func TestTaskStateUnmarshalNumeric(t *testing.T) {
+	var task Task[string]
+	if err := json.Unmarshal([]byte(`{"ID":"t1","State":4}`), &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.State != Failed {
+		t.Fatalf("expected Failed from numeric 4, got %s", task.State)
+	}
+
+	var s TaskState
+	if err := json.Unmarshal([]byte(`"Bogus"`), &s); err == nil {
+		t.Fatal("expected error for unknown state name")
+	}
+	if err := json.Unmarshal([]byte(`42`), &s); err == nil {
+		t.Fatal("expected error for out-of-range state")
+	}
+}
+
+func TestTaskWaitReadyWakesOnDependency(t *testing.T) {
+	tg := NewTaskGraph[string]()
+	tg.AddTask("t1", "first")
+	tg.AddTask("t2", "second")
+	tg.AddDependency("t2", "t1")
+
+	tg.Ready()
+	if err := tg.Transition("t1", Running); err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		tasks []Task[string]
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		tasks, err := tg.WaitReady(context.Background())
+		done <- result{tasks, err}
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("WaitReady returned before any task was ready")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if err := tg.Transition("t1", Done); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+		if ids := taskIDs(r.tasks); len(ids) != 1 || ids[0] != "t2" {
+			t.Fatalf("expected [t2] ready, got %v", ids)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitReady was not woken by the transition")
+	}
+}
+
+func TestTaskWaitReadyCompleteAndDeadlock(t *testing.T) {
+	tg := NewTaskGraph[string]()
+	if _, err := tg.WaitReady(context.Background()); !errors.Is(err, ErrTasksComplete) {
+		t.Fatalf("expected ErrTasksComplete for empty graph, got %v", err)
+	}
+
+	tg.AddTask("t1", "first")
+	tg.AddTask("t2", "second")
+	tg.AddDependency("t2", "t1")
+	tg.Ready()
+	tg.Transition("t1", Running)
+	tg.Transition("t1", Failed)
+
+	// t2 can never run because t1 failed.
+	if _, err := tg.WaitReady(context.Background()); !errors.Is(err, ErrTasksDeadlocked) {
+		t.Fatalf("expected ErrTasksDeadlocked, got %v", err)
+	}
+}
+
+func TestTaskWaitReadyContextCancel(t *testing.T) {
+	tg := NewTaskGraph[string]()
+	tg.AddTask("t1", "first")
+	tg.Ready()
+	tg.Transition("t1", Running)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := tg.WaitReady(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
 func TestTaskDependencyMissingTask(t *testing.T) {
 	tg := NewTaskGraph[string]()
 	tg.AddTask("t1", "task")
@@ -4,20 +4,37 @@ import (
 	"container/heap"
 	"errors"
 	"math"
+	"math/bits"
 	"sort"
 )
 
+// TraverseOptions bounds a traversal. MaxNodes, if > 0, stops the traversal
+// after that many nodes have been visited.
+type TraverseOptions struct {
+	MaxNodes int
+}
+
 // BFS performs a breadth-first search starting from the given node.
 // The visitor function is called for each visited node. If visitor returns false,
 // the traversal stops early. Returns the visited node IDs in BFS order.
 func BFS[N, E any](g *Graph[N, E], start string, visitor func(Node[N]) bool) []string {
+	order, _ := BFSWithOptions(g, start, visitor, TraverseOptions{})
+	return order
+}
+
+// BFSWithOptions performs a breadth-first search like BFS, bounded by opts.
+// truncated reports whether the traversal stopped at opts.MaxNodes with
+// reachable nodes left unvisited; stopping via the visitor is not truncation.
+func BFSWithOptions[N, E any](g *Graph[N, E], start string, visitor func(Node[N]) bool, opts TraverseOptions) (order []string, truncated bool) {
 	if !g.HasNode(start) {
-		return nil
+		return nil, false
 	}
 	visited := map[string]bool{start: true}
 	queue := []string{start}
-	var order []string
 	for len(queue) > 0 {
+		if opts.MaxNodes > 0 && len(order) >= opts.MaxNodes {
+			return order, true
+		}
 		id := queue[0]
 		queue = queue[1:]
 		n, _ := g.GetNode(id)
@@ -33,6 +50,40 @@ func BFS[N, E any](g *Graph[N, E], start string, visitor func(Node[N]) bool) []s
 			}
 		}
 	}
+	return order, false
+}
+
+// BFSEdges performs a breadth-first search like BFS, but the visitor also
+// receives the edge that led to each node. For the start node, from is empty
+// and e is the zero edge. If visit returns false, the traversal stops early.
+// Returns the visited node IDs in BFS order.
+func BFSEdges[N, E any](g *Graph[N, E], start string, visit func(from string, e Edge[E], to Node[N]) bool) []string {
+	if !g.HasNode(start) {
+		return nil
+	}
+	type step struct {
+		from string
+		edge Edge[E]
+		id   string
+	}
+	visited := map[string]bool{start: true}
+	queue := []step{{id: start}}
+	var order []string
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		n, _ := g.GetNode(cur.id)
+		order = append(order, cur.id)
+		if visit != nil && !visit(cur.from, cur.edge, n) {
+			break
+		}
+		for _, e := range g.OutEdges(cur.id) {
+			if !visited[e.To] {
+				visited[e.To] = true
+				queue = append(queue, step{from: cur.id, edge: e, id: e.To})
+			}
+		}
+	}
 	return order
 }
 
@@ -40,17 +91,28 @@ func BFS[N, E any](g *Graph[N, E], start string, visitor func(Node[N]) bool) []s
 // The visitor function is called for each visited node. If visitor returns false,
 // the traversal stops early. Returns the visited node IDs in DFS order.
 func DFS[N, E any](g *Graph[N, E], start string, visitor func(Node[N]) bool) []string {
+	order, _ := DFSWithOptions(g, start, visitor, TraverseOptions{})
+	return order
+}
+
+// DFSWithOptions performs a depth-first search like DFS, bounded by opts.
+// truncated reports whether the traversal stopped at opts.MaxNodes with
+// reachable nodes left unvisited; stopping via the visitor is not truncation.
+func DFSWithOptions[N, E any](g *Graph[N, E], start string, visitor func(Node[N]) bool, opts TraverseOptions) (order []string, truncated bool) {
 	if !g.HasNode(start) {
-		return nil
+		return nil, false
 	}
 	visited := make(map[string]bool)
-	var order []string
 	stopped := false
 	var walk func(id string)
 	walk = func(id string) {
 		if stopped || visited[id] {
 			return
 		}
+		if opts.MaxNodes > 0 && len(order) >= opts.MaxNodes {
+			stopped, truncated = true, true
+			return
+		}
 		visited[id] = true
 		n, _ := g.GetNode(id)
 		order = append(order, id)
@@ -64,19 +126,96 @@ func DFS[N, E any](g *Graph[N, E], start string, visitor func(Node[N]) bool) []s
 		}
 	}
 	walk(start)
-	return order
+	return order, truncated
+}
+
+// NearestMatching performs a breadth-first search from start and returns the
+// hop-shortest path to the first node accepted by pred, following out-edges
+// with neighbors visited in ID order. start itself is returned as a
+// single-node path if it matches. Returns false if start does not exist or no
+// reachable node matches.
+func NearestMatching[N, E any](g *Graph[N, E], start string, pred func(Node[N]) bool) ([]string, bool) {
+	if !g.HasNode(start) {
+		return nil, false
+	}
+	prev := map[string]string{}
+	visited := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if n, _ := g.GetNode(id); pred(n) {
+			path := []string{id}
+			for id != start {
+				id = prev[id]
+				path = append(path, id)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path, true
+		}
+		for _, nb := range g.Neighbors(id) {
+			if !visited[nb] {
+				visited[nb] = true
+				prev[nb] = id
+				queue = append(queue, nb)
+			}
+		}
+	}
+	return nil, false
 }
 
 // ShortestPath computes the shortest weighted path from src to dst using Dijkstra's algorithm.
 // Returns the path as a slice of node IDs and the total cost.
 // Returns an error if src or dst don't exist, or no path exists.
 func ShortestPath[N, E any](g *Graph[N, E], src, dst string) ([]string, float64, error) {
+	return ShortestPathWithOptions(g, src, dst, ShortestPathOpts[E]{})
+}
+
+// ShortestPathOpts constrains ShortestPathWithOptions. The zero value places
+// no constraints.
+type ShortestPathOpts[E any] struct {
+	// AvoidNodes lists nodes the path may not pass through.
+	AvoidNodes []string
+	// AvoidEdges lists [from, to] edges the path may not use. On undirected
+	// graphs an entry avoids the edge in both directions.
+	AvoidEdges [][2]string
+	// MaxCost, if > 0, rejects paths costing more than MaxCost.
+	MaxCost float64
+	// EdgeFilter, if set, is called for each edge; the path only uses edges
+	// for which it returns true.
+	EdgeFilter func(Edge[E]) bool
+}
+
+// ShortestPathWithOptions computes the shortest weighted path from src to
+// dst like ShortestPath, routing around the nodes and edges excluded by
+// opts without copying the graph. Returns an error if src or dst don't
+// exist or are avoided, or no path satisfies opts.
+func ShortestPathWithOptions[N, E any](g *Graph[N, E], src, dst string, opts ShortestPathOpts[E]) ([]string, float64, error) {
 	if !g.HasNode(src) {
 		return nil, 0, errors.New("source node not found")
 	}
 	if !g.HasNode(dst) {
 		return nil, 0, errors.New("destination node not found")
 	}
+	avoidNode := make(map[string]bool, len(opts.AvoidNodes))
+	for _, id := range opts.AvoidNodes {
+		avoidNode[id] = true
+	}
+	if avoidNode[src] {
+		return nil, 0, errors.New("source node is avoided")
+	}
+	if avoidNode[dst] {
+		return nil, 0, errors.New("destination node is avoided")
+	}
+	avoidEdge := make(map[[2]string]bool, len(opts.AvoidEdges))
+	for _, e := range opts.AvoidEdges {
+		avoidEdge[e] = true
+		if !g.Directed {
+			avoidEdge[[2]string{e[1], e[0]}] = true
+		}
+	}
 
 	dist := map[string]float64{src: 0}
 	prev := map[string]string{}
@@ -91,7 +230,16 @@ func ShortestPath[N, E any](g *Graph[N, E], src, dst string) ([]string, float64,
 			break
 		}
 		for _, e := range g.OutEdges(cur.id) {
+			if avoidNode[e.To] || avoidEdge[[2]string{e.From, e.To}] {
+				continue
+			}
+			if opts.EdgeFilter != nil && !opts.EdgeFilter(e) {
+				continue
+			}
 			nd := cur.dist + e.Weight
+			if opts.MaxCost > 0 && nd > opts.MaxCost {
+				continue
+			}
 			if d, ok := dist[e.To]; !ok || nd < d {
 				dist[e.To] = nd
 				prev[e.To] = cur.id
@@ -101,6 +249,9 @@ func ShortestPath[N, E any](g *Graph[N, E], src, dst string) ([]string, float64,
 	}
 
 	if _, ok := dist[dst]; !ok {
+		if opts.MaxCost > 0 {
+			return nil, 0, errors.New("no path found within cost limit")
+		}
 		return nil, 0, errors.New("no path found")
 	}
 
@@ -119,6 +270,352 @@ func ShortestPath[N, E any](g *Graph[N, E], src, dst string) ([]string, float64,
 	return path, dist[dst], nil
 }
 
+// ShortestPathTree runs Dijkstra from src and returns the tree of predecessor
+// edges as [from, to] pairs, sorted by from then to. Every node reachable from
+// src other than src itself has exactly one incoming tree edge. Returns nil if
+// src does not exist.
+func ShortestPathTree[N, E any](g *Graph[N, E], src string) [][2]string {
+	if !g.HasNode(src) {
+		return nil
+	}
+
+	dist := map[string]float64{src: 0}
+	prev := map[string]string{}
+	h := &dijkstraHeap{{id: src, dist: 0}}
+
+	for h.Len() > 0 {
+		cur := heap.Pop(h).(dijkstraItem)
+		if cur.dist > dist[cur.id] {
+			continue
+		}
+		for _, e := range g.OutEdges(cur.id) {
+			if e.To == src {
+				continue
+			}
+			nd := cur.dist + e.Weight
+			if d, ok := dist[e.To]; !ok || nd < d {
+				dist[e.To] = nd
+				prev[e.To] = cur.id
+				heap.Push(h, dijkstraItem{id: e.To, dist: nd})
+			}
+		}
+	}
+
+	tree := make([][2]string, 0, len(prev))
+	for to, from := range prev {
+		tree = append(tree, [2]string{from, to})
+	}
+	sort.Slice(tree, func(i, j int) bool {
+		if tree[i][0] != tree[j][0] {
+			return g.lessID(tree[i][0], tree[j][0])
+		}
+		return g.lessID(tree[i][1], tree[j][1])
+	})
+	return tree
+}
+
+// ShortestPathMaxHops finds the cheapest path from src to dst that uses at
+// most maxHops edges, via Bellman-Ford relaxation bounded to maxHops rounds.
+// Unlike ShortestPath it tolerates negative weights. Returns an error if src
+// or dst don't exist, maxHops is negative, or no path fits within the budget.
+func ShortestPathMaxHops[N, E any](g *Graph[N, E], src, dst string, maxHops int) ([]string, float64, error) {
+	if !g.HasNode(src) {
+		return nil, 0, errors.New("source node not found")
+	}
+	if !g.HasNode(dst) {
+		return nil, 0, errors.New("destination node not found")
+	}
+	if maxHops < 0 {
+		return nil, 0, errors.New("maxHops must be non-negative")
+	}
+
+	// hop records the last edge of the best path found to a node and the
+	// round in which its predecessor's path was settled.
+	type hop struct {
+		from  string
+		round int
+	}
+	dist := []map[string]float64{{src: 0}}
+	prev := []map[string]hop{{}}
+	nodes := g.Nodes()
+
+	for k := 1; k <= maxHops; k++ {
+		last := dist[k-1]
+		cur := make(map[string]float64, len(last))
+		curPrev := make(map[string]hop, len(prev[k-1]))
+		for id, d := range last {
+			cur[id] = d
+		}
+		for id, h := range prev[k-1] {
+			curPrev[id] = h
+		}
+		changed := false
+		for _, n := range nodes {
+			du, ok := last[n.ID]
+			if !ok {
+				continue
+			}
+			for _, e := range g.OutEdges(n.ID) {
+				nd := du + e.Weight
+				if d, ok := cur[e.To]; !ok || nd < d {
+					cur[e.To] = nd
+					curPrev[e.To] = hop{from: n.ID, round: k - 1}
+					changed = true
+				}
+			}
+		}
+		dist = append(dist, cur)
+		prev = append(prev, curPrev)
+		if !changed {
+			break
+		}
+	}
+
+	final := len(dist) - 1
+	total, ok := dist[final][dst]
+	if !ok {
+		return nil, 0, errors.New("no path found within hop limit")
+	}
+
+	path := []string{dst}
+	for id, round := dst, final; ; {
+		h, ok := prev[round][id]
+		if !ok {
+			break
+		}
+		path = append(path, h.from)
+		id, round = h.from, h.round
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, total, nil
+}
+
+// ErrNegativeCycle is returned when a negative-weight cycle makes shortest
+// paths undefined.
+var ErrNegativeCycle = errors.New("graph contains a negative cycle")
+
+// BellmanFordResult holds the single-source shortest paths computed by
+// BellmanFord. Dist and Prev only contain nodes reachable from Source.
+type BellmanFordResult struct {
+	Source string             `json:"source"`
+	Dist   map[string]float64 `json:"dist"`
+	Prev   map[string]string  `json:"prev"`
+	// NegativeCycle is a negative-weight cycle reachable from Source, in
+	// edge order starting at its smallest ID, or nil if there is none.
+	NegativeCycle []string `json:"negative_cycle,omitempty"`
+}
+
+// PathTo returns the shortest path from Source to dst, or nil if dst is
+// unreachable or the result has a negative cycle.
+func (r *BellmanFordResult) PathTo(dst string) []string {
+	if _, ok := r.Dist[dst]; !ok || r.NegativeCycle != nil {
+		return nil
+	}
+	path := []string{dst}
+	for id := dst; id != r.Source; {
+		id = r.Prev[id]
+		path = append(path, id)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// BellmanFord computes shortest paths from src to every reachable node with
+// the Bellman-Ford algorithm. Unlike ShortestPath it tolerates negative
+// weights. On an undirected graph every edge can be walked both ways, so a
+// single negative edge forms a negative cycle. If a negative cycle is
+// reachable from src, the result records it in NegativeCycle and
+// ErrNegativeCycle is returned alongside it. Returns an error if src does
+// not exist.
+func BellmanFord[N, E any](g *Graph[N, E], src string) (*BellmanFordResult, error) {
+	if !g.HasNode(src) {
+		return nil, errors.New("source node not found")
+	}
+	res := &BellmanFordResult{Source: src, Dist: map[string]float64{src: 0}, Prev: map[string]string{}}
+	nodes := g.Nodes()
+
+	// relax makes one pass over every edge and returns the last node whose
+	// distance improved, or "" if none did.
+	relax := func() string {
+		last := ""
+		for _, n := range nodes {
+			du, ok := res.Dist[n.ID]
+			if !ok {
+				continue
+			}
+			for _, e := range g.OutEdges(n.ID) {
+				if d, ok := res.Dist[e.To]; !ok || du+e.Weight < d {
+					res.Dist[e.To] = du + e.Weight
+					res.Prev[e.To] = n.ID
+					last = e.To
+				}
+			}
+		}
+		return last
+	}
+	for i := 1; i < len(nodes); i++ {
+		if relax() == "" {
+			return res, nil
+		}
+	}
+	v := relax()
+	if v == "" {
+		return res, nil
+	}
+
+	// Walking back len(nodes) predecessors from a node still improving in
+	// the extra pass is guaranteed to land on the cycle.
+	for range nodes {
+		v = res.Prev[v]
+	}
+	cycle := []string{v}
+	for u := res.Prev[v]; u != v; u = res.Prev[u] {
+		cycle = append(cycle, u)
+	}
+	start := 0
+	for i := range cycle {
+		if g.lessID(cycle[i], cycle[start]) {
+			start = i
+		}
+	}
+	// cycle runs against the edges; reverse it and rotate to start.
+	n := len(cycle)
+	res.NegativeCycle = make([]string, n)
+	for i := range cycle {
+		res.NegativeCycle[i] = cycle[(start-i+n)%n]
+	}
+	return res, ErrNegativeCycle
+}
+
+// ConstrainedShortestPath finds the cheapest path from src to dst using only
+// nodes accepted by allowNode and edges accepted by allowEdge. A nil predicate
+// allows everything. src and dst must themselves be allowed.
+func ConstrainedShortestPath[N, E any](g *Graph[N, E], src, dst string, allowNode func(Node[N]) bool, allowEdge func(Edge[E]) bool) ([]string, float64, error) {
+	srcNode, ok := g.GetNode(src)
+	if !ok {
+		return nil, 0, errors.New("source node not found")
+	}
+	dstNode, ok := g.GetNode(dst)
+	if !ok {
+		return nil, 0, errors.New("destination node not found")
+	}
+	if allowNode != nil && !allowNode(srcNode) {
+		return nil, 0, errors.New("source node not allowed")
+	}
+	if allowNode != nil && !allowNode(dstNode) {
+		return nil, 0, errors.New("destination node not allowed")
+	}
+
+	dist := map[string]float64{src: 0}
+	prev := map[string]string{}
+	h := &dijkstraHeap{{id: src, dist: 0}}
+
+	for h.Len() > 0 {
+		cur := heap.Pop(h).(dijkstraItem)
+		if cur.dist > dist[cur.id] {
+			continue
+		}
+		if cur.id == dst {
+			break
+		}
+		for _, e := range g.OutEdges(cur.id) {
+			if allowEdge != nil && !allowEdge(e) {
+				continue
+			}
+			if allowNode != nil {
+				if n, _ := g.GetNode(e.To); !allowNode(n) {
+					continue
+				}
+			}
+			nd := cur.dist + e.Weight
+			if d, ok := dist[e.To]; !ok || nd < d {
+				dist[e.To] = nd
+				prev[e.To] = cur.id
+				heap.Push(h, dijkstraItem{id: e.To, dist: nd})
+			}
+		}
+	}
+
+	if _, ok := dist[dst]; !ok {
+		return nil, 0, errors.New("no path found")
+	}
+
+	var path []string
+	for cur := dst; cur != ""; cur = prev[cur] {
+		path = append(path, cur)
+		if cur == src {
+			break
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, dist[dst], nil
+}
+
+// AStar computes the shortest weighted path from src to dst using A* search.
+// heuristic estimates the remaining cost from a node to dst; it must never
+// overestimate for the result to be optimal, and a nil heuristic makes AStar
+// equivalent to ShortestPath. Returns the path and its total cost, or an
+// error if src or dst don't exist or no path exists.
+func AStar[N, E any](g *Graph[N, E], src, dst string, heuristic func(id string) float64) ([]string, float64, error) {
+	if !g.HasNode(src) {
+		return nil, 0, errors.New("source node not found")
+	}
+	if !g.HasNode(dst) {
+		return nil, 0, errors.New("destination node not found")
+	}
+	if heuristic == nil {
+		heuristic = func(string) float64 { return 0 }
+	}
+
+	dist := map[string]float64{src: 0}
+	prev := map[string]string{}
+	closed := map[string]bool{}
+	h := &dijkstraHeap{{id: src, dist: heuristic(src)}}
+
+	for h.Len() > 0 {
+		cur := heap.Pop(h).(dijkstraItem)
+		if closed[cur.id] {
+			continue
+		}
+		if cur.id == dst {
+			break
+		}
+		closed[cur.id] = true
+		for _, e := range g.OutEdges(cur.id) {
+			nd := dist[cur.id] + e.Weight
+			if d, ok := dist[e.To]; !ok || nd < d {
+				dist[e.To] = nd
+				prev[e.To] = cur.id
+				// A better route reopens a node closed under an inconsistent heuristic.
+				delete(closed, e.To)
+				heap.Push(h, dijkstraItem{id: e.To, dist: nd + heuristic(e.To)})
+			}
+		}
+	}
+
+	if _, ok := dist[dst]; !ok {
+		return nil, 0, errors.New("no path found")
+	}
+
+	var path []string
+	for cur := dst; cur != ""; cur = prev[cur] {
+		path = append(path, cur)
+		if cur == src {
+			break
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, dist[dst], nil
+}
+
 type dijkstraItem struct {
 	id   string
 	dist float64
@@ -157,7 +654,7 @@ func TopologicalSort[N, E any](g *Graph[N, E]) ([]string, error) {
 			queue = append(queue, id)
 		}
 	}
-	sort.Strings(queue)
+	g.sortIDs(queue)
 
 	var order []string
 	for len(queue) > 0 {
@@ -168,7 +665,7 @@ func TopologicalSort[N, E any](g *Graph[N, E]) ([]string, error) {
 		for _, nb := range neighbors {
 			inDeg[nb]--
 			if inDeg[nb] == 0 {
-				idx := sort.SearchStrings(queue, nb)
+				idx := sort.Search(len(queue), func(i int) bool { return !g.lessID(queue[i], nb) })
 				queue = append(queue, "")
 				copy(queue[idx+1:], queue[idx:])
 				queue[idx] = nb
@@ -182,6 +679,40 @@ func TopologicalSort[N, E any](g *Graph[N, E]) ([]string, error) {
 	return order, nil
 }
 
+// TopologicalGenerations groups the nodes of a DAG into dependency levels:
+// roots are in generation 0 and every other node is one generation after its
+// deepest predecessor, so all nodes of a generation can run in parallel once
+// the previous one is done. Each generation is sorted by ID. Returns an error
+// if the graph is not directed or contains a cycle.
+func TopologicalGenerations[N, E any](g *Graph[N, E]) ([][]string, error) {
+	order, err := TopologicalSort(g)
+	if err != nil {
+		return nil, err
+	}
+	depth := make(map[string]int, len(order))
+	var levels [][]string
+	for _, id := range order {
+		d := 0
+		for _, e := range g.InEdges(id) {
+			d = max(d, depth[e.From]+1)
+		}
+		depth[id] = d
+		for len(levels) <= d {
+			levels = append(levels, nil)
+		}
+		levels[d] = append(levels[d], id)
+	}
+	for _, level := range levels {
+		g.sortIDs(level)
+	}
+	return levels, nil
+}
+
+// Levels is the same as TopologicalGenerations.
+func Levels[N, E any](g *Graph[N, E]) ([][]string, error) {
+	return TopologicalGenerations(g)
+}
+
 // CycleDetect checks if a directed graph contains a cycle.
 // Returns true and one cycle path if a cycle exists, false and nil otherwise.
 // For undirected graphs it always returns false.
@@ -250,10 +781,44 @@ func CycleDetect[N, E any](g *Graph[N, E]) (bool, []string) {
 	return false, nil
 }
 
+// NeighborsWithin returns the IDs of the nodes within radius hops of id,
+// following edges in either direction, sorted and excluding id itself.
+// Returns nil if id does not exist or radius is less than 1.
+func NeighborsWithin[N, E any](g *Graph[N, E], id string, radius int) []string {
+	if !g.HasNode(id) || radius < 1 {
+		return nil
+	}
+	seen := map[string]bool{id: true}
+	frontier := []string{id}
+	var result []string
+	for hop := 0; hop < radius && len(frontier) > 0; hop++ {
+		var next []string
+		for _, cur := range frontier {
+			for _, e := range g.OutEdges(cur) {
+				if !seen[e.To] {
+					seen[e.To] = true
+					next = append(next, e.To)
+				}
+			}
+			for _, e := range g.InEdges(cur) {
+				if !seen[e.From] {
+					seen[e.From] = true
+					next = append(next, e.From)
+				}
+			}
+		}
+		result = append(result, next...)
+		frontier = next
+	}
+	g.sortIDs(result)
+	return result
+}
+
 // Subgraph extracts a new graph containing only the specified node IDs
 // and edges between them.
 func Subgraph[N, E any](g *Graph[N, E], ids []string) *Graph[N, E] {
 	sub := NewGraph[N, E](g.Directed)
+	sub.idLess = g.idLess
 	idSet := make(map[string]bool, len(ids))
 	for _, id := range ids {
 		idSet[id] = true
@@ -286,6 +851,19 @@ func Subgraph[N, E any](g *Graph[N, E], ids []string) *Graph[N, E] {
 			}
 		}
 	}
+	for from, m := range g.dirEdgeMeta {
+		for to, store := range m {
+			if idSet[from] && idSet[to] && sub.HasEdge(from, to) {
+				if sub.dirEdgeMeta == nil {
+					sub.dirEdgeMeta = make(map[string]map[string]*Store)
+				}
+				if sub.dirEdgeMeta[from] == nil {
+					sub.dirEdgeMeta[from] = make(map[string]*Store)
+				}
+				sub.dirEdgeMeta[from][to] = store.Copy()
+			}
+		}
+	}
 	return sub
 }
 
@@ -339,7 +917,7 @@ func StronglyConnectedComponents[N, E any](g *Graph[N, E]) [][]string {
 					break
 				}
 			}
-			sort.Strings(comp)
+			g.sortIDs(comp)
 			components = append(components, comp)
 		}
 	}
@@ -352,57 +930,50 @@ func StronglyConnectedComponents[N, E any](g *Graph[N, E]) [][]string {
 
 	// Sort components by first element for deterministic output.
 	sort.Slice(components, func(i, j int) bool {
-		return components[i][0] < components[j][0]
+		return g.lessID(components[i][0], components[j][0])
 	})
 	return components
 }
 
-// unionFind implements a disjoint-set data structure for Kruskal's algorithm.
-type unionFind struct {
-	parent map[string]string
-	rank   map[string]int
-}
-
-func newUnionFind(ids []string) *unionFind {
-	uf := &unionFind{
-		parent: make(map[string]string, len(ids)),
-		rank:   make(map[string]int, len(ids)),
-	}
-	for _, id := range ids {
-		uf.parent[id] = id
-	}
-	return uf
-}
-
-func (uf *unionFind) find(x string) string {
-	for uf.parent[x] != x {
-		uf.parent[x] = uf.parent[uf.parent[x]] // path compression
-		x = uf.parent[x]
-	}
-	return x
-}
-
-func (uf *unionFind) union(x, y string) bool {
-	rx, ry := uf.find(x), uf.find(y)
-	if rx == ry {
-		return false
-	}
-	if uf.rank[rx] < uf.rank[ry] {
-		rx, ry = ry, rx
+// Condensation collapses each strongly connected component of a directed
+// graph into a single node, yielding a DAG. Each component node is named
+// after its first member (in ID order) and holds the sorted member IDs.
+// Edges between components keep the data and weight of the cheapest original
+// edge between them; edges inside a component are dropped. The returned map
+// gives the component node ID for every original node.
+func Condensation[N, E any](g *Graph[N, E]) (*Graph[[]string, E], map[string]string) {
+	dag := NewGraph[[]string, E](true)
+	dag.idLess = g.idLess
+	comp := make(map[string]string, g.Order())
+	for _, members := range StronglyConnectedComponents(g) {
+		dag.AddNode(members[0], members)
+		for _, id := range members {
+			comp[id] = members[0]
+		}
 	}
-	uf.parent[ry] = rx
-	if uf.rank[rx] == uf.rank[ry] {
-		uf.rank[rx]++
+	for _, n := range g.Nodes() {
+		for _, e := range g.OutEdges(n.ID) {
+			from, to := comp[e.From], comp[e.To]
+			if from == to {
+				continue
+			}
+			if cur, ok := dag.GetEdge(from, to); ok && cur.Weight <= e.Weight {
+				continue
+			}
+			_ = dag.AddEdge(from, to, e.Data, e.Weight)
+		}
 	}
-	return true
+	return dag, comp
 }
 
 // MinimumSpanningTree computes a minimum spanning tree (or forest) of an
 // undirected graph using Kruskal's algorithm. Returns the selected edges,
-// the total weight, and an error if the graph is directed.
-func MinimumSpanningTree[N, E any](g *Graph[N, E]) ([]Edge[E], float64, error) {
+// the total weight, the number of connected components spanned, and an error
+// if the graph is directed. A component count above 1 means the result is a
+// forest with Order() - components edges.
+func MinimumSpanningTree[N, E any](g *Graph[N, E]) ([]Edge[E], float64, int, error) {
 	if g.Directed {
-		return nil, 0, errors.New("minimum spanning tree requires an undirected graph")
+		return nil, 0, 0, errors.New("minimum spanning tree requires an undirected graph")
 	}
 
 	edges := g.Edges()
@@ -421,17 +992,17 @@ func MinimumSpanningTree[N, E any](g *Graph[N, E]) ([]Edge[E], float64, error) {
 	for i, n := range nodes {
 		ids[i] = n.ID
 	}
-	uf := newUnionFind(ids)
+	ds := NewDisjointSet(ids...)
 
 	var mst []Edge[E]
 	totalWeight := 0.0
 	for _, e := range edges {
-		if uf.union(e.From, e.To) {
+		if ds.Union(e.From, e.To) {
 			mst = append(mst, e)
 			totalWeight += e.Weight
 		}
 	}
-	return mst, totalWeight, nil
+	return mst, totalWeight, ds.Count(), nil
 }
 
 // AllPairsResult holds the result of all-pairs shortest paths (Floyd-Warshall).
@@ -440,8 +1011,10 @@ type AllPairsResult struct {
 	Next map[string]map[string]string  `json:"next"`
 }
 
-// AllPairsShortestPaths computes shortest paths between all pairs using Floyd-Warshall.
-// Returns error if a negative cycle is detected.
+// AllPairsShortestPaths computes shortest paths between all pairs. Sparse
+// graphs without negative weights run Dijkstra from every node, in
+// O(V·E·log V); otherwise Floyd-Warshall is used, in O(V³). Returns
+// ErrNegativeCycle if a negative cycle is detected.
 func AllPairsShortestPaths[N, E any](g *Graph[N, E]) (*AllPairsResult, error) {
 	nodes := g.Nodes()
 	n := len(nodes)
@@ -449,6 +1022,16 @@ func AllPairsShortestPaths[N, E any](g *Graph[N, E]) (*AllPairsResult, error) {
 	for i, nd := range nodes {
 		ids[i] = nd.ID
 	}
+	if n > 0 && g.Size()*bits.Len(uint(n)) < n*n && !hasNegativeWeight(g) {
+		res := &AllPairsResult{
+			Dist: make(map[string]map[string]float64, n),
+			Next: make(map[string]map[string]string, n),
+		}
+		for _, u := range ids {
+			res.Dist[u], res.Next[u] = dijkstraFirstHops(g, u)
+		}
+		return res, nil
+	}
 
 	dist := make(map[string]map[string]float64, n)
 	next := make(map[string]map[string]string, n)
@@ -492,7 +1075,7 @@ func AllPairsShortestPaths[N, E any](g *Graph[N, E]) (*AllPairsResult, error) {
 	// Check for negative cycles
 	for _, u := range ids {
 		if dist[u][u] < 0 {
-			return nil, errors.New("graph contains a negative cycle")
+			return nil, ErrNegativeCycle
 		}
 	}
 
@@ -509,6 +1092,48 @@ func AllPairsShortestPaths[N, E any](g *Graph[N, E]) (*AllPairsResult, error) {
 	return &AllPairsResult{Dist: dist, Next: next}, nil
 }
 
+// hasNegativeWeight reports whether any edge of g has a negative weight.
+func hasNegativeWeight[N, E any](g *Graph[N, E]) bool {
+	for _, m := range g.out {
+		for _, e := range m {
+			if e.Weight < 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dijkstraFirstHops runs Dijkstra from src and returns the distance to every
+// reachable node and the first hop on the shortest path to it, in the form of
+// one row of AllPairsResult.
+func dijkstraFirstHops[N, E any](g *Graph[N, E], src string) (map[string]float64, map[string]string) {
+	dist := map[string]float64{src: 0}
+	next := map[string]string{}
+	h := &dijkstraHeap{{id: src, dist: 0}}
+	for h.Len() > 0 {
+		cur := heap.Pop(h).(dijkstraItem)
+		if cur.dist > dist[cur.id] {
+			continue
+		}
+		for _, e := range g.OutEdges(cur.id) {
+			nd := cur.dist + e.Weight
+			if d, ok := dist[e.To]; !ok || nd < d {
+				dist[e.To] = nd
+				// The first hop is inherited from the predecessor, which
+				// is settled before any node it improves.
+				if cur.id == src {
+					next[e.To] = e.To
+				} else {
+					next[e.To] = next[cur.id]
+				}
+				heap.Push(h, dijkstraItem{id: e.To, dist: nd})
+			}
+		}
+	}
+	return dist, next
+}
+
 // ReconstructPath reconstructs the shortest path from src to dst using the Next matrix.
 func ReconstructPath(result *AllPairsResult, src, dst string) ([]string, error) {
 	if _, ok := result.Dist[src]; !ok {
@@ -627,7 +1252,20 @@ func CriticalPath[N, E any](g *Graph[N, E]) (*CriticalPathResult, error) {
 
 // ConnectedComponents returns the connected components of the graph
 // as a list of node-ID sets. For directed graphs, this finds weakly connected components.
+// If the graph has component tracking enabled (see Graph.TrackComponents) and
+// the tracked set is up to date, it is read instead of traversing.
 func ConnectedComponents[N, E any](g *Graph[N, E]) [][]string {
+	if ds := g.trackedComponents(); ds != nil && ds.Count() > 0 {
+		// Sets orders IDs lexically; match the untracked order under idLess.
+		sets := ds.Sets()
+		for _, s := range sets {
+			g.sortIDs(s)
+		}
+		sort.Slice(sets, func(i, j int) bool { return g.lessID(sets[i][0], sets[j][0]) })
+		return sets
+	} else if ds != nil {
+		return nil
+	}
 	visited := make(map[string]bool)
 	var components [][]string
 
@@ -660,8 +1298,109 @@ func ConnectedComponents[N, E any](g *Graph[N, E]) [][]string {
 				}
 			}
 		}
-		sort.Strings(comp)
+		g.sortIDs(comp)
 		components = append(components, comp)
 	}
 	return components
 }
+
+// TopComponents returns the n largest weakly connected components, sorted
+// by size descending. Ties are broken by the smallest node ID in each
+// component. A non-positive n returns all components in that order.
+func TopComponents[N, E any](g *Graph[N, E], n int) [][]string {
+	comps := ConnectedComponents(g)
+	sort.Slice(comps, func(i, j int) bool {
+		if len(comps[i]) != len(comps[j]) {
+			return len(comps[i]) > len(comps[j])
+		}
+		return g.lessID(comps[i][0], comps[j][0])
+	})
+	if n > 0 && n < len(comps) {
+		comps = comps[:n]
+	}
+	return comps
+}
+
+// CutEdges returns the edges whose removal increases the number of weakly
+// connected components (bridges of the undirected view), as [from, to] pairs
+// sorted by from then to. In a directed graph, a pair of opposing edges a->b
+// and b->a counts as two parallel links, so neither is a cut edge. Undirected
+// edges are reported with from <= to.
+func CutEdges[N, E any](g *Graph[N, E]) [][2]string {
+	// Undirected view with link multiplicities; self-loops never disconnect.
+	adj := make(map[string]map[string]int)
+	for _, n := range g.Nodes() {
+		adj[n.ID] = make(map[string]int)
+	}
+	for _, e := range g.Edges() {
+		if e.From == e.To {
+			continue
+		}
+		adj[e.From][e.To]++
+		adj[e.To][e.From]++
+	}
+
+	disc := make(map[string]int)
+	low := make(map[string]int)
+	timer := 0
+	var bridges [][2]string
+
+	var visit func(u, parent string)
+	visit = func(u, parent string) {
+		timer++
+		disc[u] = timer
+		low[u] = timer
+		nbs := make([]string, 0, len(adj[u]))
+		for v := range adj[u] {
+			nbs = append(nbs, v)
+		}
+		sort.Strings(nbs)
+		for _, v := range nbs {
+			if v == parent {
+				// A parallel link back to the parent is a genuine back edge.
+				if adj[u][v] > 1 && disc[v] < low[u] {
+					low[u] = disc[v]
+				}
+				continue
+			}
+			if _, seen := disc[v]; seen {
+				if disc[v] < low[u] {
+					low[u] = disc[v]
+				}
+				continue
+			}
+			visit(v, u)
+			if low[v] < low[u] {
+				low[u] = low[v]
+			}
+			if low[v] > disc[u] {
+				bridges = append(bridges, [2]string{u, v})
+			}
+		}
+	}
+	for _, n := range g.Nodes() {
+		if _, seen := disc[n.ID]; !seen {
+			visit(n.ID, "")
+		}
+	}
+
+	result := make([][2]string, 0, len(bridges))
+	for _, b := range bridges {
+		from, to := b[0], b[1]
+		if g.Directed {
+			if !g.HasEdge(from, to) {
+				from, to = to, from
+			}
+		} else if to < from {
+			from, to = to, from
+		}
+		result = append(result, [2]string{from, to})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i][0] != result[j][0] {
+			return g.lessID(result[i][0], result[j][0])
+		}
+		return g.lessID(result[i][1], result[j][1])
+	})
+	return result
+}
@@ -1,6 +1,10 @@
 package spine
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
 	"testing"
 )
 
@@ -49,6 +53,61 @@ func TestBFSMissingStart(t *testing.T) {
 	}
 }
 
+func TestBFSEdgesAccumulatesWeights(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", 0, 2)
+	g.AddEdge("a", "c", 0, 5)
+	g.AddEdge("b", "d", 0, 3)
+
+	cost := map[string]float64{}
+	order := BFSEdges(g, "a", func(from string, e Edge[int], to Node[string]) bool {
+		if from == "" {
+			if e.From != "" || e.To != "" || e.Weight != 0 {
+				t.Errorf("expected zero edge for start, got %+v", e)
+			}
+			cost[to.ID] = 0
+			return true
+		}
+		if e.From != from || e.To != to.ID {
+			t.Errorf("edge %+v does not match %s -> %s", e, from, to.ID)
+		}
+		cost[to.ID] = cost[from] + e.Weight
+		return true
+	})
+
+	if len(order) != 4 || order[0] != "a" {
+		t.Fatalf("unexpected order: %v", order)
+	}
+	want := map[string]float64{"a": 0, "b": 2, "c": 5, "d": 5}
+	for id, w := range want {
+		if cost[id] != w {
+			t.Errorf("cost[%s] = %v, want %v", id, cost[id], w)
+		}
+	}
+}
+
+func TestBFSEdgesEarlyStop(t *testing.T) {
+	g := NewGraph[string, int](true)
+	g.AddNode("a", "a")
+	g.AddNode("b", "b")
+	g.AddNode("c", "c")
+	g.AddEdge("a", "b", 0, 1)
+	g.AddEdge("b", "c", 0, 1)
+
+	order := BFSEdges(g, "a", func(_ string, _ Edge[int], to Node[string]) bool {
+		return to.ID != "b"
+	})
+	if indexOf(order, "c") != -1 {
+		t.Fatalf("should not visit c: %v", order)
+	}
+	if BFSEdges[string, int](g, "missing", nil) != nil {
+		t.Fatal("expected nil for missing start")
+	}
+}
+
 func TestDFS(t *testing.T) {
 	g := NewGraph[string, int](true)
 	for _, id := range []string{"a", "b", "c", "d"} {
@@ -80,6 +139,42 @@ func TestDFSEarlyStop(t *testing.T) {
 	}
 }
 
+func TestTraverseMaxNodes(t *testing.T) {
+	// A star: hub with 10 leaves, each leaf with one child.
+	g := NewGraph[string, int](true)
+	g.AddNode("hub", "hub")
+	for i := 0; i < 10; i++ {
+		leaf := string(rune('a' + i))
+		g.AddEdgeAuto("hub", leaf, 0, 1, leaf)
+		g.AddEdgeAuto(leaf, leaf+"2", 0, 1, leaf+"2")
+	}
+
+	for name, traverse := range map[string]func(*Graph[string, int], string, func(Node[string]) bool, TraverseOptions) ([]string, bool){
+		"bfs": BFSWithOptions[string, int],
+		"dfs": DFSWithOptions[string, int],
+	} {
+		order, truncated := traverse(g, "hub", nil, TraverseOptions{MaxNodes: 5})
+		if len(order) != 5 || !truncated {
+			t.Errorf("%s: expected 5 nodes and truncation, got %v (truncated=%v)", name, order, truncated)
+		}
+
+		order, truncated = traverse(g, "hub", nil, TraverseOptions{MaxNodes: 21})
+		if len(order) != 21 || truncated {
+			t.Errorf("%s: expected all 21 nodes without truncation, got %d (truncated=%v)", name, len(order), truncated)
+		}
+
+		order, truncated = traverse(g, "hub", nil, TraverseOptions{})
+		if len(order) != 21 || truncated {
+			t.Errorf("%s: expected uncapped traversal, got %d (truncated=%v)", name, len(order), truncated)
+		}
+
+		order, truncated = traverse(g, "hub", func(n Node[string]) bool { return n.ID == "hub" }, TraverseOptions{MaxNodes: 5})
+		if truncated {
+			t.Errorf("%s: stopping via the visitor should not report truncation (%v)", name, order)
+		}
+	}
+}
+
 func TestShortestPath(t *testing.T) {
 	g := NewGraph[string, string](true)
 	for _, id := range []string{"a", "b", "c", "d"} {
@@ -103,6 +198,169 @@ func TestShortestPath(t *testing.T) {
 	}
 }
 
+func TestAStar(t *testing.T) {
+	// A 10x10 grid with unit weights; node data holds the coordinates.
+	g := NewGraph[[2]int, string](false)
+	id := func(x, y int) string { return fmt.Sprintf("%d,%d", x, y) }
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			g.AddNode(id(x, y), [2]int{x, y})
+			if x > 0 {
+				g.AddEdge(id(x-1, y), id(x, y), "", 1)
+			}
+			if y > 0 {
+				g.AddEdge(id(x, y-1), id(x, y), "", 1)
+			}
+		}
+	}
+	dst := id(0, 9)
+	goal, _ := g.GetNode(dst)
+	visited := make(map[string]bool)
+	manhattan := func(n string) float64 {
+		visited[n] = true
+		p, _ := g.GetNode(n)
+		dx, dy := p.Data[0]-goal.Data[0], p.Data[1]-goal.Data[1]
+		return math.Abs(float64(dx)) + math.Abs(float64(dy))
+	}
+
+	path, cost, err := AStar(g, id(0, 0), dst, manhattan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != 9 || len(path) != 10 || path[0] != "0,0" || path[9] != dst {
+		t.Fatalf("expected a 9-step path, got cost %v path %v", cost, path)
+	}
+	if len(visited) >= g.Order()/2 {
+		t.Errorf("expected the heuristic to prune the search, evaluated %d nodes", len(visited))
+	}
+
+	// Without a heuristic it matches Dijkstra.
+	_, want, _ := ShortestPath(g, "3,4", "8,1")
+	if _, got, _ := AStar(g, "3,4", "8,1", nil); got != want {
+		t.Errorf("expected cost %v, got %v", want, got)
+	}
+
+	g.AddNode("island", [2]int{20, 20})
+	if _, _, err := AStar(g, "0,0", "island", manhattan); err == nil {
+		t.Error("expected error for unreachable node")
+	}
+	if _, _, err := AStar(g, "missing", dst, nil); err == nil {
+		t.Error("expected error for missing source")
+	}
+}
+
+func TestShortestPathTree(t *testing.T) {
+	g := NewGraph[string, string](true)
+	for _, id := range []string{"a", "b", "c", "d", "e", "z"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", "", 1)
+	g.AddEdge("b", "d", "", 2)
+	g.AddEdge("a", "c", "", 1)
+	g.AddEdge("c", "d", "", 1)
+	g.AddEdge("d", "e", "", 1)
+	g.AddEdge("e", "a", "", 1) // back edge into the source
+	// z is unreachable
+
+	tree := ShortestPathTree(g, "a")
+	incoming := map[string]int{}
+	for _, e := range tree {
+		incoming[e[1]]++
+	}
+	for _, id := range []string{"b", "c", "d", "e"} {
+		if incoming[id] != 1 {
+			t.Errorf("%s: expected exactly one incoming tree edge, got %d", id, incoming[id])
+		}
+	}
+	if incoming["a"] != 0 || incoming["z"] != 0 {
+		t.Errorf("expected no tree edge into source or unreachable node, got %v", tree)
+	}
+	want := [][2]string{{"a", "b"}, {"a", "c"}, {"c", "d"}, {"d", "e"}}
+	if len(tree) != len(want) {
+		t.Fatalf("expected %v, got %v", want, tree)
+	}
+	for i := range want {
+		if tree[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, tree)
+		}
+	}
+	if ShortestPathTree(g, "missing") != nil {
+		t.Error("expected nil for missing source")
+	}
+}
+
+func TestConstrainedShortestPath(t *testing.T) {
+	g := NewGraph[string, string](true)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(id, "secure")
+	}
+	g.AddNode("c", "open")
+	g.AddEdge("a", "b", "", 1)
+	g.AddEdge("b", "d", "", 2)
+	g.AddEdge("a", "c", "", 1)
+	g.AddEdge("c", "d", "", 1)
+
+	secure := func(n Node[string]) bool { return n.Data == "secure" }
+	path, cost, err := ConstrainedShortestPath(g, "a", "d", secure, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The cheaper route through c is excluded.
+	if cost != 3 || len(path) != 3 || path[1] != "b" {
+		t.Fatalf("expected [a b d] cost 3, got %v cost %f", path, cost)
+	}
+
+	noAB := func(e Edge[string]) bool { return e.From != "a" || e.To != "b" }
+	if _, _, err := ConstrainedShortestPath(g, "a", "d", secure, noAB); err == nil {
+		t.Error("expected no path when both routes are excluded")
+	}
+	if _, _, err := ConstrainedShortestPath(g, "c", "d", secure, nil); err == nil {
+		t.Error("expected error for disallowed source")
+	}
+}
+
+func TestShortestPathWithOptions(t *testing.T) {
+	g := NewGraph[string, string](false)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", "ok", 1)
+	g.AddEdge("b", "d", "ok", 1)
+	g.AddEdge("a", "c", "ok", 2)
+	g.AddEdge("c", "d", "ok", 2)
+	g.AddEdge("a", "e", "ok", 3)
+	g.AddEdge("e", "d", "failed", 3)
+
+	path, cost, err := ShortestPathWithOptions(g, "a", "d", ShortestPathOpts[string]{AvoidNodes: []string{"b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != 4 || len(path) != 3 || path[1] != "c" {
+		t.Fatalf("expected [a c d] cost 4, got %v cost %f", path, cost)
+	}
+
+	// Edges are avoided in both directions on undirected graphs.
+	opts := ShortestPathOpts[string]{
+		AvoidEdges: [][2]string{{"b", "a"}, {"d", "c"}},
+		EdgeFilter: func(e Edge[string]) bool { return e.Data != "failed" },
+	}
+	if _, _, err := ShortestPathWithOptions(g, "a", "d", opts); err == nil {
+		t.Error("expected no path when every route is excluded")
+	}
+
+	opts.EdgeFilter = nil
+	if path, cost, err := ShortestPathWithOptions(g, "a", "d", opts); err != nil || cost != 6 || path[1] != "e" {
+		t.Errorf("expected [a e d] cost 6, got %v cost %f err %v", path, cost, err)
+	}
+	opts.MaxCost = 5
+	if _, _, err := ShortestPathWithOptions(g, "a", "d", opts); err == nil {
+		t.Error("expected no path within the cost limit")
+	}
+	if _, _, err := ShortestPathWithOptions(g, "a", "d", ShortestPathOpts[string]{AvoidNodes: []string{"d"}}); err == nil {
+		t.Error("expected error for avoided destination")
+	}
+}
+
 func TestShortestPathNoPath(t *testing.T) {
 	g := NewGraph[int, int](true)
 	g.AddNode("a", 1)
@@ -169,6 +427,36 @@ func TestTopologicalSortUndirected(t *testing.T) {
 	}
 }
 
+func TestTopologicalGenerations(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", 0, 1)
+	g.AddEdge("a", "c", 0, 1)
+	g.AddEdge("b", "d", 0, 1)
+	g.AddEdge("c", "d", 0, 1)
+	// The shortcut a -> d must not pull d up a level.
+	g.AddEdge("a", "d", 0, 1)
+
+	levels, err := TopologicalGenerations(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"a", "e"}, {"b", "c"}, {"d"}}
+	if !reflect.DeepEqual(levels, want) {
+		t.Errorf("expected %v, got %v", want, levels)
+	}
+	if alias, _ := Levels(g); !reflect.DeepEqual(alias, want) {
+		t.Errorf("expected Levels to match, got %v", alias)
+	}
+
+	g.AddEdge("d", "a", 0, 1)
+	if _, err := TopologicalGenerations(g); err == nil {
+		t.Error("expected error for cyclic graph")
+	}
+}
+
 func TestCycleDetect(t *testing.T) {
 	g := NewGraph[int, int](true)
 	g.AddNode("a", 1)
@@ -268,6 +556,30 @@ func TestSubgraph(t *testing.T) {
 	}
 }
 
+func TestNeighborsWithin(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", 0, 1)
+	g.AddEdge("c", "b", 0, 1)
+	g.AddEdge("c", "d", 0, 1)
+	g.AddEdge("d", "e", 0, 1)
+
+	if got := NeighborsWithin(g, "b", 1); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("radius 1: expected [a c], got %v", got)
+	}
+	if got := NeighborsWithin(g, "b", 2); !reflect.DeepEqual(got, []string{"a", "c", "d"}) {
+		t.Errorf("radius 2: expected [a c d], got %v", got)
+	}
+	if got := NeighborsWithin(g, "b", 0); got != nil {
+		t.Errorf("radius 0: expected nil, got %v", got)
+	}
+	if got := NeighborsWithin(g, "missing", 1); got != nil {
+		t.Errorf("missing node: expected nil, got %v", got)
+	}
+}
+
 func TestConnectedComponents(t *testing.T) {
 	g := NewGraph[int, int](false)
 	g.AddNode("a", 1)
@@ -283,6 +595,26 @@ func TestConnectedComponents(t *testing.T) {
 	}
 }
 
+func TestConnectedComponentsTrackedOrder(t *testing.T) {
+	g := NewGraph[int, int](false)
+	g.SetIDLess(NaturalIDLess)
+	for _, id := range []string{"v10", "v9", "v2", "b", "a"} {
+		g.AddNode(id, 0)
+	}
+	g.AddEdge("v10", "v2", 0, 1)
+	g.AddEdge("v2", "v9", 0, 1)
+	g.AddEdge("a", "b", 0, 1)
+
+	untracked := ConnectedComponents(g)
+	g.TrackComponents(true)
+	if tracked := ConnectedComponents(g); fmt.Sprint(tracked) != fmt.Sprint(untracked) {
+		t.Errorf("expected tracked order %v to match %v", tracked, untracked)
+	}
+	if got := fmt.Sprint(untracked); got != "[[a b] [v2 v9 v10]]" {
+		t.Errorf("unexpected components %s", got)
+	}
+}
+
 func TestConnectedComponentsDirected(t *testing.T) {
 	g := NewGraph[int, int](true)
 	g.AddNode("a", 1)
@@ -307,6 +639,152 @@ func TestConnectedComponentsSingleNode(t *testing.T) {
 	}
 }
 
+func TestNearestMatching(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for id, status := range map[string]string{
+		"a": "running", "b": "pending", "c": "done", "d": "pending", "e": "done",
+	} {
+		g.AddNode(id, status)
+	}
+	g.AddEdge("a", "b", 0, 1)
+	g.AddEdge("a", "d", 0, 1)
+	g.AddEdge("b", "e", 0, 1)
+	g.AddEdge("d", "c", 0, 1)
+	isDone := func(n Node[string]) bool { return n.Data == "done" }
+
+	path, ok := NearestMatching(g, "a", isDone)
+	if !ok || !reflect.DeepEqual(path, []string{"a", "b", "e"}) {
+		t.Errorf("expected [a b e], got %v %v", path, ok)
+	}
+
+	path, ok = NearestMatching(g, "c", isDone)
+	if !ok || !reflect.DeepEqual(path, []string{"c"}) {
+		t.Errorf("expected matching start to return [c], got %v %v", path, ok)
+	}
+
+	if _, ok := NearestMatching(g, "a", func(n Node[string]) bool { return n.Data == "failed" }); ok {
+		t.Error("expected no match")
+	}
+	if _, ok := NearestMatching(g, "missing", isDone); ok {
+		t.Error("expected no match for missing start")
+	}
+}
+
+func TestShortestPathMaxHops(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		g.AddNode(id, id)
+	}
+	// Cheapest route a->b->c->d->e costs 4 over 4 hops;
+	// a->e direct costs 10, a->c->e costs 7 over 2 hops.
+	g.AddEdge("a", "b", 0, 1)
+	g.AddEdge("b", "c", 0, 1)
+	g.AddEdge("c", "d", 0, 1)
+	g.AddEdge("d", "e", 0, 1)
+	g.AddEdge("a", "c", 0, 4)
+	g.AddEdge("c", "e", 0, 5)
+	g.AddEdge("a", "e", 0, 10)
+
+	tests := []struct {
+		hops int
+		path []string
+		cost float64
+	}{
+		{4, []string{"a", "b", "c", "d", "e"}, 4},
+		{3, []string{"a", "c", "d", "e"}, 6},
+		{2, []string{"a", "c", "e"}, 9},
+		{1, []string{"a", "e"}, 10},
+	}
+	for _, tt := range tests {
+		path, cost, err := ShortestPathMaxHops(g, "a", "e", tt.hops)
+		if err != nil {
+			t.Fatalf("hops=%d: %v", tt.hops, err)
+		}
+		if !reflect.DeepEqual(path, tt.path) || cost != tt.cost {
+			t.Errorf("hops=%d: expected %v (%v), got %v (%v)", tt.hops, tt.path, tt.cost, path, cost)
+		}
+	}
+
+	if _, _, err := ShortestPathMaxHops(g, "a", "e", 0); err == nil {
+		t.Error("expected no path within 0 hops")
+	}
+	if path, cost, err := ShortestPathMaxHops(g, "a", "a", 0); err != nil || len(path) != 1 || cost != 0 {
+		t.Errorf("expected trivial path to self, got %v %v %v", path, cost, err)
+	}
+	if _, _, err := ShortestPathMaxHops(g, "a", "missing", 3); err == nil {
+		t.Error("expected error for missing destination")
+	}
+}
+
+func TestTrackComponentsMatchesFromScratch(t *testing.T) {
+	tracked := NewGraph[int, int](true)
+	tracked.TrackComponents(true)
+	plain := NewGraph[int, int](true)
+
+	steps := []struct{ from, to string }{
+		{"a", "b"}, {"c", "d"}, {"e", ""}, {"b", "c"}, {"f", "g"}, {"g", "f"}, {"h", ""},
+	}
+	for _, st := range steps {
+		for _, g := range []*Graph[int, int]{tracked, plain} {
+			g.AddNode(st.from, 0)
+			if st.to != "" {
+				g.AddNode(st.to, 0)
+				g.AddEdge(st.from, st.to, 0, 1)
+			}
+		}
+		got, want := ConnectedComponents(tracked), ConnectedComponents(plain)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("after %v: tracked %v, from scratch %v", st, got, want)
+		}
+	}
+
+	// Removals trigger a rebuild.
+	tracked.RemoveEdge("b", "c")
+	plain.RemoveEdge("b", "c")
+	tracked.RemoveNode("g")
+	plain.RemoveNode("g")
+	if got, want := ConnectedComponents(tracked), ConnectedComponents(plain); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after removals: tracked %v, from scratch %v", got, want)
+	}
+
+	// Copies keep tracking, and disabling falls back to traversal.
+	if got := ConnectedComponents(tracked.Copy()); len(got) != 5 {
+		t.Errorf("expected 5 components on copy, got %v", got)
+	}
+	tracked.TrackComponents(false)
+	if got := ConnectedComponents(tracked); len(got) != 5 {
+		t.Errorf("expected 5 components untracked, got %v", got)
+	}
+}
+
+func TestTopComponents(t *testing.T) {
+	g := NewGraph[int, int](false)
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		g.AddNode(id, 0)
+	}
+	// Sizes: {e,f,g,h}=4, {a,b}=2, {c}=1, {d}=1.
+	g.AddEdge("e", "f", 0, 1)
+	g.AddEdge("f", "g", 0, 1)
+	g.AddEdge("g", "h", 0, 1)
+	g.AddEdge("a", "b", 0, 1)
+
+	top := TopComponents(g, 2)
+	if len(top) != 2 {
+		t.Fatalf("expected 2 components, got %v", top)
+	}
+	if len(top[0]) != 4 || top[0][0] != "e" {
+		t.Errorf("expected size-4 component first, got %v", top[0])
+	}
+	if len(top[1]) != 2 || top[1][0] != "a" {
+		t.Errorf("expected size-2 component second, got %v", top[1])
+	}
+
+	all := TopComponents(g, 0)
+	if len(all) != 4 || all[2][0] != "c" || all[3][0] != "d" {
+		t.Errorf("expected singletons ordered by ID, got %v", all)
+	}
+}
+
 func TestSCC(t *testing.T) {
 	// Graph with 2 SCCs: a->b->c->a (cycle) and c->d (bridge to singleton)
 	g := NewGraph[string, int](true)
@@ -341,6 +819,38 @@ func TestSCC(t *testing.T) {
 	}
 }
 
+func TestCondensation(t *testing.T) {
+	// Two cycles {a,b} and {c,d} joined by two edges, plus a tail e.
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", 0, 1)
+	g.AddEdge("b", "a", 0, 1)
+	g.AddEdge("c", "d", 0, 1)
+	g.AddEdge("d", "c", 0, 1)
+	g.AddEdge("a", "c", 1, 5)
+	g.AddEdge("b", "d", 2, 3)
+	g.AddEdge("d", "e", 3, 1)
+
+	dag, comp := Condensation(g)
+	if dag.Order() != 3 || dag.Size() != 2 {
+		t.Fatalf("expected 3 components and 2 edges, got %d and %d", dag.Order(), dag.Size())
+	}
+	if comp["b"] != "a" || comp["d"] != "c" || comp["e"] != "e" {
+		t.Errorf("unexpected component map: %v", comp)
+	}
+	if n, _ := dag.GetNode("c"); !reflect.DeepEqual(n.Data, []string{"c", "d"}) {
+		t.Errorf("expected members [c d], got %v", n.Data)
+	}
+	if e, _ := dag.GetEdge("a", "c"); e.Weight != 3 || e.Data != 2 {
+		t.Errorf("expected the cheapest edge b->d to be kept, got %+v", e)
+	}
+	if order, err := TopologicalSort(dag); err != nil || !reflect.DeepEqual(order, []string{"a", "c", "e"}) {
+		t.Errorf("expected acyclic [a c e], got %v, %v", order, err)
+	}
+}
+
 func TestSCCUndirected(t *testing.T) {
 	// Undirected graph: should fall back to ConnectedComponents
 	g := NewGraph[int, int](false)
@@ -386,7 +896,7 @@ func TestMST(t *testing.T) {
 	g.AddEdge("b", "c", 0, 2)
 	g.AddEdge("a", "c", 0, 3)
 
-	edges, total, err := MinimumSpanningTree(g)
+	edges, total, components, err := MinimumSpanningTree(g)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -396,6 +906,9 @@ func TestMST(t *testing.T) {
 	if total != 3.0 {
 		t.Fatalf("expected total weight 3, got %f", total)
 	}
+	if components != 1 {
+		t.Fatalf("expected a single spanning tree, got %d components", components)
+	}
 }
 
 func TestMSTDirectedError(t *testing.T) {
@@ -404,7 +917,7 @@ func TestMSTDirectedError(t *testing.T) {
 	g.AddNode("b", 2)
 	g.AddEdge("a", "b", 0, 1)
 
-	_, _, err := MinimumSpanningTree(g)
+	_, _, _, err := MinimumSpanningTree(g)
 	if err == nil {
 		t.Fatal("expected error for directed graph")
 	}
@@ -420,7 +933,7 @@ func TestMSTDisconnected(t *testing.T) {
 	g.AddEdge("a", "b", 0, 1)
 	g.AddEdge("c", "d", 0, 2)
 
-	edges, total, err := MinimumSpanningTree(g)
+	edges, total, components, err := MinimumSpanningTree(g)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -430,6 +943,12 @@ func TestMSTDisconnected(t *testing.T) {
 	if total != 3.0 {
 		t.Fatalf("expected total weight 3, got %f", total)
 	}
+	if components != 2 {
+		t.Fatalf("expected 2 components, got %d", components)
+	}
+	if len(edges) != g.Order()-components {
+		t.Fatalf("expected V - components = %d edges, got %d", g.Order()-components, len(edges))
+	}
 }
 
 func TestAllPairsShortestPaths(t *testing.T) {
@@ -491,6 +1010,132 @@ func TestAllPairsNoPath(t *testing.T) {
 	}
 }
 
+func TestAllPairsStrategiesAgree(t *testing.T) {
+	// A sparse directed grid runs repeated Dijkstra; an isolated negative
+	// edge in a copy forces Floyd-Warshall. Both must agree.
+	g := NewGraph[string, int](true)
+	id := func(r, c int) string { return fmt.Sprintf("n%d%d", r, c) }
+	for r := 0; r < 5; r++ {
+		for c := 0; c < 5; c++ {
+			g.AddNode(id(r, c), "")
+		}
+	}
+	for r := 0; r < 5; r++ {
+		for c := 0; c < 5; c++ {
+			if c < 4 {
+				g.AddEdge(id(r, c), id(r, c+1), 0, float64(1+(r+c)%3))
+			}
+			if r < 4 {
+				g.AddEdge(id(r, c), id(r+1, c), 0, float64(1+(r*c)%4))
+			}
+			if r > 0 && c > 0 && (r+c)%2 == 0 {
+				g.AddEdge(id(r, c), id(r-1, c-1), 0, 2)
+			}
+		}
+	}
+	dense := g.Copy()
+	dense.AddNode("x", "")
+	dense.AddNode("y", "")
+	dense.AddEdge("x", "y", 0, -1)
+
+	sparse, err := AllPairsShortestPaths(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, err := AllPairsShortestPaths(dense)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range g.Nodes() {
+		for v, d := range full.Dist[u.ID] {
+			if v == "x" || v == "y" {
+				continue
+			}
+			if sparse.Dist[u.ID][v] != d {
+				t.Fatalf("dist %s->%s: dijkstra %v, floyd-warshall %v", u.ID, v, sparse.Dist[u.ID][v], d)
+			}
+			path, err := ReconstructPath(sparse, u.ID, v)
+			if err != nil {
+				t.Fatalf("path %s->%s: %v", u.ID, v, err)
+			}
+			cost := 0.0
+			for i := 1; i < len(path); i++ {
+				e, _ := g.GetEdge(path[i-1], path[i])
+				cost += e.Weight
+			}
+			if cost != d {
+				t.Fatalf("path %s->%s %v costs %v, want %v", u.ID, v, path, cost, d)
+			}
+		}
+		if len(sparse.Dist[u.ID]) != len(full.Dist[u.ID]) {
+			t.Fatalf("reachable from %s: %d vs %d", u.ID, len(sparse.Dist[u.ID]), len(full.Dist[u.ID]))
+		}
+	}
+}
+
+func TestBellmanFord(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		g.AddNode(id, id)
+	}
+	// A discount on b->c makes a->b->c->d cheaper than a->c->d; e is
+	// unreachable.
+	g.AddEdge("a", "b", 0, 4)
+	g.AddEdge("a", "c", 0, 2)
+	g.AddEdge("b", "c", 0, -3)
+	g.AddEdge("c", "d", 0, 2)
+
+	res, err := BellmanFord(g, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]float64{"a": 0, "b": 4, "c": 1, "d": 3}
+	if !reflect.DeepEqual(res.Dist, want) {
+		t.Errorf("expected distances %v, got %v", want, res.Dist)
+	}
+	if path := res.PathTo("d"); !reflect.DeepEqual(path, []string{"a", "b", "c", "d"}) {
+		t.Errorf("unexpected path to d: %v", path)
+	}
+	if path := res.PathTo("a"); !reflect.DeepEqual(path, []string{"a"}) {
+		t.Errorf("unexpected path to a: %v", path)
+	}
+	if res.PathTo("e") != nil {
+		t.Error("expected no path to unreachable e")
+	}
+
+	// Closing d->b with weight -1 gives the cycle b->c->d->b total -2.
+	g.AddEdge("d", "b", 0, -1)
+	res, err = BellmanFord(g, "a")
+	if !errors.Is(err, ErrNegativeCycle) {
+		t.Fatalf("expected ErrNegativeCycle, got %v", err)
+	}
+	if !reflect.DeepEqual(res.NegativeCycle, []string{"b", "c", "d"}) {
+		t.Errorf("unexpected negative cycle: %v", res.NegativeCycle)
+	}
+	if res.PathTo("d") != nil {
+		t.Error("expected no paths when a negative cycle is present")
+	}
+
+	// The cycle is not reachable from e.
+	if _, err := BellmanFord(g, "e"); err != nil {
+		t.Errorf("expected no error from e, got %v", err)
+	}
+	if _, err := BellmanFord(g, "missing"); err == nil {
+		t.Error("expected error for missing source")
+	}
+}
+
+func TestBellmanFordUndirectedNegativeEdge(t *testing.T) {
+	g := NewGraph[string, int](false)
+	g.AddNode("x", "")
+	g.AddNode("y", "")
+	g.AddEdge("y", "x", 0, -1)
+	res, err := BellmanFord(g, "x")
+	if !errors.Is(err, ErrNegativeCycle) || !reflect.DeepEqual(res.NegativeCycle, []string{"x", "y"}) {
+		t.Errorf("expected negative cycle [x y], got %v, %v", res.NegativeCycle, err)
+	}
+}
+
 func TestCriticalPath(t *testing.T) {
 	// DAG: a->b(3), a->c(2), b->d(1), c->d(4)
 	// Critical path: a->c->d (length 6)
@@ -570,3 +1215,54 @@ func indexOf(s []string, v string) int {
 	}
 	return -1
 }
+
+func TestCutEdgesTwoClusters(t *testing.T) {
+	g := NewGraph[string, int](true)
+	for _, id := range []string{"a", "b", "c", "x", "y", "z"} {
+		g.AddNode(id, id)
+	}
+	// Cluster 1: a -> b -> c -> a
+	g.AddEdge("a", "b", 0, 1)
+	g.AddEdge("b", "c", 0, 1)
+	g.AddEdge("c", "a", 0, 1)
+	// Cluster 2: x -> y -> z -> x
+	g.AddEdge("x", "y", 0, 1)
+	g.AddEdge("y", "z", 0, 1)
+	g.AddEdge("z", "x", 0, 1)
+	// Single link between the clusters.
+	g.AddEdge("y", "c", 0, 1)
+
+	cuts := CutEdges(g)
+	if len(cuts) != 1 || cuts[0] != [2]string{"y", "c"} {
+		t.Fatalf("expected [[y c]], got %v", cuts)
+	}
+}
+
+func TestCutEdgesParallelAndUndirected(t *testing.T) {
+	g := NewGraph[string, int](true)
+	g.AddNode("a", "a")
+	g.AddNode("b", "b")
+	g.AddNode("c", "c")
+	g.AddEdge("a", "b", 0, 1)
+	g.AddEdge("b", "a", 0, 1) // opposing edges: removing one keeps a-b connected
+	g.AddEdge("b", "c", 0, 1)
+
+	cuts := CutEdges(g)
+	if len(cuts) != 1 || cuts[0] != [2]string{"b", "c"} {
+		t.Fatalf("expected [[b c]], got %v", cuts)
+	}
+
+	u := NewGraph[string, int](false)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		u.AddNode(id, id)
+	}
+	u.AddEdge("b", "a", 0, 1)
+	u.AddEdge("c", "b", 0, 1)
+	u.AddEdge("c", "d", 0, 1)
+	u.AddEdge("d", "b", 0, 1)
+
+	cuts = CutEdges(u)
+	if len(cuts) != 1 || cuts[0] != [2]string{"a", "b"} {
+		t.Fatalf("expected [[a b]], got %v", cuts)
+	}
+}
@@ -0,0 +1,63 @@
+package spine
+
+import (
+	"sort"
+	"time"
+)
+
+// Version is one recorded value of a Store key.
+type Version struct {
+	Value   any       `json:"value,omitempty"`
+	Time    time.Time `json:"time"`
+	Deleted bool      `json:"deleted,omitempty"` // the key was deleted at Time
+}
+
+// TrackVersions turns value history on or off. While on, every Set, Delete
+// and Clear appends a timestamped Version for the key, queryable with History
+// and GetAt. Turning it on records the current entries as their first
+// versions; turning it off discards the history. Versions are kept in memory
+// only and are not serialized.
+func (s *Store) TrackVersions(enable bool) {
+	if !enable {
+		s.versions = nil
+		return
+	}
+	if s.versions != nil {
+		return
+	}
+	s.versions = make(map[string][]Version, len(s.entries))
+	for _, k := range s.Keys() {
+		s.recordVersion(k, s.entries[k], false)
+	}
+}
+
+// TracksVersions reports whether value history is on.
+func (s *Store) TracksVersions() bool {
+	return s.versions != nil
+}
+
+func (s *Store) recordVersion(key string, value any, deleted bool) {
+	if s.versions == nil {
+		return
+	}
+	s.versions[key] = append(s.versions[key], Version{Value: value, Time: timeNow(), Deleted: deleted})
+}
+
+// History returns the recorded versions of key, oldest first; the last one is
+// the current value unless the key was deleted. Returns nil if versions are
+// not tracked or key was never recorded.
+func (s *Store) History(key string) []Version {
+	return append([]Version(nil), s.versions[key]...)
+}
+
+// GetAt returns the value key had at time t: the latest version recorded at
+// or before t. Returns false if key did not exist then, was deleted, or
+// versions are not tracked.
+func (s *Store) GetAt(key string, t time.Time) (any, bool) {
+	vs := s.versions[key]
+	i := sort.Search(len(vs), func(i int) bool { return vs[i].Time.After(t) })
+	if i == 0 || vs[i-1].Deleted {
+		return nil, false
+	}
+	return vs[i-1].Value, true
+}
@@ -0,0 +1,69 @@
+package spine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreVersions(t *testing.T) {
+	advance := fakeClock(t)
+	s := NewStore()
+	s.Set("status", "pending")
+	s.Set("owner", "alice")
+	if s.History("status") != nil {
+		t.Fatal("expected no history before tracking is on")
+	}
+
+	s.TrackVersions(true)
+	start := timeNow()
+	advance(time.Minute)
+	s.Set("status", "running")
+	mid := timeNow()
+	advance(time.Minute)
+	s.Set("status", "done")
+	s.Delete("owner")
+
+	h := s.History("status")
+	if len(h) != 3 || h[0].Value != "pending" || h[1].Value != "running" || h[2].Value != "done" {
+		t.Fatalf("unexpected history %+v", h)
+	}
+	if !h[0].Time.Equal(start) || !h[1].Time.Equal(mid) {
+		t.Errorf("unexpected timestamps %+v", h)
+	}
+
+	for _, tc := range []struct {
+		at   time.Time
+		want any
+		ok   bool
+	}{
+		{start.Add(-time.Second), nil, false},
+		{start, "pending", true},
+		{mid.Add(30 * time.Second), "running", true},
+		{timeNow(), "done", true},
+	} {
+		if v, ok := s.GetAt("status", tc.at); v != tc.want || ok != tc.ok {
+			t.Errorf("GetAt(%v): expected %v %v, got %v %v", tc.at, tc.want, tc.ok, v, ok)
+		}
+	}
+	if v, ok := s.GetAt("owner", mid); !ok || v != "alice" {
+		t.Errorf("expected owner alice before the delete, got %v %v", v, ok)
+	}
+	if _, ok := s.GetAt("owner", timeNow()); ok {
+		t.Error("expected owner to be gone after the delete")
+	}
+
+	c := s.Copy()
+	s.Clear()
+	if h := s.History("status"); !h[len(h)-1].Deleted {
+		t.Error("expected Clear to record deletions")
+	}
+	if h := c.History("status"); len(h) != 3 || !c.TracksVersions() {
+		t.Errorf("expected the copy to keep its own history, got %+v", h)
+	}
+
+	s.TrackVersions(false)
+	s.Set("status", "again")
+	if s.History("status") != nil || s.TracksVersions() {
+		t.Error("expected history to be discarded when tracking is off")
+	}
+}
@@ -0,0 +1,138 @@
+package spine
+
+// Direction selects which edges a Walk follows from each node.
+type Direction int
+
+const (
+	// DirOut follows edges leaving a node.
+	DirOut Direction = iota
+	// DirIn follows edges entering a node, walking the graph backwards.
+	DirIn
+	// DirBoth follows edges in either direction.
+	DirBoth
+)
+
+// WalkOrder selects the traversal order of a Walk.
+type WalkOrder int
+
+const (
+	// WalkBFS visits nodes breadth-first.
+	WalkBFS WalkOrder = iota
+	// WalkDFS visits nodes depth-first.
+	WalkDFS
+)
+
+// WalkOptions configures Walk. The zero value is a breadth-first walk over
+// out-edges with no limits, like BFS.
+type WalkOptions[E any] struct {
+	TraverseOptions
+
+	Order     WalkOrder
+	Direction Direction
+	// MaxDepth, if > 0, stops the walk MaxDepth hops away from start.
+	MaxDepth int
+	// EdgeFilter, if set, is called for each edge the walk could follow; only
+	// edges for which it returns true are followed.
+	EdgeFilter func(Edge[E]) bool
+}
+
+// Walk traverses the graph from start as configured by opts and calls visitor
+// for each node reached, with its distance in hops from start along the walk.
+// If visitor returns false, the walk stops early. Returns the visited node
+// IDs in visiting order; truncated reports whether the walk stopped at
+// opts.MaxNodes with reachable nodes left unvisited.
+//
+// Every node within opts.MaxDepth hops of start is visited, in DFS order too:
+// a node first reached by a long path is expanded again if a shorter one is
+// found later, though the visitor is called only once per node.
+func Walk[N, E any](g *Graph[N, E], start string, opts WalkOptions[E], visitor func(n Node[N], depth int) bool) (order []string, truncated bool) {
+	if !g.HasNode(start) {
+		return nil, false
+	}
+	next := func(id string) []string {
+		var ids []string
+		if opts.Direction != DirIn {
+			for _, e := range g.OutEdges(id) {
+				if opts.EdgeFilter == nil || opts.EdgeFilter(e) {
+					ids = append(ids, e.To)
+				}
+			}
+		}
+		if opts.Direction != DirOut {
+			for _, e := range g.InEdges(id) {
+				if opts.EdgeFilter == nil || opts.EdgeFilter(e) {
+					ids = append(ids, e.From)
+				}
+			}
+		}
+		return ids
+	}
+	expand := func(depth int) bool {
+		return opts.MaxDepth <= 0 || depth < opts.MaxDepth
+	}
+
+	if opts.Order == WalkDFS {
+		depth := make(map[string]int)
+		stopped := false
+		var walk func(id string, d int)
+		walk = func(id string, d int) {
+			if stopped {
+				return
+			}
+			if best, seen := depth[id]; seen {
+				if best <= d {
+					return
+				}
+			} else {
+				if opts.MaxNodes > 0 && len(order) >= opts.MaxNodes {
+					stopped, truncated = true, true
+					return
+				}
+				n, _ := g.GetNode(id)
+				order = append(order, id)
+				if visitor != nil && !visitor(n, d) {
+					stopped = true
+					return
+				}
+			}
+			depth[id] = d
+			if !expand(d) {
+				return
+			}
+			for _, nb := range next(id) {
+				walk(nb, d+1)
+			}
+		}
+		walk(start, 0)
+		return order, truncated
+	}
+
+	type item struct {
+		id    string
+		depth int
+	}
+	visited := map[string]bool{start: true}
+	queue := []item{{id: start}}
+	for len(queue) > 0 {
+		if opts.MaxNodes > 0 && len(order) >= opts.MaxNodes {
+			return order, true
+		}
+		cur := queue[0]
+		queue = queue[1:]
+		n, _ := g.GetNode(cur.id)
+		order = append(order, cur.id)
+		if visitor != nil && !visitor(n, cur.depth) {
+			break
+		}
+		if !expand(cur.depth) {
+			continue
+		}
+		for _, nb := range next(cur.id) {
+			if !visited[nb] {
+				visited[nb] = true
+				queue = append(queue, item{id: nb, depth: cur.depth + 1})
+			}
+		}
+	}
+	return order, false
+}
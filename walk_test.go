@@ -0,0 +1,90 @@
+package spine
+
+import (
+	"reflect"
+	"testing"
+)
+
+// walkFixture returns a -> b -> c -> d with a shortcut a -> d, plus x -> b.
+func walkFixture() *Graph[string, string] {
+	g := NewGraph[string, string](true)
+	for _, id := range []string{"a", "b", "c", "d", "x"} {
+		g.AddNode(id, id)
+	}
+	g.AddEdge("a", "b", "", 1)
+	g.AddEdge("b", "c", "", 1)
+	g.AddEdge("c", "d", "", 1)
+	g.AddEdge("a", "d", "skip", 1)
+	g.AddEdge("x", "b", "", 1)
+	return g
+}
+
+func TestWalkDefaultsToBFS(t *testing.T) {
+	g := walkFixture()
+	got, _ := Walk(g, "a", WalkOptions[string]{}, nil)
+	if want := BFS(g, "a", nil); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestWalkMaxDepth(t *testing.T) {
+	g := walkFixture()
+	depths := map[string]int{}
+	visit := func(n Node[string], d int) bool {
+		depths[n.ID] = d
+		return true
+	}
+	got, _ := Walk(g, "a", WalkOptions[string]{MaxDepth: 1}, visit)
+	if want := []string{"a", "b", "d"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if depths["a"] != 0 || depths["b"] != 1 || depths["d"] != 1 {
+		t.Errorf("unexpected depths %v", depths)
+	}
+
+	// DFS reaches d at depth 3 via b and c first, but must expand it again
+	// when the shortcut reaches it at depth 1.
+	g.AddNode("e", "e")
+	g.AddEdge("d", "e", "", 1)
+	got, _ = Walk(g, "a", WalkOptions[string]{Order: WalkDFS, MaxDepth: 3}, nil)
+	if want := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestWalkDirectionAndFilter(t *testing.T) {
+	g := walkFixture()
+	got, _ := Walk(g, "c", WalkOptions[string]{Direction: DirIn}, nil)
+	if want := []string{"c", "b", "a", "x"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected ancestors %v, got %v", want, got)
+	}
+	got, _ = Walk(g, "x", WalkOptions[string]{Direction: DirBoth, MaxDepth: 2}, nil)
+	if want := []string{"x", "b", "c", "a"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	noSkip := func(e Edge[string]) bool { return e.Data != "skip" }
+	got, _ = Walk(g, "a", WalkOptions[string]{MaxDepth: 1, EdgeFilter: noSkip}, nil)
+	if want := []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestWalkLimits(t *testing.T) {
+	g := walkFixture()
+	for _, order := range []WalkOrder{WalkBFS, WalkDFS} {
+		opts := WalkOptions[string]{TraverseOptions: TraverseOptions{MaxNodes: 2}, Order: order}
+		got, truncated := Walk(g, "a", opts, nil)
+		if len(got) != 2 || !truncated {
+			t.Errorf("order %d: expected 2 nodes and truncation, got %v %v", order, got, truncated)
+		}
+		got, truncated = Walk(g, "a", WalkOptions[string]{Order: order}, func(n Node[string], _ int) bool {
+			return n.ID != "b"
+		})
+		if got[len(got)-1] != "b" || truncated {
+			t.Errorf("order %d: expected the visitor to stop at b, got %v", order, got)
+		}
+	}
+	if got, _ := Walk(g, "missing", WalkOptions[string]{}, nil); got != nil {
+		t.Errorf("expected nil for a missing start, got %v", got)
+	}
+}